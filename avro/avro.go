@@ -0,0 +1,241 @@
+// Package avro turns a schema into an Apache Avro record schema, so that the same contract can be registered
+// for a stream of rows as is used to validate files of them. The record has a field for each of the schema's,
+// in the schema's order.
+package avro
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"tableschema-validator/schema"
+)
+
+// Decimal is the precision and scale of a number field whose values are exact decimals, such as amounts of
+// money, rather than floating point numbers.
+type Decimal struct {
+	// Precision is how many digits a value can have.
+	Precision int
+	// Scale is how many of them are after the decimal point.
+	Scale int
+}
+
+// Options are what the record is called, and the fields whose values are of a type no type of field has.
+type Options struct {
+	// Name is the name of the record.
+	Name string
+	// Namespace is the namespace of the record. It's left out if it's "".
+	Namespace string
+	// Decimals are the number and integer fields whose values are decimals, by name.
+	Decimals map[string]Decimal
+	// Times are the string fields whose values are times of day, e.g. 09:30:00.
+	Times []string
+}
+
+// field is an Avro field of a record.
+type field struct {
+	Name string `json:"name"`
+	Doc  string `json:"doc,omitempty"`
+	Type any    `json:"type"`
+	// Default is null for a field whose value can be null, and left out for any other
+	Default json.RawMessage `json:"default,omitempty"`
+}
+
+// record is an Avro record schema.
+type record struct {
+	Type      string  `json:"type"`
+	Name      string  `json:"name"`
+	Namespace string  `json:"namespace,omitempty"`
+	Fields    []field `json:"fields"`
+}
+
+// logicalType is an Avro primitive type annotated with a logical type.
+type logicalType struct {
+	Type        string `json:"type"`
+	LogicalType string `json:"logicalType"`
+	Precision   int    `json:"precision,omitempty"`
+	Scale       int    `json:"scale,omitempty"`
+}
+
+// enum is an Avro enum type.
+type enum struct {
+	Type    string   `json:"type"`
+	Name    string   `json:"name"`
+	Symbols []string `json:"symbols"`
+}
+
+// array is an Avro array type.
+type array struct {
+	Type  string `json:"type"`
+	Items string `json:"items"`
+}
+
+// primitiveTypes are the Avro types of the values of each type of field, apart from dates, datetimes and lists.
+var primitiveTypes = map[string]string{
+	"string":  "string",
+	"number":  "double",
+	"integer": "long",
+	"boolean": "boolean",
+}
+
+// avroTypes are the names of Avro's own types, which a record or enum can't have, since a type given by name
+// would be read as the Avro type rather than it.
+var avroTypes = []string{"null", "boolean", "int", "long", "float", "double", "bytes", "string", "record", "enum", "array", "map", "fixed"}
+
+// avroName matches the names Avro allows for records, fields, enums and enum symbols.
+var avroName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Name returns name as a name Avro allows: a character that can't be part of one is replaced with _, as is
+// a leading digit given one in front of it.
+func Name(name string) string {
+	var builder strings.Builder
+	for index, character := range name {
+		switch {
+		case character == '_', 'A' <= character && character <= 'Z', 'a' <= character && character <= 'z':
+			builder.WriteRune(character)
+		case '0' <= character && character <= '9':
+			if index == 0 {
+				builder.WriteRune('_')
+			}
+			builder.WriteRune(character)
+		default:
+			builder.WriteRune('_')
+		}
+	}
+	if builder.Len() == 0 {
+		return "_"
+	}
+	return builder.String()
+}
+
+// fieldType returns the Avro type of the values of field, which has the name name in the record.
+func fieldType(field schema.FieldDescriptor, name string, options Options) (any, error) {
+	if decimal, ok := options.Decimals[field.Name]; ok {
+		if field.FieldType != "number" && field.FieldType != "integer" {
+			return nil, fmt.Errorf("%s is of type %s, so its values can't be decimals", field.Name, field.FieldType)
+		}
+		if decimal.Precision < 1 || decimal.Scale < 0 || decimal.Scale > decimal.Precision {
+			return nil, fmt.Errorf("%s has a precision of %d and a scale of %d, but the scale has to be between 0 and the precision, which has to be at least 1",
+				field.Name, decimal.Precision, decimal.Scale)
+		}
+		return logicalType{Type: "bytes", LogicalType: "decimal", Precision: decimal.Precision, Scale: decimal.Scale}, nil
+	}
+	if slices.Contains(options.Times, field.Name) {
+		if field.FieldType != "string" {
+			return nil, fmt.Errorf("%s is of type %s, so its values can't be times", field.Name, field.FieldType)
+		}
+		return logicalType{Type: "int", LogicalType: "time-millis"}, nil
+	}
+
+	switch field.FieldType {
+	case "date":
+		return logicalType{Type: "int", LogicalType: "date"}, nil
+	case "datetime":
+		return logicalType{Type: "long", LogicalType: "timestamp-millis"}, nil
+	case "list":
+		return array{Type: "array", Items: "string"}, nil
+	case "string":
+		// an enum's symbols have to be names, so a string field whose values aren't stays a string
+		if symbols, ok := field.Constraints["enum"].([]string); ok &&
+			!slices.ContainsFunc(symbols, func(symbol string) bool { return !avroName.MatchString(symbol) }) {
+			// Avro doesn't allow a symbol twice, so a value given more than once is only the first time
+			var unique []string
+			for _, symbol := range symbols {
+				if !slices.Contains(unique, symbol) {
+					unique = append(unique, symbol)
+				}
+			}
+			// an enum is named after its field, unless that's the name of an Avro type
+			if slices.Contains(avroTypes, name) {
+				name += "_enum"
+			}
+			return enum{Type: "enum", Name: name, Symbols: unique}, nil
+		}
+	}
+	return primitiveTypes[field.FieldType], nil
+}
+
+// doc returns the documentation of field: its title and description.
+func doc(field schema.FieldDescriptor) string {
+	switch {
+	case field.Title == "":
+		return field.Description
+	case field.Description == "":
+		return field.Title
+	}
+	return field.Title + ": " + field.Description
+}
+
+// Generate returns an Avro record schema, named options.Name, for a row of tableSchema. Each field is a field
+// of the record, whose name is the field's with any character Avro doesn't allow in a name replaced with _.
+// An integer field's values are longs, a number field's doubles, a boolean field's booleans and a list field's
+// arrays of strings. A date field's values are ints of the logical type date, and a datetime field's longs of
+// the logical type timestamp-millis. A string field with an enum constraint is an Avro enum, if its values
+// can be symbols, named after the field, or the field with _enum added if that's the name of an Avro type,
+// e.g. int; any other string field's values are strings.
+//
+// Number and integer fields in options.Decimals are bytes of the logical type decimal, and string fields in options.Times
+// are ints of the logical type time-millis. A field that isn't required is a union of null and its type, with
+// a default of null. A field's title and description are its doc. Avro has no constraints, so the others
+// aren't carried over.
+func Generate(tableSchema schema.Schema, options Options) ([]byte, error) {
+	if !avroName.MatchString(options.Name) {
+		return nil, fmt.Errorf("the record can't be called %q, which isn't a name Avro allows", options.Name)
+	}
+	if slices.Contains(avroTypes, options.Name) {
+		return nil, fmt.Errorf("the record can't be called %q, which is the name of an Avro type", options.Name)
+	}
+	fields := tableSchema.Fields.List()
+	if len(fields) == 0 {
+		return nil, errors.New("the schema has no fields")
+	}
+	for name := range options.Decimals {
+		if !slices.Contains(tableSchema.Fields.Names(), name) {
+			return nil, fmt.Errorf("%s is a decimal, but isn't a field", name)
+		}
+	}
+	for _, name := range options.Times {
+		if !slices.Contains(tableSchema.Fields.Names(), name) {
+			return nil, fmt.Errorf("%s is a time, but isn't a field", name)
+		}
+	}
+
+	result := record{Type: "record", Name: options.Name, Namespace: options.Namespace}
+	// fieldNames are the fields each name in the record was given to, and typeNames the record and fields each
+	// named type was: each has to be different from the others
+	fieldNames := map[string]string{}
+	typeNames := map[string]string{options.Name: "the record"}
+	for _, descriptor := range fields {
+		name := Name(descriptor.Name)
+		if other, ok := fieldNames[name]; ok {
+			return nil, fmt.Errorf("%s and %s would both be called %s", other, descriptor.Name, name)
+		}
+		fieldNames[name] = descriptor.Name
+		avroType, err := fieldType(descriptor, name, options)
+		if err != nil {
+			return nil, err
+		}
+		if enumType, ok := avroType.(enum); ok {
+			if other, ok := typeNames[enumType.Name]; ok {
+				return nil, fmt.Errorf("the enum of %s would have the same name as %s", descriptor.Name, other)
+			}
+			typeNames[enumType.Name] = descriptor.Name
+		}
+
+		avroField := field{Name: name, Doc: doc(descriptor), Type: avroType}
+		if required, _ := descriptor.Constraints["required"].(bool); !required {
+			// a union's default has to be of its first type, so null comes first
+			avroField.Type = []any{"null", avroType}
+			avroField.Default = json.RawMessage("null")
+		}
+		result.Fields = append(result.Fields, avroField)
+	}
+
+	generated, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(generated, '\n'), nil
+}
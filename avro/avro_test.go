@@ -0,0 +1,266 @@
+package avro
+
+import (
+	"encoding/json"
+	"strings"
+	"tableschema-validator/schema"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGenerate(t *testing.T) {
+	tableSchema, err := schema.New().
+		Integer("id").Title("ID").Required().Min(1).
+		String("size").Description("T-shirt size").Enum("S", "M").
+		String("code").Title("Code").Description("Three letters").Required().Enum("a-b").
+		Number("price").Required().
+		Number("score").
+		Boolean("active").
+		Date("joined").Required().
+		DateTime("updated").
+		String("opens").
+		List("tags").
+		String("2nd name").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Generate(tableSchema, Options{
+		Name:      "accounts",
+		Namespace: "com.example",
+		Decimals:  map[string]Decimal{"price": {Precision: 10, Scale: 2}},
+		Times:     []string{"opens"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{
+  "type": "record",
+  "name": "accounts",
+  "namespace": "com.example",
+  "fields": [
+    {
+      "name": "id",
+      "doc": "ID",
+      "type": "long"
+    },
+    {
+      "name": "size",
+      "doc": "T-shirt size",
+      "type": [
+        "null",
+        {
+          "type": "enum",
+          "name": "size",
+          "symbols": [
+            "S",
+            "M"
+          ]
+        }
+      ],
+      "default": null
+    },
+    {
+      "name": "code",
+      "doc": "Code: Three letters",
+      "type": "string"
+    },
+    {
+      "name": "price",
+      "type": {
+        "type": "bytes",
+        "logicalType": "decimal",
+        "precision": 10,
+        "scale": 2
+      }
+    },
+    {
+      "name": "score",
+      "type": [
+        "null",
+        "double"
+      ],
+      "default": null
+    },
+    {
+      "name": "active",
+      "type": [
+        "null",
+        "boolean"
+      ],
+      "default": null
+    },
+    {
+      "name": "joined",
+      "type": {
+        "type": "int",
+        "logicalType": "date"
+      }
+    },
+    {
+      "name": "updated",
+      "type": [
+        "null",
+        {
+          "type": "long",
+          "logicalType": "timestamp-millis"
+        }
+      ],
+      "default": null
+    },
+    {
+      "name": "opens",
+      "type": [
+        "null",
+        {
+          "type": "int",
+          "logicalType": "time-millis"
+        }
+      ],
+      "default": null
+    },
+    {
+      "name": "tags",
+      "type": [
+        "null",
+        {
+          "type": "array",
+          "items": "string"
+        }
+      ],
+      "default": null
+    },
+    {
+      "name": "_2nd_name",
+      "type": [
+        "null",
+        "string"
+      ],
+      "default": null
+    }
+  ]
+}
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+	if !json.Valid(got) {
+		t.Error("expected valid JSON")
+	}
+}
+
+func TestGenerateEnumDuplicates(t *testing.T) {
+	// the builder doesn't allow an enum value twice, but a descriptor can have one
+	tableSchema, err := schema.ParseSchema([]byte(`{"fields": [{"name": "size", "constraints": {"required": true, "enum": ["b", "a", "b", "a"]}}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated, err := Generate(tableSchema, Options{Name: "t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var record struct {
+		Fields []struct {
+			Type struct {
+				Symbols []string `json:"symbols"`
+			} `json:"type"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal(generated, &record); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"b", "a"}, record.Fields[0].Type.Symbols); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateEnumTypeNames(t *testing.T) {
+	tableSchema, err := schema.New().String("int").Enum("a").String("record").Enum("b").String("size").Enum("c").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	generated, err := Generate(tableSchema, Options{Name: "t"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var record struct {
+		Fields []struct {
+			Type []any `json:"type"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal(generated, &record); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, field := range record.Fields {
+		names = append(names, field.Type[1].(map[string]any)["name"].(string))
+	}
+	// an enum named after its field can't have the name of an Avro type
+	if diff := cmp.Diff([]string{"int_enum", "record_enum", "size"}, names); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestName(t *testing.T) {
+	testCases := map[string]string{
+		"id":         "id",
+		"first name": "first_name",
+		"2024":       "_2024",
+		"café":       "caf_",
+		"":           "_",
+	}
+	for name, want := range testCases {
+		if got := Name(name); got != want {
+			t.Errorf("%q: expected %q, got %q", name, want, got)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tableSchema, err := schema.New().
+		String("first name").Enum("a", "b").
+		String("note").
+		Integer("count").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name    string
+		options Options
+		wantErr string
+	}{
+		{name: "record name", options: Options{Name: "my table"}, wantErr: "isn't a name Avro allows"},
+		{name: "record named as type", options: Options{Name: "string"}, wantErr: `the record can't be called "string", which is the name of an Avro type`},
+		{name: "decimal string", options: Options{Name: "t", Decimals: map[string]Decimal{"note": {Precision: 5}}}, wantErr: "note is of type string, so its values can't be decimals"},
+		{name: "scale", options: Options{Name: "t", Decimals: map[string]Decimal{"count": {Precision: 2, Scale: 3}}}, wantErr: "the scale has to be between 0 and the precision"},
+		{name: "unknown decimal", options: Options{Name: "t", Decimals: map[string]Decimal{"price": {Precision: 5}}}, wantErr: "price is a decimal, but isn't a field"},
+		{name: "time integer", options: Options{Name: "t", Times: []string{"count"}}, wantErr: "count is of type integer, so its values can't be times"},
+		{name: "enum named as record", options: Options{Name: "first_name"}, wantErr: "the enum of first name would have the same name as the record"},
+	}
+
+	for _, testCase := range testCases {
+		_, err := Generate(tableSchema, testCase.options)
+		if err == nil || !strings.Contains(err.Error(), testCase.wantErr) {
+			t.Errorf("%s: expected an error containing %q, got %v", testCase.name, testCase.wantErr, err)
+		}
+	}
+
+	clashing, err := schema.New().String("first name").String("first_name").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Generate(clashing, Options{Name: "t"}); err == nil || err.Error() != "first name and first_name would both be called first_name" {
+		t.Errorf("expected an error for fields with the same Avro name, got %v", err)
+	}
+
+	if _, err := Generate(schema.MakeSchema(schema.SchemaOptions{}), Options{Name: "t"}); err == nil {
+		t.Error("expected an error for a schema without fields")
+	}
+}
@@ -0,0 +1,335 @@
+// Package codegen generates Go code from a schema: a struct with a typed field for each of the schema's fields,
+// and a function which parses a row of cells into one, checking them as the validate package would. A generated
+// parser uses neither reflection nor maps, for when rows have to be read faster than validate.ValidateTable can.
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+	"tableschema-validator/schema"
+	"unicode"
+)
+
+// Options are what the generated code is called.
+type Options struct {
+	// Package is the name of the package the code is generated for.
+	Package string
+	// TypeName is the name of the generated struct. The parser is called Parse followed by TypeName.
+	TypeName string
+	// Source is where the schema came from, e.g. its path, for the comment at the top of the generated code.
+	Source string
+}
+
+// initialisms are the words written in capitals in a Go name, as Go's style is.
+var initialisms = []string{"id", "url", "uri", "uuid", "api", "http", "json", "sql", "csv", "ip"}
+
+// goName turns the name of a schema field into an exported Go name, e.g. customer_id into CustomerID.
+func goName(name string) string {
+	words := strings.FieldsFunc(name, func(character rune) bool {
+		return !unicode.IsLetter(character) && !unicode.IsDigit(character)
+	})
+
+	var goName strings.Builder
+	for _, word := range words {
+		if slices.Contains(initialisms, strings.ToLower(word)) {
+			goName.WriteString(strings.ToUpper(word))
+			continue
+		}
+		runes := []rune(word)
+		goName.WriteString(string(unicode.ToUpper(runes[0])) + string(runes[1:]))
+	}
+
+	if goName.Len() == 0 || !unicode.IsLetter([]rune(goName.String())[0]) {
+		return "Field" + goName.String()
+	}
+	return goName.String()
+}
+
+// generatedField is a field of the schema, with what is needed to generate code for it.
+type generatedField struct {
+	schema.FieldDescriptor
+	goName   string
+	required bool
+}
+
+// goType returns the Go type of the field. A field that isn't required is a pointer, which is nil if its cell
+// is empty, unless its type's zero value already means empty.
+func (field generatedField) goType() string {
+	var goType string
+	switch field.FieldType {
+	case "string":
+		return "string"
+	case "list":
+		return "[]string"
+	case "number":
+		goType = "float64"
+	case "integer":
+		goType = "int64"
+	case "boolean":
+		goType = "bool"
+	case "date", "datetime":
+		goType = "time.Time"
+	}
+	if !field.required {
+		return "*" + goType
+	}
+	return goType
+}
+
+// escapeTagValue escapes a value in a tableschema struct tag, as schema.FromType reads them.
+func escapeTagValue(value string) string {
+	return strings.ReplaceAll(value, ",", `\,`)
+}
+
+// structTag returns the struct tag of the field, which names its schema field and gives its constraints so that
+// schema.FromType can make the schema again from the generated struct.
+func (field generatedField) structTag() string {
+	options := []string{"name=" + escapeTagValue(field.Name)}
+	if field.FieldType == "date" {
+		options = append(options, "type=date")
+	}
+	// strings and lists aren't pointers, so have to be marked optional for schema.FromType
+	if !field.required && (field.FieldType == "string" || field.FieldType == "list") {
+		options = append(options, "optional")
+	}
+	if field.Title != "" {
+		options = append(options, "title="+escapeTagValue(field.Title))
+	}
+	if field.Description != "" {
+		options = append(options, "description="+escapeTagValue(field.Description))
+	}
+	if unique, _ := field.Constraints["unique"].(bool); unique {
+		options = append(options, "unique")
+	}
+	if pattern, ok := field.Constraints["pattern"].(string); ok {
+		options = append(options, "pattern="+escapeTagValue(pattern))
+	}
+	if enum, ok := field.Constraints["enum"].([]string); ok {
+		options = append(options, "enum="+escapeTagValue(strings.Join(enum, "|")))
+	}
+	for _, key := range []string{"minLength", "maxLength", "min", "max"} {
+		if value, ok := field.Constraints[key].(int64); ok {
+			options = append(options, fmt.Sprintf("%s=%d", key, value))
+		}
+	}
+
+	tag := fmt.Sprintf(`json:%s tableschema:%s`, strconv.Quote(field.Name), strconv.Quote(strings.Join(options, ",")))
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+	return "`" + tag + "`"
+}
+
+// typeCheck returns the validate function that checks the field's type, or "" if nothing is checked.
+func (field generatedField) typeCheck() string {
+	return map[string]string{
+		"number":   "validate.EnforceNumberConstraint",
+		"boolean":  "validate.EnforceBooleanConstraint",
+		"integer":  "validate.EnforceIntegerConstraint",
+		"date":     "validate.EnforceDateConstraint",
+		"datetime": "validate.EnforceDateTimeConstraint",
+	}[field.FieldType]
+}
+
+// parseExpression returns an expression, of the form (value, error), which parses the cell into the field's
+// Go type, for the types that can fail to parse, and the packages it uses. It is only evaluated once the cell
+// has passed the type check.
+func (field generatedField) parseExpression() (string, []string) {
+	switch field.FieldType {
+	case "number":
+		return "strconv.ParseFloat(strings.TrimSpace(cell), 64)", []string{"strconv", "strings"}
+	case "integer":
+		return "strconv.ParseInt(cell, 10, 64)", []string{"strconv"}
+	case "date":
+		return "time.Parse(time.DateOnly, cell)", []string{"time"}
+	case "datetime":
+		return "parseDateTime(cell)", nil
+	}
+	return "", nil
+}
+
+// usesCheck reports whether the code for the field calls check, the function of the generated parser which
+// records a failure.
+func (field generatedField) usesCheck() bool {
+	return field.required || field.typeCheck() != ""
+}
+
+// writeField writes the code which checks and parses the cell at index into the field, adding the packages it
+// uses to imports.
+func writeField(code *bytes.Buffer, imports map[string]bool, index int, field generatedField) {
+	fmt.Fprintf(code, "\n\t// %s\n", field.Name)
+	fmt.Fprintf(code, "\tcell = cellAt(%d)\n", index)
+
+	assign := fmt.Sprintf("row.%s = value", field.goName)
+	if strings.HasPrefix(field.goType(), "*") {
+		assign = fmt.Sprintf("row.%s = &value", field.goName)
+	}
+
+	// the checks of a field are made in the same order as validate.ValidateTable makes them
+	switch typeCheck := field.typeCheck(); {
+	case field.FieldType == "string":
+		fmt.Fprintf(code, "\trow.%s = cell\n", field.goName)
+	case typeCheck == "":
+		// a list, whose items aren't checked
+		imports["strings"] = true
+		fmt.Fprintf(code, "\tif cell != \"\" {\n")
+		fmt.Fprintf(code, "\t\trow.%s = strings.Split(cell, \",\")\n", field.goName)
+		fmt.Fprintf(code, "\t}\n")
+	default:
+		// validate.ValidateTable checks the type of an empty number cell too, but not of other types
+		checksEmpty := field.FieldType == "number"
+		if !checksEmpty {
+			fmt.Fprintf(code, "\tif cell != \"\" {\n")
+		}
+		fmt.Fprintf(code, "\tif valid, err := check(%s(%q, cell)); err != nil {\n", typeCheck, field.Name)
+		fmt.Fprintf(code, "\t\treturn row, nil, err\n")
+		fmt.Fprintf(code, "\t} else if valid {\n")
+		imports["tableschema-validator/validate"] = true
+		if field.FieldType == "boolean" {
+			imports["tableschema-validator/schema"] = true
+			fmt.Fprintf(code, "\t\tvalue := schema.IsTrue(cell)\n")
+		} else {
+			expression, packages := field.parseExpression()
+			imports["fmt"] = true
+			for _, imported := range packages {
+				imports[imported] = true
+			}
+			fmt.Fprintf(code, "\t\tvalue, err := %s\n", expression)
+			fmt.Fprintf(code, "\t\tif err != nil {\n\t\t\treturn row, nil, fmt.Errorf(\"%%s: %%w\", %q, err)\n\t\t}\n", field.Name)
+		}
+		fmt.Fprintf(code, "\t\t%s\n", assign)
+		fmt.Fprintf(code, "\t}\n")
+		if !checksEmpty {
+			fmt.Fprintf(code, "\t}\n")
+		}
+	}
+
+	if field.required {
+		imports["tableschema-validator/validate"] = true
+		fmt.Fprintf(code, "\tif _, err := check(validate.EnforceRequiredConstraint(required, %q, cell)); err != nil {\n", field.Name)
+		fmt.Fprintf(code, "\t\treturn row, nil, err\n")
+		fmt.Fprintf(code, "\t}\n")
+	}
+}
+
+// Generate returns the formatted source of a Go file declaring a struct for the rows of tableSchema, called
+// options.TypeName, and a function which parses a row's cells into one. The function checks each cell against
+// the field's type and required constraint, calling the same validate functions validate.ValidateTable does,
+// and returns the failures; it doesn't check unique constraints, which depend on the rest of the table. An
+// integer field is an int64, so the function returns an error for an integer too big for one, which
+// validate.ValidateTable accepts.
+func Generate(tableSchema schema.Schema, options Options) ([]byte, error) {
+	if !token.IsIdentifier(options.Package) {
+		return nil, fmt.Errorf("%q is not a valid package name", options.Package)
+	}
+	if !token.IsIdentifier(options.TypeName) || !token.IsExported(options.TypeName) {
+		return nil, fmt.Errorf("%q is not a valid exported type name", options.TypeName)
+	}
+
+	var fields []generatedField
+	usedNames := make(map[string]bool)
+	for _, descriptor := range tableSchema.Fields.List() {
+		field := generatedField{FieldDescriptor: descriptor, goName: goName(descriptor.Name)}
+		field.required, _ = descriptor.Constraints["required"].(bool)
+		// fields whose names only differ in punctuation, e.g. a-b and a_b, are numbered to tell them apart
+		for number := 2; usedNames[field.goName]; number++ {
+			field.goName = goName(descriptor.Name) + strconv.Itoa(number)
+		}
+		usedNames[field.goName] = true
+		fields = append(fields, field)
+	}
+
+	usesDateTime := slices.ContainsFunc(fields, func(field generatedField) bool { return field.FieldType == "datetime" })
+
+	// imports are the packages the code uses, which are the only ones it can import
+	imports := map[string]bool{"tableschema-validator/validate": true}
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "// %s is a row of the schema.\n", options.TypeName)
+	fmt.Fprintf(&body, "type %s struct {\n", options.TypeName)
+	for _, field := range fields {
+		if field.Description != "" {
+			fmt.Fprintf(&body, "\t// %s\n", strings.ReplaceAll(field.Description, "\n", "\n\t// "))
+		}
+		if strings.Contains(field.goType(), "time.") {
+			imports["time"] = true
+		}
+		fmt.Fprintf(&body, "\t%s %s %s\n", field.goName, field.goType(), field.structTag())
+	}
+	fmt.Fprintf(&body, "}\n\n")
+
+	fmt.Fprintf(&body, "// %sHeader is the names of the fields of the schema, in the order Parse%s expects cells in.\n", options.TypeName, options.TypeName)
+	var names []string
+	for _, field := range fields {
+		names = append(names, strconv.Quote(field.Name))
+	}
+	fmt.Fprintf(&body, "var %sHeader = []string{%s}\n\n", options.TypeName, strings.Join(names, ", "))
+
+	fmt.Fprintf(&body, "// Parse%s parses the cells of a row, in the order of %sHeader. Each cell is checked as\n", options.TypeName, options.TypeName)
+	fmt.Fprintf(&body, "// validate.ValidateTable would check it, apart from unique constraints, and the failures are returned along\n")
+	fmt.Fprintf(&body, "// with the cells that could be parsed. A missing cell is empty.\n")
+	if slices.ContainsFunc(fields, func(field generatedField) bool { return field.FieldType == "integer" }) {
+		fmt.Fprintf(&body, "//\n// An integer too big for an int64 is an error, though validate.ValidateTable accepts it.\n")
+	}
+	fmt.Fprintf(&body, "func Parse%s(cells []string) (%s, []validate.CellValidationResult, error) {\n", options.TypeName, options.TypeName)
+	fmt.Fprintf(&body, "\tvar row %s\n", options.TypeName)
+	fmt.Fprintf(&body, "\tvar failures []validate.CellValidationResult\n")
+	// Go doesn't allow a variable that isn't used, so required and check are only declared if a field uses them
+	if slices.ContainsFunc(fields, func(field generatedField) bool { return field.required }) {
+		imports["tableschema-validator/schema"] = true
+		fmt.Fprintf(&body, "\trequired := schema.RequiredConstraint{Selected: true, Value: true}\n")
+	}
+	fmt.Fprintf(&body, "\tcellAt := func(index int) string {\n\t\tif index < len(cells) {\n\t\t\treturn cells[index]\n\t\t}\n\t\treturn \"\"\n\t}\n")
+	if slices.ContainsFunc(fields, generatedField.usesCheck) {
+		fmt.Fprintf(&body, "\t// check records result if it is a failure, and reports whether it isn't\n")
+		fmt.Fprintf(&body, "\tcheck := func(result validate.CellValidationResult, err error) (bool, error) {\n")
+		fmt.Fprintf(&body, "\t\tif err == nil && !result.IsValid() {\n\t\t\tfailures = append(failures, result)\n\t\t}\n")
+		fmt.Fprintf(&body, "\t\treturn result.IsValid(), err\n\t}\n")
+	}
+	if usesDateTime {
+		imports["time"] = true
+		fmt.Fprintf(&body, "\tparseDateTime := func(cell string) (time.Time, error) {\n")
+		fmt.Fprintf(&body, "\t\tif value, err := time.Parse(time.RFC3339Nano, cell); err == nil {\n\t\t\treturn value, nil\n\t\t}\n")
+		fmt.Fprintf(&body, "\t\treturn time.Parse(\"2006-01-02T15:04:05.999999999\", cell)\n\t}\n")
+	}
+	fmt.Fprintf(&body, "\tvar cell string\n")
+	// validate.ValidateTable checks fields a type at a time, in this order, so their failures are in that order
+	checkOrder := []string{"string", "number", "boolean", "integer", "date", "datetime", "list"}
+	for _, fieldType := range checkOrder {
+		for index, field := range fields {
+			if field.FieldType == fieldType {
+				writeField(&body, imports, index, field)
+			}
+		}
+	}
+	fmt.Fprintf(&body, "\n\treturn row, failures, nil\n}\n")
+
+	var code bytes.Buffer
+	source := ""
+	if options.Source != "" {
+		source = " from " + options.Source
+	}
+	fmt.Fprintf(&code, "// Code generated by tableschema-validator generate%s; DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&code, "package %s\n\n", options.Package)
+	fmt.Fprintf(&code, "import (\n")
+	sorted := make([]string, 0, len(imports))
+	for imported := range imports {
+		sorted = append(sorted, imported)
+	}
+	slices.Sort(sorted)
+	for _, imported := range sorted {
+		fmt.Fprintf(&code, "\t%q\n", imported)
+	}
+	fmt.Fprintf(&code, ")\n\n")
+	code.Write(body.Bytes())
+
+	formatted, err := format.Source(code.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid code: %w", err)
+	}
+	return formatted, nil
+}
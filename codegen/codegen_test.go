@@ -0,0 +1,122 @@
+package codegen
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"tableschema-validator/schema"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestGenerateExample checks that the example package's generated code is what Generate generates now, so
+// that the example's tests are of the current generator. Run go generate in the example package to update it.
+func TestGenerateExample(t *testing.T) {
+	tableSchema, err := schema.LoadSchema("internal/example/schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile("internal/example/account_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Generate(tableSchema, Options{Package: "example", TypeName: "Account", Source: "schema.json"})
+	if err != nil {
+		t.Fatalf("Failed to generate code with error %s", err.Error())
+	}
+	if diff := cmp.Diff(string(want), string(got)); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestGoName(t *testing.T) {
+	testCases := map[string]string{
+		"id":          "ID",
+		"customer_id": "CustomerID",
+		"last-login":  "LastLogin",
+		"Full Name":   "FullName",
+		"2fa":         "Field2fa",
+		"":            "Field",
+		"größe":       "Größe",
+	}
+	for name, want := range testCases {
+		if got := goName(name); got != want {
+			t.Errorf("expected %s to be named %s, got %s", name, want, got)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tableSchema := schema.MakeSchema(schema.SchemaOptions{Fields: schema.Fields{
+		StringFields: []schema.StringField{{FieldBase: schema.FieldBase{Name: "a-b"}}, {FieldBase: schema.FieldBase{Name: "a_b"}}},
+	}})
+
+	for _, options := range []Options{
+		{Package: "my-package", TypeName: "Row"},
+		{Package: "rows", TypeName: "row"},
+	} {
+		if _, err := Generate(tableSchema, options); err == nil {
+			t.Errorf("expected an error generating with %+v", options)
+		}
+	}
+
+	// fields whose Go names would be the same are told apart
+	code, err := Generate(tableSchema, Options{Package: "rows", TypeName: "Row"})
+	if err != nil {
+		t.Fatalf("Failed to generate code with error %s", err.Error())
+	}
+	for _, name := range []string{"\tAB ", "\tAB2 "} {
+		if !strings.Contains(string(code), name) {
+			t.Errorf("expected the generated code to have a field %q, got %s", name, code)
+		}
+	}
+}
+
+// TestGenerateCompiles type-checks the code generated for schemas unlike the example's, which use fewer of the
+// parser's variables and packages, since Go doesn't allow any that aren't used.
+func TestGenerateCompiles(t *testing.T) {
+	testCases := map[string]string{
+		"optional strings and lists": `{"fields": [{"name": "a"}, {"name": "b", "type": "list"}]}`,
+		"required string":            `{"fields": [{"name": "a", "constraints": {"required": true}}]}`,
+		"boolean":                    `{"fields": [{"name": "a", "type": "boolean"}]}`,
+		"datetime":                   `{"fields": [{"name": "a", "type": "datetime"}]}`,
+		"date":                       `{"fields": [{"name": "a", "type": "date", "constraints": {"required": true}}]}`,
+		"description naming packages": `{"fields": [
+  {"name": "a", "description": "see strings.Split and time.Time"},
+  {"name": "b", "type": "integer", "description": "like fmt.Sprint, not slices.Contains or schema.New"}
+]}`,
+	}
+
+	directory, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// one importer, so that the packages the code imports are only type-checked once
+	files := token.NewFileSet()
+	config := types.Config{Importer: importer.ForCompiler(files, "source", nil)}
+	for name, descriptor := range testCases {
+		tableSchema, err := schema.ParseSchema([]byte(descriptor))
+		if err != nil {
+			t.Fatal(err)
+		}
+		code, err := Generate(tableSchema, Options{Package: "rows", TypeName: "Row"})
+		if err != nil {
+			t.Fatalf("%s: failed to generate code with error %s", name, err.Error())
+		}
+
+		file, err := parser.ParseFile(files, filepath.Join(directory, "row_gen.go"), code, 0)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err.Error())
+		}
+		if _, err := config.Check("rows", files, []*ast.File{file}, nil); err != nil {
+			t.Errorf("%s: the generated code doesn't compile: %s\n%s", name, err.Error(), code)
+		}
+	}
+}
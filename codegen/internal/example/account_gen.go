@@ -0,0 +1,165 @@
+// Code generated by tableschema-validator generate from schema.json; DO NOT EDIT.
+
+package example
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"tableschema-validator/schema"
+	"tableschema-validator/validate"
+	"time"
+)
+
+// Account is a row of the schema.
+type Account struct {
+	ID int64 `json:"id" tableschema:"name=id,unique,min=1"`
+	// The account holder's name.
+	Name      string     `json:"name" tableschema:"name=name,title=Full name,description=The account holder's name.,maxLength=50"`
+	Size      string     `json:"size" tableschema:"name=size,optional,enum=S|M|L"`
+	Score     *float64   `json:"score" tableschema:"name=score"`
+	Balance   float64    `json:"balance" tableschema:"name=balance"`
+	Active    *bool      `json:"active" tableschema:"name=active"`
+	Joined    time.Time  `json:"joined" tableschema:"name=joined,type=date"`
+	LastLogin *time.Time `json:"last-login" tableschema:"name=last-login"`
+	Tags      []string   `json:"tags" tableschema:"name=tags,optional"`
+}
+
+// AccountHeader is the names of the fields of the schema, in the order ParseAccount expects cells in.
+var AccountHeader = []string{"id", "name", "size", "score", "balance", "active", "joined", "last-login", "tags"}
+
+// ParseAccount parses the cells of a row, in the order of AccountHeader. Each cell is checked as
+// validate.ValidateTable would check it, apart from unique constraints, and the failures are returned along
+// with the cells that could be parsed. A missing cell is empty.
+//
+// An integer too big for an int64 is an error, though validate.ValidateTable accepts it.
+func ParseAccount(cells []string) (Account, []validate.CellValidationResult, error) {
+	var row Account
+	var failures []validate.CellValidationResult
+	required := schema.RequiredConstraint{Selected: true, Value: true}
+	cellAt := func(index int) string {
+		if index < len(cells) {
+			return cells[index]
+		}
+		return ""
+	}
+	// check records result if it is a failure, and reports whether it isn't
+	check := func(result validate.CellValidationResult, err error) (bool, error) {
+		if err == nil && !result.IsValid() {
+			failures = append(failures, result)
+		}
+		return result.IsValid(), err
+	}
+	parseDateTime := func(cell string) (time.Time, error) {
+		if value, err := time.Parse(time.RFC3339Nano, cell); err == nil {
+			return value, nil
+		}
+		return time.Parse("2006-01-02T15:04:05.999999999", cell)
+	}
+	var cell string
+
+	// name
+	cell = cellAt(1)
+	row.Name = cell
+	if _, err := check(validate.EnforceRequiredConstraint(required, "name", cell)); err != nil {
+		return row, nil, err
+	}
+
+	// size
+	cell = cellAt(2)
+	row.Size = cell
+
+	// score
+	cell = cellAt(3)
+	if valid, err := check(validate.EnforceNumberConstraint("score", cell)); err != nil {
+		return row, nil, err
+	} else if valid {
+		value, err := strconv.ParseFloat(strings.TrimSpace(cell), 64)
+		if err != nil {
+			return row, nil, fmt.Errorf("%s: %w", "score", err)
+		}
+		row.Score = &value
+	}
+
+	// balance
+	cell = cellAt(4)
+	if valid, err := check(validate.EnforceNumberConstraint("balance", cell)); err != nil {
+		return row, nil, err
+	} else if valid {
+		value, err := strconv.ParseFloat(strings.TrimSpace(cell), 64)
+		if err != nil {
+			return row, nil, fmt.Errorf("%s: %w", "balance", err)
+		}
+		row.Balance = value
+	}
+	if _, err := check(validate.EnforceRequiredConstraint(required, "balance", cell)); err != nil {
+		return row, nil, err
+	}
+
+	// active
+	cell = cellAt(5)
+	if cell != "" {
+		if valid, err := check(validate.EnforceBooleanConstraint("active", cell)); err != nil {
+			return row, nil, err
+		} else if valid {
+			value := schema.IsTrue(cell)
+			row.Active = &value
+		}
+	}
+
+	// id
+	cell = cellAt(0)
+	if cell != "" {
+		if valid, err := check(validate.EnforceIntegerConstraint("id", cell)); err != nil {
+			return row, nil, err
+		} else if valid {
+			value, err := strconv.ParseInt(cell, 10, 64)
+			if err != nil {
+				return row, nil, fmt.Errorf("%s: %w", "id", err)
+			}
+			row.ID = value
+		}
+	}
+	if _, err := check(validate.EnforceRequiredConstraint(required, "id", cell)); err != nil {
+		return row, nil, err
+	}
+
+	// joined
+	cell = cellAt(6)
+	if cell != "" {
+		if valid, err := check(validate.EnforceDateConstraint("joined", cell)); err != nil {
+			return row, nil, err
+		} else if valid {
+			value, err := time.Parse(time.DateOnly, cell)
+			if err != nil {
+				return row, nil, fmt.Errorf("%s: %w", "joined", err)
+			}
+			row.Joined = value
+		}
+	}
+	if _, err := check(validate.EnforceRequiredConstraint(required, "joined", cell)); err != nil {
+		return row, nil, err
+	}
+
+	// last-login
+	cell = cellAt(7)
+	if cell != "" {
+		if valid, err := check(validate.EnforceDateTimeConstraint("last-login", cell)); err != nil {
+			return row, nil, err
+		} else if valid {
+			value, err := parseDateTime(cell)
+			if err != nil {
+				return row, nil, fmt.Errorf("%s: %w", "last-login", err)
+			}
+			row.LastLogin = &value
+		}
+	}
+
+	// tags
+	cell = cellAt(8)
+	if cell != "" {
+		row.Tags = strings.Split(cell, ",")
+	}
+
+	return row, failures, nil
+}
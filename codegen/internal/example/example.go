@@ -0,0 +1,5 @@
+// Package example holds code generated from schema.json, which shows what codegen generates and is checked
+// against the validate package by its tests.
+package example
+
+//go:generate go run tableschema-validator generate --schema schema.json --package example --type Account --output account_gen.go
@@ -0,0 +1,111 @@
+package example
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"tableschema-validator/schema"
+	"tableschema-validator/source"
+	"tableschema-validator/validate"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestParseAccount checks that the generated parser finds the same failures as validate.ValidateTable.
+func TestParseAccount(t *testing.T) {
+	tableSchema, err := schema.LoadSchema("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := [][]string{
+		{"1", "Ada", "S", "2.5", "10", "true", "2024-01-31", "2024-01-31T09:30:00Z", "a,b"},
+		{"2", "Grace", "", "", "-1.5E2", "", "2024-02-29", "", ""},
+		{"x", "", "XL", "high", "", "maybe", "31/01/2024", "yesterday", ""},
+		{"3"},
+	}
+
+	var data strings.Builder
+	data.WriteString(strings.Join(AccountHeader, ",") + "\n")
+	for _, row := range rows {
+		for index, cell := range row {
+			if index > 0 {
+				data.WriteString(",")
+			}
+			if strings.Contains(cell, ",") {
+				cell = `"` + cell + `"`
+			}
+			data.WriteString(cell)
+		}
+		data.WriteString("\n")
+	}
+	report, err := validate.ValidateTable(tableSchema, source.NewCSVReader(strings.NewReader(data.String()), source.DefaultDialect()), validate.ValidationOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reasons := func(failures []validate.CellValidationResult) []string {
+		var reasons []string
+		for _, failure := range failures {
+			// validate.ValidateTable adds where the cell is, which the parser can't know
+			reason, _, _ := strings.Cut(failure.Reason(), " (row ")
+			reasons = append(reasons, reason)
+		}
+		return reasons
+	}
+
+	for index, row := range rows {
+		_, failures, err := ParseAccount(row)
+		if err != nil {
+			t.Fatalf("row %d: %s", index, err.Error())
+		}
+		if diff := cmp.Diff(reasons(report.Rows[index].Failures), reasons(failures)); diff != "" {
+			t.Errorf("row %d (-validate +generated):\n%s", index, diff)
+		}
+	}
+
+	got, _, err := ParseAccount(rows[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	score, active, lastLogin := 2.5, true, time.Date(2024, 1, 31, 9, 30, 0, 0, time.UTC)
+	want := Account{
+		ID: 1, Name: "Ada", Size: "S", Score: &score, Balance: 10, Active: &active,
+		Joined: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), LastLogin: &lastLogin, Tags: []string{"a", "b"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+// TestParseAccountIntegerRange checks that an integer too big for an int64, which validate.ValidateTable accepts,
+// is an error, as the generated code says.
+func TestParseAccountIntegerRange(t *testing.T) {
+	_, _, err := ParseAccount([]string{"9223372036854775808", "Ada", "", "2.5", "10", "", "2024-01-31"})
+	if !errors.Is(err, strconv.ErrRange) || !strings.HasPrefix(err.Error(), "id: ") {
+		t.Errorf("expected an out of range error for id, got %v", err)
+	}
+
+	got, failures, err := ParseAccount([]string{"9223372036854775807", "Ada", "", "2.5", "10", "", "2024-01-31"})
+	if err != nil || len(failures) > 0 || got.ID != math.MaxInt64 {
+		t.Errorf("expected the largest int64 to be parsed, got %d, %v and %v", got.ID, failures, err)
+	}
+}
+
+// TestAccountSchema checks that the generated struct describes the schema it was generated from.
+func TestAccountSchema(t *testing.T) {
+	want, err := schema.LoadSchema("schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := schema.FromStruct[Account]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
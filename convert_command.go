@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"tableschema-validator/schema"
+	"tableschema-validator/yaml"
+)
+
+func runConvert(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("convert", stderr)
+	schemaFlags := addSchemaFlags(flags)
+	to := flags.String("to", "", `format to write to stdout: "json" or "yaml" (default the other one to the input's)`)
+	output := flags.String("output", "", "file to write the schema to, in YAML if it ends in .yaml or .yml (default stdout)")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: tableschema-validator convert [flags] schema.json|schema.yaml")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Converts a schema descriptor between json and YAML, which is told from the file extensions: .yaml or .yml")
+		fmt.Fprintln(stderr, "is YAML, and anything else json. The descriptor is checked, but converted as it's written, with its keys")
+		fmt.Fprintln(stderr, "in the same order and a composed one still composed.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+
+	paths, err := parseFlags(flags, args)
+	if err != nil {
+		return flagsExitCode(err)
+	}
+	if len(paths) != 1 {
+		fmt.Fprintln(stderr, "tableschema-validator convert: expected one schema")
+		flags.Usage()
+		return exitError
+	}
+	if *to != "" && *to != "json" && *to != "yaml" {
+		fmt.Fprintf(stderr, "tableschema-validator convert: unknown format %q, expected json or yaml\n", *to)
+		return exitError
+	}
+	if *to != "" && *output != "" {
+		fmt.Fprintln(stderr, "tableschema-validator convert: --to is for stdout; an output file's format is told from its extension")
+		return exitError
+	}
+
+	if _, err := os.Stat(paths[0]); err != nil && strings.Contains(paths[0], "#") {
+		fmt.Fprintf(stderr, "tableschema-validator convert: %s: a whole file is converted, so it can't have a fragment\n", paths[0])
+		return exitError
+	}
+	// the descriptor is read as a schema only to check it, so that what's converted is what was written
+	if _, err := schemaFlags.load(paths[0]); err != nil {
+		fmt.Fprintf(stderr, "tableschema-validator convert: %s: %s\n", paths[0], err.Error())
+		return exitError
+	}
+	data, err := os.ReadFile(paths[0])
+	if err == nil && schema.IsYAML(paths[0]) {
+		data, err = yaml.ToJSON(data)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "tableschema-validator convert: %s: %s\n", paths[0], err.Error())
+		return exitError
+	}
+
+	switch {
+	case *output != "":
+		*to = "json"
+		if schema.IsYAML(*output) {
+			*to = "yaml"
+		}
+	case *to == "":
+		*to = "yaml"
+		if schema.IsYAML(paths[0]) {
+			*to = "json"
+		}
+	}
+	var descriptor []byte
+	if *to == "yaml" {
+		descriptor, err = yaml.FromJSON(data)
+	} else {
+		var indented bytes.Buffer
+		err = json.Indent(&indented, data, "", "  ")
+		descriptor = append(indented.Bytes(), '\n')
+	}
+	if err == nil && *output != "" {
+		err = os.WriteFile(*output, descriptor, 0o644)
+	} else if err == nil {
+		_, err = stdout.Write(descriptor)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "tableschema-validator convert: %s\n", err.Error())
+		return exitError
+	}
+	return exitValid
+}
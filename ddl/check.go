@@ -0,0 +1,354 @@
+package ddl
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// lengthFunctions are the functions that count the characters of a string, in the databases statements are
+// read from.
+var lengthFunctions = []string{"length", "char_length", "character_length", "len"}
+
+// isNull reports whether condition is a test that a column is NULL, e.g. score IS NULL.
+func isNull(condition []token) bool {
+	return len(condition) == 3 && (condition[0].kind == word || condition[0].kind == quoted) && condition[1].is("IS") && condition[2].is("NULL")
+}
+
+// conditions splits the condition of a CHECK constraint into the conditions that are ANDed together in it. A
+// condition with an OR outside of parentheses isn't split, since its parts don't each have to hold, unless
+// the OR only allows a column to be NULL, e.g. score > 0 OR score IS NULL: a constraint doesn't apply to an
+// empty value anyway.
+func conditions(condition []token) [][]token {
+	var alternatives [][]token
+	depth, start := 0, 0
+	for index, current := range condition {
+		switch {
+		case current.is("("):
+			depth++
+		case current.is(")"):
+			depth--
+		case current.is("OR") && depth == 0:
+			alternatives = append(alternatives, condition[start:index])
+			start = index + 1
+		}
+	}
+	if alternatives != nil {
+		alternatives = append(alternatives, condition[start:])
+		var nullable []string
+		for _, alternative := range alternatives {
+			if isNull(alternative) {
+				nullable = append(nullable, strings.ToLower(alternative[0].text))
+			}
+		}
+		alternatives = slices.DeleteFunc(alternatives, isNull)
+		if len(alternatives) != 1 || len(nullable) == 0 {
+			return [][]token{condition}
+		}
+		// the other alternative has to be about the column that can be NULL, or it doesn't always have to hold
+		for _, part := range conditions(alternatives[0]) {
+			if comparison, ok := parseComparison(part); !ok || !slices.Contains(nullable, strings.ToLower(comparison.name)) {
+				return [][]token{condition}
+			}
+		}
+		condition = alternatives[0]
+	}
+
+	var parts [][]token
+	depth, start = 0, 0
+	inBetween := false
+	for index, current := range condition {
+		switch {
+		case current.is("("):
+			depth++
+		case current.is(")"):
+			depth--
+		case depth > 0:
+		case current.is("BETWEEN"):
+			inBetween = true
+		case current.is("AND") && inBetween:
+			// the AND of BETWEEN x AND y is part of the BETWEEN
+			inBetween = false
+		case current.is("AND"):
+			parts = append(parts, condition[start:index])
+			start = index + 1
+		}
+	}
+	parts = append(parts, condition[start:])
+
+	// a part in parentheses, e.g. (a > 1 AND a < 9), is split too
+	var split [][]token
+	for _, part := range parts {
+		if len(part) > 2 && part[0].is("(") && part[len(part)-1].is(")") && (&parser{tokens: part}).encloses() {
+			split = append(split, conditions(part[1:len(part)-1])...)
+			continue
+		}
+		split = append(split, part)
+	}
+	return split
+}
+
+// encloses reports whether the parenthesis that starts the parser's tokens closes at their end.
+func (parser *parser) encloses() bool {
+	group, err := parser.group()
+	return err == nil && len(group) == len(parser.tokens)-2
+}
+
+// subject reads what a condition is about: a column, or the length of one. It returns the column's name.
+func (parser *parser) subject() (name string, isLength bool, ok bool) {
+	first := parser.peek(0)
+	if first.kind == word && slices.ContainsFunc(lengthFunctions, first.is) && parser.peek(1).is("(") && parser.peek(3).is(")") {
+		column := parser.peek(2)
+		if column.kind == word || column.kind == quoted {
+			parser.position += 4
+			return column.text, true, true
+		}
+	}
+	if first.kind == quoted || first.kind == word && !first.is("TRUE") && !first.is("FALSE") && !first.is("NULL") {
+		parser.next()
+		return first.text, false, true
+	}
+	return "", false, false
+}
+
+// A value is a literal in a condition.
+type value struct {
+	kind tokenKind
+	// text is the value as a string, e.g. -1 or TRUE
+	text string
+}
+
+// literal reads a literal value: a string, a number, which may be signed, or TRUE or FALSE.
+func (parser *parser) literal() (value, bool) {
+	sign := ""
+	if parser.peek(0).is("-") || parser.peek(0).is("+") {
+		sign = strings.TrimPrefix(parser.next().text, "+")
+		if parser.peek(0).kind != number {
+			return value{}, false
+		}
+	}
+	token := parser.next()
+	switch {
+	case token.kind == stringLiteral || token.kind == number:
+		return value{kind: token.kind, text: sign + token.text}, true
+	case token.is("TRUE") || token.is("FALSE"):
+		return value{kind: word, text: strings.ToUpper(token.text)}, true
+	}
+	return value{}, false
+}
+
+// integer returns the value as an integer, if it's a number with no fraction that fits in one.
+func (value value) integer() (int64, bool) {
+	if value.kind != number {
+		return 0, false
+	}
+	if integer, err := strconv.ParseInt(value.text, 10, 64); err == nil {
+		return integer, true
+	}
+	float, err := strconv.ParseFloat(value.text, 64)
+	if err != nil || float != math.Trunc(float) || float < math.MinInt64 || float >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(float), true
+}
+
+// boolean returns the value of a boolean field that value is, e.g. "true" for TRUE or 1.
+func (value value) boolean() (string, bool) {
+	switch strings.ToLower(value.text) {
+	case "true", "1":
+		return "true", true
+	case "false", "0":
+		return "false", true
+	}
+	return "", false
+}
+
+// flipped is the comparison operator that means the same with its operands swapped, e.g. > for <.
+var flipped = map[string]string{">=": "<=", ">": "<", "<=": ">=", "<": ">", "=": "=", "<>": "<>", "!=": "!="}
+
+// comparison is a condition that compares a column, or its length, to values.
+type comparison struct {
+	name     string
+	isLength bool
+	// operator is a comparison operator, IN, BETWEEN or IS NOT NULL
+	operator string
+	values   []value
+}
+
+// parseComparison reads condition as a comparison, if it is one.
+func parseComparison(condition []token) (comparison, bool) {
+	conditionParser := &parser{tokens: condition}
+	var result comparison
+	var ok bool
+
+	if literal, isLiteral := conditionParser.literal(); isLiteral && flipped[conditionParser.peek(0).text] != "" {
+		// e.g. 0 <= score
+		result.operator = flipped[conditionParser.next().text]
+		result.values = []value{literal}
+		if result.name, result.isLength, ok = conditionParser.subject(); !ok {
+			return comparison{}, false
+		}
+		return result, conditionParser.done()
+	}
+	conditionParser.position = 0
+
+	if result.name, result.isLength, ok = conditionParser.subject(); !ok {
+		return comparison{}, false
+	}
+	switch {
+	case conditionParser.accept("IS", "NOT", "NULL"):
+		result.operator = "IS NOT NULL"
+	case conditionParser.accept("IN"):
+		group, err := conditionParser.group()
+		if err != nil {
+			return comparison{}, false
+		}
+		result.operator = "IN"
+		for _, item := range split(group) {
+			itemParser := &parser{tokens: item}
+			literal, isLiteral := itemParser.literal()
+			if !isLiteral || !itemParser.done() {
+				return comparison{}, false
+			}
+			result.values = append(result.values, literal)
+		}
+	case conditionParser.accept("BETWEEN"):
+		low, isLiteral := conditionParser.literal()
+		if !isLiteral || !conditionParser.accept("AND") {
+			return comparison{}, false
+		}
+		high, isLiteral := conditionParser.literal()
+		if !isLiteral {
+			return comparison{}, false
+		}
+		result.operator = "BETWEEN"
+		result.values = []value{low, high}
+	case flipped[conditionParser.peek(0).text] != "" && conditionParser.peek(0).kind == punctuation:
+		result.operator = conditionParser.next().text
+		literal, isLiteral := conditionParser.literal()
+		if !isLiteral {
+			return comparison{}, false
+		}
+		result.values = []value{literal}
+	default:
+		return comparison{}, false
+	}
+	return result, conditionParser.done()
+}
+
+// bounds returns the lowest and highest values a comparison with whole numbers allows, as lower and upper
+// bounds, each nil if there isn't one. integral is whether the values compared are integers.
+func (comparison comparison) bounds(integral bool) (lower *int64, upper *int64, ok bool) {
+	var integers []int64
+	for _, value := range comparison.values {
+		integer, ok := value.integer()
+		if !ok {
+			return nil, nil, false
+		}
+		integers = append(integers, integer)
+	}
+	switch comparison.operator {
+	case ">=":
+		return &integers[0], nil, true
+	case "<=":
+		return nil, &integers[0], true
+	case "=":
+		return &integers[0], &integers[0], true
+	case "BETWEEN":
+		return &integers[0], &integers[1], true
+	case ">", "<":
+		// a bound of a number that isn't an integer, e.g. > 0, allows values between integers, e.g. 0.5
+		if !integral {
+			return nil, nil, false
+		}
+		if comparison.operator == ">" {
+			bound := integers[0] + 1
+			return &bound, nil, true
+		}
+		bound := integers[0] - 1
+		return nil, &bound, true
+	}
+	return nil, nil, false
+}
+
+// check translates the condition of a CHECK constraint, on the column called columnName or of the table if
+// columnName is "", into constraints, noting each part of it that can't be translated.
+func (reader *tableReader) check(condition []token, columnName string) {
+	for _, part := range conditions(condition) {
+		if !reader.translate(part) {
+			reader.note(columnName, append(append([]token{{kind: word, text: "CHECK"}, {kind: punctuation, text: "("}}, part...), token{kind: punctuation, text: ")"}),
+				"this condition can't be expressed with the constraints of a schema")
+		}
+	}
+}
+
+// translate sets the constraints that condition is equivalent to, reporting whether it is equivalent to any.
+func (reader *tableReader) translate(condition []token) bool {
+	comparison, ok := parseComparison(condition)
+	if !ok {
+		return false
+	}
+	column := reader.column(comparison.name)
+	if column == nil {
+		return false
+	}
+
+	if comparison.isLength {
+		if column.fieldType != "string" {
+			return false
+		}
+		lower, upper, ok := comparison.bounds(true)
+		if !ok || lower != nil && *lower < 0 {
+			return false
+		}
+		if lower != nil {
+			tighten(&column.minLength, *lower, true)
+		}
+		if upper != nil {
+			tighten(&column.maxLength, *upper, false)
+		}
+		return true
+	}
+
+	switch {
+	case comparison.operator == "IS NOT NULL":
+		column.required = true
+		return true
+	case (comparison.operator == "<>" || comparison.operator == "!=") && column.fieldType == "string" && comparison.values[0].kind == stringLiteral && comparison.values[0].text == "":
+		tighten(&column.minLength, 1, true)
+		return true
+	case (comparison.operator == "IN" || comparison.operator == "=") && (column.fieldType == "string" || column.fieldType == "boolean"):
+		var enum []string
+		for _, value := range comparison.values {
+			text := value.text
+			if column.fieldType == "boolean" {
+				if text, ok = value.boolean(); !ok {
+					return false
+				}
+			}
+			if !slices.Contains(enum, text) {
+				enum = append(enum, text)
+			}
+		}
+		if column.enum != nil {
+			// both sets of values have to be allowed
+			enum = slices.DeleteFunc(enum, func(text string) bool { return !slices.Contains(column.enum, text) })
+		}
+		column.enum = enum
+		return len(enum) > 0
+	case column.fieldType == "integer" || column.fieldType == "number":
+		lower, upper, ok := comparison.bounds(column.fieldType == "integer")
+		if !ok {
+			return false
+		}
+		if lower != nil {
+			tighten(&column.minimum, *lower, true)
+		}
+		if upper != nil {
+			tighten(&column.maximum, *upper, false)
+		}
+		return true
+	}
+	return false
+}
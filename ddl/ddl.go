@@ -0,0 +1,300 @@
+// Package ddl turns a schema into the SQL statement that creates a table for its rows, so that data which has
+// been validated can be loaded into a database whose table is kept in step with the schema rather than written
+// out by hand. The table has a column for each field, in the schema's order, and as many of the schema's
+// constraints as the database can check.
+//
+// It also reads schemas back from CREATE TABLE statements, for tables that were made without one.
+package ddl
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"tableschema-validator/schema"
+)
+
+// A Dialect is the database a statement is written for.
+type Dialect string
+
+const (
+	PostgreSQL Dialect = "postgresql"
+	SQLite     Dialect = "sqlite"
+	MySQL      Dialect = "mysql"
+)
+
+// Dialects are the databases statements can be written for.
+var Dialects = []Dialect{PostgreSQL, SQLite, MySQL}
+
+// Options are what the table is called and which database it is for.
+type Options struct {
+	Dialect Dialect
+	// Table is the name of the table.
+	Table string
+}
+
+// dialect is how a database writes what a statement needs.
+type dialect struct {
+	// quote is the character identifiers are quoted with.
+	quote string
+	// types are the column types for each type of field.
+	types map[string]string
+	// keyString is the column type for a string field that is part of a key, for a database that can't index
+	// the type in types, or "" if it can.
+	keyString string
+	// maxVarchar is the longest maxLength of a string column that is given a VARCHAR of that length, for a
+	// database with a keyString; a longer one has the type in types and a CHECK of its length. maxKeyVarchar is
+	// the longest that a string column in a key can have, since its values have to be indexed.
+	maxVarchar, maxKeyVarchar int64
+	// length is the function that counts the characters of a string.
+	length string
+	// escapesBackslashes is whether a backslash in a string literal escapes the character after it.
+	escapesBackslashes bool
+}
+
+var dialects = map[Dialect]dialect{
+	PostgreSQL: {
+		quote: `"`,
+		types: map[string]string{
+			"string":   "TEXT",
+			"number":   "DOUBLE PRECISION",
+			"integer":  "BIGINT",
+			"boolean":  "BOOLEAN",
+			"date":     "DATE",
+			"datetime": "TIMESTAMP WITH TIME ZONE",
+			"list":     "TEXT",
+		},
+		length: "char_length",
+	},
+	// SQLite has no date types; dates and datetimes are kept as ISO 8601 text, which sorts in time order.
+	SQLite: {
+		quote: `"`,
+		types: map[string]string{
+			"string":   "TEXT",
+			"number":   "REAL",
+			"integer":  "INTEGER",
+			"boolean":  "BOOLEAN",
+			"date":     "TEXT",
+			"datetime": "TEXT",
+			"list":     "TEXT",
+		},
+		length: "length",
+	},
+	MySQL: {
+		quote: "`",
+		types: map[string]string{
+			"string":   "TEXT",
+			"number":   "DOUBLE",
+			"integer":  "BIGINT",
+			"boolean":  "BOOLEAN",
+			"date":     "DATE",
+			"datetime": "DATETIME",
+			"list":     "TEXT",
+		},
+		// MySQL can't index a TEXT column without a prefix length, so one in a key is given a length instead.
+		// A VARCHAR is kept in its row, which can be at most 65,535 bytes, at up to 4 bytes a character, so a
+		// long one is a TEXT, which isn't, unless it's in a key. An index can be at most 3072 bytes.
+		keyString:          "VARCHAR(255)",
+		maxVarchar:         255,
+		maxKeyVarchar:      768,
+		length:             "CHAR_LENGTH",
+		escapesBackslashes: true,
+	},
+}
+
+func (dialect dialect) identifier(name string) string {
+	return dialect.quote + strings.ReplaceAll(name, dialect.quote, dialect.quote+dialect.quote) + dialect.quote
+}
+
+func (dialect dialect) identifiers(names []string) string {
+	var quoted []string
+	for _, name := range names {
+		quoted = append(quoted, dialect.identifier(name))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func (dialect dialect) literal(value string) string {
+	if dialect.escapesBackslashes {
+		value = strings.ReplaceAll(value, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// columnType returns the type of the column for field, which is in a key if inKey is true. It's an error for
+// a column in a key to be too long for the database to index.
+func (dialect dialect) columnType(field schema.FieldDescriptor, inKey bool) (string, error) {
+	if field.FieldType == "string" && dialect.keyString != "" {
+		maxLength, ok := field.Constraints["maxLength"].(int64)
+		switch {
+		case ok && inKey && maxLength > dialect.maxKeyVarchar:
+			return "", fmt.Errorf("%s is in a key, but has a maxLength of %d, which is more than the %d characters a key's column can have", field.Name, maxLength, dialect.maxKeyVarchar)
+		case ok && (inKey || maxLength <= dialect.maxVarchar):
+			// the length of a VARCHAR is its maxLength, which it checks, so no CHECK is needed for it
+			return fmt.Sprintf("VARCHAR(%d)", maxLength), nil
+		case inKey:
+			return dialect.keyString, nil
+		}
+	}
+	return dialect.types[field.FieldType], nil
+}
+
+// booleanLiteral returns the SQL value of value, a value of a boolean field, or "" if a boolean field doesn't
+// accept it.
+func booleanLiteral(value string) string {
+	switch {
+	case schema.IsTrue(value):
+		return "TRUE"
+	case schema.IsFalse(value):
+		return "FALSE"
+	}
+	return ""
+}
+
+// checks returns the conditions a CHECK constraint on the column for field has to test. A NULL passes a
+// CHECK, so they don't stop an optional column being left empty. It's an error for an enum to have no value
+// that the column could hold.
+func (dialect dialect) checks(field schema.FieldDescriptor, columnType string) ([]string, error) {
+	column := dialect.identifier(field.Name)
+	var conditions []string
+
+	if enum, ok := field.Constraints["enum"].([]string); ok {
+		var values []string
+		for _, value := range enum {
+			literal := dialect.literal(value)
+			if field.FieldType == "boolean" {
+				literal = booleanLiteral(value)
+			}
+			if literal != "" && !slices.Contains(values, literal) {
+				values = append(values, literal)
+			}
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("%s has an enum with no value that is a valid %s, so no value could be", field.Name, field.FieldType)
+		}
+		// a boolean field that can be either value isn't constrained at all
+		if field.FieldType != "boolean" || len(values) == 1 {
+			conditions = append(conditions, fmt.Sprintf("%s IN (%s)", column, strings.Join(values, ", ")))
+		}
+	}
+
+	if field.FieldType == "string" {
+		if minLength, ok := field.Constraints["minLength"].(int64); ok && minLength > 0 {
+			conditions = append(conditions, fmt.Sprintf("%s(%s) >= %d", dialect.length, column, minLength))
+		}
+		if maxLength, ok := field.Constraints["maxLength"].(int64); ok && !strings.HasPrefix(columnType, "VARCHAR") {
+			conditions = append(conditions, fmt.Sprintf("%s(%s) <= %d", dialect.length, column, maxLength))
+		}
+	}
+
+	if minimum, ok := field.Constraints["min"].(int64); ok {
+		conditions = append(conditions, fmt.Sprintf("%s >= %s", column, strconv.FormatInt(minimum, 10)))
+	}
+	if maximum, ok := field.Constraints["max"].(int64); ok {
+		conditions = append(conditions, fmt.Sprintf("%s <= %s", column, strconv.FormatInt(maximum, 10)))
+	}
+
+	return conditions, nil
+}
+
+// CreateTable returns the CREATE TABLE statement, in options.Dialect, for a table holding the rows of
+// tableSchema. Each field is a column of the type closest to the field's, which is NOT NULL if the field
+// is required and UNIQUE if it's unique. Enum, min, max, minLength and maxLength constraints are CHECK
+// constraints. The primary key, unique keys and foreign keys are constraints of the table; a foreign key
+// refers to the table named by its resource, or to the table itself if it has none.
+//
+// A pattern constraint isn't checked, since each database has its own kind of regular expression, nor are a
+// list field's constraints. An empty cell should be loaded as a NULL for the constraints to treat it as one.
+// It's an error for an enum to have no value its column could hold, or for a column in a key to be longer
+// than the database can index.
+func CreateTable(tableSchema schema.Schema, options Options) (string, error) {
+	dialect, ok := dialects[options.Dialect]
+	if !ok {
+		return "", fmt.Errorf("unknown dialect %q, expected one of postgresql, sqlite or mysql", options.Dialect)
+	}
+	if options.Table == "" {
+		return "", errors.New("the table has no name")
+	}
+	fields := tableSchema.Fields.List()
+	if len(fields) == 0 {
+		return "", errors.New("the schema has no fields, so the table would have no columns")
+	}
+
+	// checkNames reports whether each of names is the name of a field
+	checkNames := func(names []string, key string) error {
+		for _, name := range names {
+			if !slices.ContainsFunc(fields, func(field schema.FieldDescriptor) bool { return field.Name == name }) {
+				return fmt.Errorf("the %s names %s, which is not a field", key, name)
+			}
+		}
+		return nil
+	}
+	keyFields := slices.Clone(tableSchema.PrimaryKey)
+	if err := checkNames(tableSchema.PrimaryKey, "primary key"); err != nil {
+		return "", err
+	}
+	for index, key := range tableSchema.UniqueKeys {
+		if err := checkNames(key, fmt.Sprintf("unique key %d", index)); err != nil {
+			return "", err
+		}
+		keyFields = append(keyFields, key...)
+	}
+	for index, key := range tableSchema.ForeignKeys {
+		if err := checkNames(key.Fields, fmt.Sprintf("foreign key %d", index)); err != nil {
+			return "", err
+		}
+		if len(key.Fields) != len(key.Reference.Fields) {
+			return "", fmt.Errorf("foreign key %d has %d fields, but refers to %d", index, len(key.Fields), len(key.Reference.Fields))
+		}
+		keyFields = append(keyFields, key.Fields...)
+		if key.Reference.Resource == "" {
+			if err := checkNames(key.Reference.Fields, fmt.Sprintf("reference of foreign key %d", index)); err != nil {
+				return "", err
+			}
+			keyFields = append(keyFields, key.Reference.Fields...)
+		}
+	}
+
+	var definitions []string
+	for _, field := range fields {
+		unique, _ := field.Constraints["unique"].(bool)
+		columnType, err := dialect.columnType(field, unique || slices.Contains(keyFields, field.Name))
+		if err != nil {
+			return "", err
+		}
+
+		definition := dialect.identifier(field.Name) + " " + columnType
+		if required, _ := field.Constraints["required"].(bool); required {
+			definition += " NOT NULL"
+		}
+		if unique {
+			definition += " UNIQUE"
+		}
+		conditions, err := dialect.checks(field, columnType)
+		if err != nil {
+			return "", err
+		}
+		if len(conditions) > 0 {
+			definition += " CHECK (" + strings.Join(conditions, " AND ") + ")"
+		}
+		definitions = append(definitions, definition)
+	}
+
+	if len(tableSchema.PrimaryKey) > 0 {
+		definitions = append(definitions, fmt.Sprintf("PRIMARY KEY (%s)", dialect.identifiers(tableSchema.PrimaryKey)))
+	}
+	for _, key := range tableSchema.UniqueKeys {
+		definitions = append(definitions, fmt.Sprintf("UNIQUE (%s)", dialect.identifiers(key)))
+	}
+	for _, key := range tableSchema.ForeignKeys {
+		table := key.Reference.Resource
+		if table == "" {
+			table = options.Table
+		}
+		definitions = append(definitions, fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)",
+			dialect.identifiers(key.Fields), dialect.identifier(table), dialect.identifiers(key.Reference.Fields)))
+	}
+
+	return fmt.Sprintf("CREATE TABLE %s (\n  %s\n);\n", dialect.identifier(options.Table), strings.Join(definitions, ",\n  ")), nil
+}
@@ -0,0 +1,197 @@
+package ddl
+
+import (
+	"strings"
+	"tableschema-validator/schema"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const descriptor = `{
+  "fields": [
+    {"name": "id", "type": "integer", "constraints": {"required": true, "minimum": 1}},
+    {"name": "email", "type": "string", "constraints": {"required": true, "unique": true, "maxLength": 100}},
+    {"name": "size", "type": "string", "constraints": {"enum": ["S", "M", "it's"], "minLength": 1}},
+    {"name": "score", "type": "number", "constraints": {"minimum": 0, "maximum": 10}},
+    {"name": "active", "type": "boolean", "constraints": {"enum": ["true", "TRUE"]}},
+    {"name": "joined", "type": "date"},
+    {"name": "updated", "type": "datetime"},
+    {"name": "tags", "type": "list"},
+    {"name": "team", "type": "string"},
+    {"name": "manager", "type": "integer"}
+  ],
+  "primaryKey": "id",
+  "uniqueKeys": [["team", "email"]],
+  "foreignKeys": [
+    {"fields": "team", "reference": {"resource": "teams", "fields": "name"}},
+    {"fields": "manager", "reference": {"fields": "id"}}
+  ]
+}`
+
+func TestCreateTable(t *testing.T) {
+	tableSchema, err := schema.ParseSchema([]byte(descriptor))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		dialect Dialect
+		want    string
+	}{
+		{
+			dialect: PostgreSQL,
+			want: `CREATE TABLE "people" (
+  "id" BIGINT NOT NULL CHECK ("id" >= 1),
+  "email" TEXT NOT NULL UNIQUE CHECK (char_length("email") <= 100),
+  "size" TEXT CHECK ("size" IN ('S', 'M', 'it''s') AND char_length("size") >= 1),
+  "score" DOUBLE PRECISION CHECK ("score" >= 0 AND "score" <= 10),
+  "active" BOOLEAN CHECK ("active" IN (TRUE)),
+  "joined" DATE,
+  "updated" TIMESTAMP WITH TIME ZONE,
+  "tags" TEXT,
+  "team" TEXT,
+  "manager" BIGINT,
+  PRIMARY KEY ("id"),
+  UNIQUE ("team", "email"),
+  FOREIGN KEY ("team") REFERENCES "teams" ("name"),
+  FOREIGN KEY ("manager") REFERENCES "people" ("id")
+);
+`,
+		},
+		{
+			dialect: SQLite,
+			want: `CREATE TABLE "people" (
+  "id" INTEGER NOT NULL CHECK ("id" >= 1),
+  "email" TEXT NOT NULL UNIQUE CHECK (length("email") <= 100),
+  "size" TEXT CHECK ("size" IN ('S', 'M', 'it''s') AND length("size") >= 1),
+  "score" REAL CHECK ("score" >= 0 AND "score" <= 10),
+  "active" BOOLEAN CHECK ("active" IN (TRUE)),
+  "joined" TEXT,
+  "updated" TEXT,
+  "tags" TEXT,
+  "team" TEXT,
+  "manager" INTEGER,
+  PRIMARY KEY ("id"),
+  UNIQUE ("team", "email"),
+  FOREIGN KEY ("team") REFERENCES "teams" ("name"),
+  FOREIGN KEY ("manager") REFERENCES "people" ("id")
+);
+`,
+		},
+		{
+			dialect: MySQL,
+			want: "CREATE TABLE `people` (\n" +
+				"  `id` BIGINT NOT NULL CHECK (`id` >= 1),\n" +
+				"  `email` VARCHAR(100) NOT NULL UNIQUE,\n" +
+				"  `size` TEXT CHECK (`size` IN ('S', 'M', 'it''s') AND CHAR_LENGTH(`size`) >= 1),\n" +
+				"  `score` DOUBLE CHECK (`score` >= 0 AND `score` <= 10),\n" +
+				"  `active` BOOLEAN CHECK (`active` IN (TRUE)),\n" +
+				"  `joined` DATE,\n" +
+				"  `updated` DATETIME,\n" +
+				"  `tags` TEXT,\n" +
+				"  `team` VARCHAR(255),\n" +
+				"  `manager` BIGINT,\n" +
+				"  PRIMARY KEY (`id`),\n" +
+				"  UNIQUE (`team`, `email`),\n" +
+				"  FOREIGN KEY (`team`) REFERENCES `teams` (`name`),\n" +
+				"  FOREIGN KEY (`manager`) REFERENCES `people` (`id`)\n" +
+				");\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		got, err := CreateTable(tableSchema, Options{Dialect: testCase.dialect, Table: "people"})
+		if err != nil {
+			t.Fatalf("%s: %s", testCase.dialect, err.Error())
+		}
+		if diff := cmp.Diff(testCase.want, got); diff != "" {
+			t.Errorf("%s: (-want +got):\n%s", testCase.dialect, diff)
+		}
+	}
+}
+
+func TestCreateTableQuoting(t *testing.T) {
+	tableSchema := schema.MakeSchema(schema.SchemaOptions{Fields: schema.Fields{
+		StringFields: []schema.StringField{{
+			FieldBase:   schema.FieldBase{Name: `say "hi"`},
+			Constraints: schema.StringConstraints{Enum: schema.EnumConstraint{Selected: true, Value: []string{`a\b`}}},
+		}},
+	}})
+
+	got, err := CreateTable(tableSchema, Options{Dialect: MySQL, Table: "odd`name"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "CREATE TABLE `odd``name` (\n  `say \"hi\"` TEXT CHECK (`say \"hi\"` IN ('a\\\\b'))\n);\n"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestCreateTableLongStrings(t *testing.T) {
+	tableSchema, err := schema.New().
+		String("code").MaxLength(300).
+		String("name").MaxLength(300).
+		String("note").MaxLength(20000).
+		PrimaryKey("code").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a long VARCHAR could make a MySQL row too big, so only one in a key, which has to be indexed, is kept
+	got, err := CreateTable(tableSchema, Options{Dialect: MySQL, Table: "notes"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "CREATE TABLE `notes` (\n" +
+		"  `code` VARCHAR(300),\n" +
+		"  `name` TEXT CHECK (CHAR_LENGTH(`name`) <= 300),\n" +
+		"  `note` TEXT CHECK (CHAR_LENGTH(`note`) <= 20000),\n" +
+		"  PRIMARY KEY (`code`)\n" +
+		");\n"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestCreateTableErrors(t *testing.T) {
+	tableSchema, err := schema.ParseSchema([]byte(descriptor))
+	if err != nil {
+		t.Fatal(err)
+	}
+	missingKey := tableSchema
+	missingKey.PrimaryKey = schema.FieldNames{"key"}
+	mismatched := tableSchema
+	mismatched.ForeignKeys = []schema.ForeignKey{{Fields: schema.FieldNames{"team"}, Reference: schema.ForeignKeyReference{Resource: "teams", Fields: schema.FieldNames{"a", "b"}}}}
+	noBoolean, err := schema.New().Boolean("active").Enum("yes").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	longKey, err := schema.New().String("code").MaxLength(1000).Unique().Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		schema  schema.Schema
+		options Options
+		wantErr string
+	}{
+		{schema: tableSchema, options: Options{Dialect: "oracle", Table: "people"}, wantErr: `unknown dialect "oracle"`},
+		{schema: tableSchema, options: Options{Dialect: SQLite}, wantErr: "the table has no name"},
+		{schema: schema.MakeSchema(schema.SchemaOptions{}), options: Options{Dialect: SQLite, Table: "people"}, wantErr: "the schema has no fields"},
+		{schema: missingKey, options: Options{Dialect: SQLite, Table: "people"}, wantErr: "the primary key names key, which is not a field"},
+		{schema: mismatched, options: Options{Dialect: SQLite, Table: "people"}, wantErr: "foreign key 0 has 1 fields, but refers to 2"},
+		{schema: noBoolean, options: Options{Dialect: PostgreSQL, Table: "people"}, wantErr: "active has an enum with no value that is a valid boolean"},
+		{schema: longKey, options: Options{Dialect: MySQL, Table: "people"}, wantErr: "code is in a key, but has a maxLength of 1000, which is more than the 768 characters"},
+	}
+
+	for _, testCase := range testCases {
+		_, err := CreateTable(testCase.schema, testCase.options)
+		if err == nil || !strings.Contains(err.Error(), testCase.wantErr) {
+			t.Errorf("expected an error containing %q, got %v", testCase.wantErr, err)
+		}
+	}
+}
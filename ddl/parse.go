@@ -0,0 +1,848 @@
+package ddl
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"tableschema-validator/schema"
+	"unicode"
+)
+
+// A Table is a table read from a CREATE TABLE statement.
+type Table struct {
+	Name   string
+	Schema schema.Schema
+	// Untranslated is what the statement says that the schema doesn't, e.g. a DEFAULT.
+	Untranslated []Untranslated
+}
+
+// An Untranslated is part of a CREATE TABLE statement that couldn't be translated into the schema.
+type Untranslated struct {
+	// Column is the column the SQL is part of the definition of, or "" for a constraint of the table.
+	Column string `json:"column,omitempty"`
+	SQL    string `json:"sql"`
+	Reason string `json:"reason"`
+}
+
+// tokenKind is the kind of a token of SQL.
+type tokenKind int
+
+const (
+	// word is a keyword or an identifier which isn't quoted
+	word tokenKind = iota
+	// quoted is an identifier in quotes, e.g. "name", `name` or [name], which is never a keyword
+	quoted
+	stringLiteral
+	number
+	punctuation
+)
+
+type token struct {
+	kind tokenKind
+	// text is the token as it is in the statement, apart from a quoted identifier's or a string's quotes
+	text string
+}
+
+// is reports whether the token is the keyword, or the punctuation, keyword.
+func (token token) is(keyword string) bool {
+	return (token.kind == word || token.kind == punctuation) && strings.EqualFold(token.text, keyword)
+}
+
+// sql writes the token as it would be in a statement.
+func (token token) sql() string {
+	switch token.kind {
+	case quoted:
+		return `"` + strings.ReplaceAll(token.text, `"`, `""`) + `"`
+	case stringLiteral:
+		return "'" + strings.ReplaceAll(token.text, "'", "''") + "'"
+	}
+	return token.text
+}
+
+// render writes tokens back out as SQL, for reporting what couldn't be translated.
+func render(tokens []token) string {
+	var sql strings.Builder
+	for index, token := range tokens {
+		if index > 0 && spaced(tokens[index-1], token) {
+			sql.WriteString(" ")
+		}
+		sql.WriteString(token.sql())
+	}
+	return sql.String()
+}
+
+// spaced reports whether there's a space between the tokens previous and next when they're written out.
+func spaced(previous token, next token) bool {
+	switch {
+	case previous.is("(") || previous.is(".") || previous.is("::"):
+		return false
+	case next.is(")") || next.is(",") || next.is(".") || next.is("::"):
+		return false
+	case next.is("("):
+		// a function's or a type's arguments follow its name, but a list follows a keyword after a space
+		return previous.kind != word || slices.ContainsFunc([]string{"IN", "CHECK", "KEY", "UNIQUE", "AND", "OR", "NOT", "AS"}, previous.is)
+	}
+	return true
+}
+
+// tokenize splits sql into tokens, leaving out whitespace and comments.
+func tokenize(sql string) ([]token, error) {
+	var tokens []token
+	runes := []rune(sql)
+	for position := 0; position < len(runes); {
+		character := runes[position]
+		switch {
+		case unicode.IsSpace(character):
+			position++
+		case character == '-' && position+1 < len(runes) && runes[position+1] == '-', character == '#':
+			for position < len(runes) && runes[position] != '\n' {
+				position++
+			}
+		case character == '/' && position+1 < len(runes) && runes[position+1] == '*':
+			end := strings.Index(string(runes[position+2:]), "*/")
+			if end < 0 {
+				return nil, errors.New("a comment is never closed")
+			}
+			position += 2 + len([]rune(string(runes[position+2:])[:end])) + 2
+		// a [ starts a quoted identifier in SQLite, unless it's part of an array type, e.g. text[] or text[3]
+		case character == '\'' || character == '"' || character == '`' || character == '[' && position+1 < len(runes) && runes[position+1] != ']' && !unicode.IsDigit(runes[position+1]):
+			closing := map[rune]rune{'\'': '\'', '"': '"', '`': '`', '[': ']'}[character]
+			var text strings.Builder
+			position++
+			for {
+				if position >= len(runes) {
+					return nil, fmt.Errorf("a %c is never closed", character)
+				}
+				if runes[position] == closing {
+					// a quote is escaped by doubling it
+					if closing != ']' && position+1 < len(runes) && runes[position+1] == closing {
+						text.WriteRune(closing)
+						position += 2
+						continue
+					}
+					position++
+					break
+				}
+				text.WriteRune(runes[position])
+				position++
+			}
+			kind := quoted
+			if character == '\'' {
+				kind = stringLiteral
+			}
+			tokens = append(tokens, token{kind: kind, text: text.String()})
+		case unicode.IsDigit(character) || character == '.' && position+1 < len(runes) && unicode.IsDigit(runes[position+1]):
+			start := position
+			for position < len(runes) && (unicode.IsDigit(runes[position]) || runes[position] == '.') {
+				position++
+			}
+			if position < len(runes) && (runes[position] == 'e' || runes[position] == 'E') {
+				position++
+				if position < len(runes) && (runes[position] == '+' || runes[position] == '-') {
+					position++
+				}
+				for position < len(runes) && unicode.IsDigit(runes[position]) {
+					position++
+				}
+			}
+			tokens = append(tokens, token{kind: number, text: string(runes[start:position])})
+		case unicode.IsLetter(character) || character == '_':
+			start := position
+			for position < len(runes) && (unicode.IsLetter(runes[position]) || unicode.IsDigit(runes[position]) || runes[position] == '_' || runes[position] == '$') {
+				position++
+			}
+			tokens = append(tokens, token{kind: word, text: string(runes[start:position])})
+		default:
+			text := string(character)
+			if position+1 < len(runes) && slices.Contains([]string{">=", "<=", "<>", "!=", "::", "||"}, string(runes[position:position+2])) {
+				text = string(runes[position : position+2])
+			}
+			tokens = append(tokens, token{kind: punctuation, text: text})
+			position += len([]rune(text))
+		}
+	}
+	return tokens, nil
+}
+
+// A parser reads the tokens of one statement.
+type parser struct {
+	tokens   []token
+	position int
+}
+
+func (parser *parser) done() bool {
+	return parser.position >= len(parser.tokens)
+}
+
+// peek returns the token at offset from the next one, or a token matching nothing if there isn't one.
+func (parser *parser) peek(offset int) token {
+	if parser.position+offset >= len(parser.tokens) {
+		return token{kind: punctuation}
+	}
+	return parser.tokens[parser.position+offset]
+}
+
+func (parser *parser) next() token {
+	token := parser.peek(0)
+	parser.position++
+	return token
+}
+
+// accept moves past the keywords if they're next, reporting whether they were.
+func (parser *parser) accept(keywords ...string) bool {
+	for offset, keyword := range keywords {
+		if !parser.peek(offset).is(keyword) {
+			return false
+		}
+	}
+	parser.position += len(keywords)
+	return true
+}
+
+func (parser *parser) expect(keywords ...string) error {
+	if parser.done() {
+		return fmt.Errorf("expected %s, not the end of the statement", strings.Join(keywords, " "))
+	}
+	if !parser.accept(keywords...) {
+		return fmt.Errorf("expected %s, not %s", strings.Join(keywords, " "), render([]token{parser.peek(0)}))
+	}
+	return nil
+}
+
+func (parser *parser) identifier() (string, error) {
+	name := parser.next()
+	if name.kind != word && name.kind != quoted {
+		return "", fmt.Errorf("expected a name, not %s", render([]token{name}))
+	}
+	return name.text, nil
+}
+
+// group returns the tokens between the parenthesis that is next and the one that closes it.
+func (parser *parser) group() ([]token, error) {
+	if err := parser.expect("("); err != nil {
+		return nil, err
+	}
+	start := parser.position
+	depth := 1
+	for !parser.done() {
+		token := parser.next()
+		switch {
+		case token.is("("):
+			depth++
+		case token.is(")"):
+			depth--
+			if depth == 0 {
+				return parser.tokens[start : parser.position-1], nil
+			}
+		}
+	}
+	return nil, errors.New("a ( is never closed")
+}
+
+// split splits tokens at the commas that aren't in parentheses.
+func split(tokens []token) [][]token {
+	var parts [][]token
+	depth, start := 0, 0
+	for index, token := range tokens {
+		switch {
+		case token.is("("):
+			depth++
+		case token.is(")"):
+			depth--
+		case token.is(",") && depth == 0:
+			parts = append(parts, tokens[start:index])
+			start = index + 1
+		}
+	}
+	return append(parts, tokens[start:])
+}
+
+// names returns the column names in a parenthesized list, e.g. of a key. An item can have more than the
+// name, e.g. a prefix length or ASC, which is left out.
+func (parser *parser) names() ([]string, error) {
+	group, err := parser.group()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, item := range split(group) {
+		if len(item) == 0 || item[0].kind != word && item[0].kind != quoted {
+			return nil, fmt.Errorf("expected a list of columns, not (%s)", render(group))
+		}
+		names = append(names, item[0].text)
+	}
+	return names, nil
+}
+
+// column is what's known of a column of the table being read.
+type column struct {
+	name        string
+	fieldType   string
+	description string
+	required    bool
+	unique      bool
+	enum        []string
+	// the bounds are nil if the column hasn't got them
+	minLength, maxLength, minimum, maximum *int64
+}
+
+// tighten sets *bound to value, unless it is already tighter, i.e. larger than value if lower is true and
+// smaller than it if not.
+func tighten(bound **int64, value int64, lower bool) {
+	if *bound == nil || lower && value > **bound || !lower && value < **bound {
+		*bound = &value
+	}
+}
+
+// tableReader collects the columns and keys of the table being read.
+type tableReader struct {
+	name        string
+	columns     []*column
+	primaryKey  []string
+	uniqueKeys  [][]string
+	foreignKeys []schema.ForeignKey
+	notes       []Untranslated
+}
+
+func (reader *tableReader) note(column string, tokens []token, format string, args ...any) {
+	reader.notes = append(reader.notes, Untranslated{Column: column, SQL: render(tokens), Reason: fmt.Sprintf(format, args...)})
+}
+
+func (reader *tableReader) column(name string) *column {
+	index := slices.IndexFunc(reader.columns, func(column *column) bool { return strings.EqualFold(column.name, name) })
+	if index < 0 {
+		return nil
+	}
+	return reader.columns[index]
+}
+
+// uniqueTypes are the field types that have a unique constraint.
+var uniqueTypes = []string{"string", "number", "integer", "date", "datetime"}
+
+// addUniqueKey makes the columns called names unique together, as a unique constraint of the field if
+// there's one name and its type has one, or a unique key if not.
+func (reader *tableReader) addUniqueKey(names []string) {
+	if len(names) == 1 {
+		if column := reader.column(names[0]); column != nil && slices.Contains(uniqueTypes, column.fieldType) {
+			column.unique = true
+			return
+		}
+	}
+	reader.uniqueKeys = append(reader.uniqueKeys, names)
+}
+
+// referentialActions are the keywords that can follow the REFERENCES of a foreign key, e.g. ON DELETE
+// CASCADE, which a schema has no equivalent of.
+var referentialActions = []string{"ON", "DELETE", "UPDATE", "CASCADE", "RESTRICT", "NO", "ACTION", "SET", "NULL", "DEFAULT",
+	"MATCH", "FULL", "PARTIAL", "SIMPLE", "DEFERRABLE", "NOT", "INITIALLY", "DEFERRED", "IMMEDIATE"}
+
+// reference reads the REFERENCES clause of a foreign key from the columns called names, which the parser
+// has just moved past the REFERENCES of.
+func (parser *parser) reference(reader *tableReader, names []string, columnName string) error {
+	start := parser.position - 1
+	table, err := parser.qualifiedName()
+	if err != nil {
+		return err
+	}
+	var referenced []string
+	if parser.peek(0).is("(") {
+		if referenced, err = parser.names(); err != nil {
+			return err
+		}
+	}
+
+	actions := parser.position
+	for parser.peek(0).kind == word && slices.ContainsFunc(referentialActions, parser.peek(0).is) {
+		// NOT NULL after a column's REFERENCES is the column's, not the reference's
+		if parser.peek(0).is("NOT") && parser.peek(1).is("NULL") {
+			break
+		}
+		parser.next()
+	}
+	if parser.position > actions {
+		reader.note(columnName, parser.tokens[actions:parser.position], "a schema has no equivalent of what a database does to a foreign key's rows")
+	}
+
+	resource := table
+	if strings.EqualFold(table, reader.name) {
+		resource = ""
+	}
+	if referenced == nil {
+		// a reference without columns is to the referenced table's primary key, which is only known for this table
+		if resource != "" || reader.primaryKey == nil {
+			reader.note(columnName, parser.tokens[start:parser.position], "the columns referred to aren't named, and a foreign key in a schema has to name them")
+			return nil
+		}
+		referenced = reader.primaryKey
+	}
+	reader.foreignKeys = append(reader.foreignKeys, schema.ForeignKey{
+		Fields:    names,
+		Reference: schema.ForeignKeyReference{Resource: resource, Fields: referenced},
+	})
+	return nil
+}
+
+// qualifiedName reads a name which may be qualified, e.g. by a schema, returning its last part.
+func (parser *parser) qualifiedName() (string, error) {
+	name, err := parser.identifier()
+	for err == nil && parser.accept(".") {
+		name, err = parser.identifier()
+	}
+	return name, err
+}
+
+// tableConstraintKeywords are the keywords that start a constraint of a table rather than a column.
+var tableConstraintKeywords = []string{"CONSTRAINT", "PRIMARY", "UNIQUE", "FOREIGN", "CHECK", "KEY", "INDEX", "FULLTEXT", "SPATIAL", "EXCLUDE"}
+
+// tableConstraint reads a constraint of the table, e.g. PRIMARY KEY (id).
+func (parser *parser) tableConstraint(reader *tableReader) error {
+	if parser.accept("CONSTRAINT") {
+		if _, err := parser.identifier(); err != nil {
+			return err
+		}
+	}
+	start := parser.position
+	switch {
+	case parser.accept("PRIMARY", "KEY"):
+		names, err := parser.names()
+		if err != nil {
+			return err
+		}
+		reader.primaryKey = names
+	case parser.accept("UNIQUE"):
+		_ = parser.accept("KEY") || parser.accept("INDEX")
+		if !parser.peek(0).is("(") {
+			if _, err := parser.identifier(); err != nil {
+				return err
+			}
+		}
+		names, err := parser.names()
+		if err != nil {
+			return err
+		}
+		reader.addUniqueKey(names)
+	case parser.accept("FOREIGN", "KEY"):
+		if !parser.peek(0).is("(") {
+			if _, err := parser.identifier(); err != nil {
+				return err
+			}
+		}
+		names, err := parser.names()
+		if err != nil {
+			return err
+		}
+		if err := parser.expect("REFERENCES"); err != nil {
+			return err
+		}
+		if err := parser.reference(reader, names, ""); err != nil {
+			return err
+		}
+	case parser.accept("CHECK"):
+		condition, err := parser.group()
+		if err != nil {
+			return err
+		}
+		reader.check(condition, "")
+	case parser.peek(0).is("KEY") || parser.peek(0).is("INDEX") || parser.peek(0).is("FULLTEXT") || parser.peek(0).is("SPATIAL"):
+		parser.position = len(parser.tokens)
+		reader.note("", parser.tokens[start:], "an index isn't a constraint, so isn't part of a schema")
+	default:
+		parser.position = len(parser.tokens)
+		reader.note("", parser.tokens[start:], "this kind of constraint has no equivalent in a schema")
+	}
+	if !parser.done() {
+		reader.note("", parser.tokens[parser.position:], "this part of the constraint has no equivalent in a schema")
+	}
+	return nil
+}
+
+// columnConstraintKeywords are the keywords that end a column's type and start one of its constraints.
+var columnConstraintKeywords = []string{"CONSTRAINT", "NOT", "NULL", "PRIMARY", "UNIQUE", "DEFAULT", "CHECK", "REFERENCES", "COLLATE",
+	"GENERATED", "AS", "AUTO_INCREMENT", "AUTOINCREMENT", "COMMENT", "ON", "IDENTITY", "CHARACTER", "CHARSET"}
+
+func isColumnConstraintKeyword(token token) bool {
+	return token.kind == word && slices.ContainsFunc(columnConstraintKeywords, token.is)
+}
+
+// columnType reads a column's type, returning its words, e.g. "double precision", and the values in the
+// parentheses after it, e.g. 10 of VARCHAR(10) or the values of a MySQL ENUM. array is true for an array type.
+func (parser *parser) columnType() (words string, arguments []token, array bool, err error) {
+	var typeWords []string
+	for !parser.done() {
+		token := parser.peek(0)
+		switch {
+		// CHARACTER starts a type, e.g. CHARACTER VARYING, as well as the CHARACTER SET after one
+		case token.kind == word && (!isColumnConstraintKeyword(token) || token.is("CHARACTER") && len(typeWords) == 0):
+			typeWords = append(typeWords, strings.ToLower(parser.next().text))
+		case token.is("(") && len(typeWords) > 0:
+			group, err := parser.group()
+			if err != nil {
+				return "", nil, false, err
+			}
+			if arguments == nil {
+				arguments = group
+			}
+		case token.is("["):
+			parser.next()
+			if parser.peek(0).kind == number {
+				parser.next()
+			}
+			parser.accept("]")
+			array = true
+		default:
+			return strings.Join(typeWords, " "), arguments, array, nil
+		}
+	}
+	return strings.Join(typeWords, " "), arguments, array, nil
+}
+
+// typeFieldTypes maps the first word of a column type to the type of field for it.
+var typeFieldTypes = map[string]string{
+	"int": "integer", "integer": "integer", "bigint": "integer", "smallint": "integer", "tinyint": "integer",
+	"mediumint": "integer", "int2": "integer", "int4": "integer", "int8": "integer", "serial": "integer",
+	"bigserial": "integer", "smallserial": "integer", "serial4": "integer", "serial8": "integer",
+	"real": "number", "float": "number", "float4": "number", "float8": "number", "double": "number",
+	"numeric": "number", "decimal": "number", "dec": "number", "number": "number",
+	"bool": "boolean", "boolean": "boolean",
+	"date": "date", "timestamp": "datetime", "timestamptz": "datetime", "datetime": "datetime",
+	"char": "string", "character": "string", "varchar": "string", "nchar": "string", "nvarchar": "string",
+	"text": "string", "tinytext": "string", "mediumtext": "string", "longtext": "string", "clob": "string",
+	"string": "string", "citext": "string", "uuid": "string", "enum": "string", "national": "string",
+	"varying": "string",
+}
+
+// setType sets the field type of column from its SQL type.
+func (reader *tableReader) setType(column *column, words string, arguments []token, array bool, sql []token) {
+	typeWords := strings.Fields(words)
+	unsigned := slices.Contains(typeWords, "unsigned")
+	typeWords = slices.DeleteFunc(typeWords, func(word string) bool { return word == "unsigned" || word == "zerofill" || word == "signed" })
+	if slices.Contains(typeWords, "array") {
+		array = true
+	}
+
+	column.fieldType = "string"
+	switch {
+	case array:
+		column.fieldType = "list"
+		return
+	case len(typeWords) == 0:
+		// SQLite allows a column without a type
+		return
+	case typeWords[0] == "tinyint" && len(arguments) == 1 && arguments[0].text == "1":
+		// MySQL's BOOLEAN is a TINYINT(1)
+		column.fieldType = "boolean"
+		return
+	}
+
+	fieldType, ok := typeFieldTypes[typeWords[0]]
+	if !ok {
+		reader.note(column.name, sql, "%s has no equivalent type of field, so %s is a string field", strings.ToUpper(words), column.name)
+		return
+	}
+	column.fieldType = fieldType
+
+	switch {
+	case typeWords[0] == "enum":
+		for _, argument := range split(arguments) {
+			if len(argument) == 1 && argument[0].kind == stringLiteral {
+				column.enum = append(column.enum, argument[0].text)
+			}
+		}
+	case fieldType == "string" && len(arguments) == 1 && arguments[0].kind == number:
+		if length, err := strconv.ParseInt(arguments[0].text, 10, 64); err == nil {
+			tighten(&column.maxLength, length, false)
+		}
+	case unsigned && (fieldType == "integer" || fieldType == "number"):
+		tighten(&column.minimum, 0, true)
+	}
+}
+
+// expression reads the tokens of an expression, e.g. of a DEFAULT: a value, perhaps negative, a function
+// call, or an expression in parentheses, and any casts of it.
+func (parser *parser) expression() error {
+	parser.accept("-")
+	token := parser.next()
+	if token.is("(") {
+		parser.position--
+		if _, err := parser.group(); err != nil {
+			return err
+		}
+	} else if token.kind == word && parser.peek(0).is("(") {
+		if _, err := parser.group(); err != nil {
+			return err
+		}
+	}
+	for parser.accept("::") {
+		if _, _, _, err := parser.columnType(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// columnDefinition reads the definition of a column, e.g. id INTEGER NOT NULL PRIMARY KEY.
+func (parser *parser) columnDefinition(reader *tableReader) error {
+	name, err := parser.identifier()
+	if err != nil {
+		return err
+	}
+	if reader.column(name) != nil {
+		return fmt.Errorf("there is already a column called %s", name)
+	}
+	column := &column{name: name}
+	reader.columns = append(reader.columns, column)
+
+	typeStart := parser.position
+	words, arguments, array, err := parser.columnType()
+	if err != nil {
+		return err
+	}
+	reader.setType(column, words, arguments, array, parser.tokens[typeStart:parser.position])
+
+	for !parser.done() {
+		start := parser.position
+		switch {
+		case parser.accept("CONSTRAINT"):
+			if _, err := parser.identifier(); err != nil {
+				return err
+			}
+		case parser.accept("NOT", "NULL"):
+			column.required = true
+		case parser.accept("NULL"):
+		case parser.accept("PRIMARY", "KEY"):
+			reader.primaryKey = []string{column.name}
+			_ = parser.accept("ASC") || parser.accept("DESC")
+			if parser.accept("AUTOINCREMENT") {
+				reader.note(column.name, parser.tokens[parser.position-1:parser.position], "a schema has no equivalent of values the database generates")
+			}
+		case parser.accept("UNIQUE"):
+			parser.accept("KEY")
+			reader.addUniqueKey([]string{column.name})
+		case parser.accept("CHECK"):
+			condition, err := parser.group()
+			if err != nil {
+				return err
+			}
+			reader.check(condition, column.name)
+		case parser.accept("REFERENCES"):
+			if err := parser.reference(reader, []string{column.name}, column.name); err != nil {
+				return err
+			}
+		case parser.accept("COMMENT"):
+			comment := parser.next()
+			if comment.kind != stringLiteral {
+				return fmt.Errorf("expected the comment of %s to be a string", column.name)
+			}
+			column.description = comment.text
+		case parser.accept("DEFAULT"):
+			if err := parser.expression(); err != nil {
+				return err
+			}
+			if !parser.tokens[parser.position-1].is("NULL") {
+				reader.note(column.name, parser.tokens[start:parser.position], "a schema has no default values")
+			}
+		case parser.accept("AUTO_INCREMENT") || parser.accept("AUTOINCREMENT"):
+			reader.note(column.name, parser.tokens[start:parser.position], "a schema has no equivalent of values the database generates")
+		default:
+			// anything else, e.g. COLLATE or GENERATED ALWAYS AS (...), runs up to the next constraint
+			parser.next()
+			for !parser.done() && !slices.ContainsFunc([]string{"CONSTRAINT", "NOT", "NULL", "PRIMARY", "UNIQUE", "CHECK", "REFERENCES", "DEFAULT", "COMMENT"}, parser.peek(0).is) {
+				if parser.peek(0).is("(") {
+					if _, err := parser.group(); err != nil {
+						return err
+					}
+					continue
+				}
+				parser.next()
+			}
+			reader.note(column.name, parser.tokens[start:parser.position], "this has no equivalent in a schema")
+		}
+	}
+	return nil
+}
+
+// createTable moves past the keywords of a CREATE TABLE statement up to the table's name, reporting whether
+// they were there.
+func (parser *parser) createTable() bool {
+	if !parser.accept("CREATE") {
+		return false
+	}
+	parser.accept("OR", "REPLACE")
+	for _, modifier := range []string{"GLOBAL", "LOCAL", "TEMP", "TEMPORARY", "UNLOGGED"} {
+		parser.accept(modifier)
+	}
+	if !parser.accept("TABLE") {
+		return false
+	}
+	parser.accept("IF", "NOT", "EXISTS")
+	return true
+}
+
+func isCreateTable(statement []token) bool {
+	return (&parser{tokens: statement}).createTable()
+}
+
+// parseCreateTable reads a CREATE TABLE statement, whose tokens are tokens.
+func parseCreateTable(tokens []token) (Table, error) {
+	statement := &parser{tokens: tokens}
+	if !statement.createTable() {
+		return Table{}, errors.New("expected a CREATE TABLE statement")
+	}
+	name, err := statement.qualifiedName()
+	if err != nil {
+		return Table{}, err
+	}
+	if !statement.peek(0).is("(") {
+		return Table{}, fmt.Errorf("%s has no column definitions", name)
+	}
+	definitions, err := statement.group()
+	if err != nil {
+		return Table{}, fmt.Errorf("%s: %w", name, err)
+	}
+	// what follows the definitions, e.g. ENGINE=InnoDB or WITHOUT ROWID, is about how the table is stored
+
+	reader := &tableReader{name: name}
+	// table constraints are read after the columns, so that they can refer to the columns' types
+	var constraints [][]token
+	for _, definition := range split(definitions) {
+		if len(definition) == 0 {
+			return Table{}, fmt.Errorf("%s has an empty column definition", name)
+		}
+		if definition[0].kind == word && slices.ContainsFunc(tableConstraintKeywords, definition[0].is) {
+			constraints = append(constraints, definition)
+			continue
+		}
+		if err := (&parser{tokens: definition}).columnDefinition(reader); err != nil {
+			return Table{}, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	for _, constraint := range constraints {
+		if err := (&parser{tokens: constraint}).tableConstraint(reader); err != nil {
+			return Table{}, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	tableSchema, err := reader.build()
+	if err != nil {
+		return Table{}, fmt.Errorf("%s: %w", name, err)
+	}
+	return Table{Name: name, Schema: tableSchema, Untranslated: reader.notes}, nil
+}
+
+// build returns the schema of the table that has been read.
+func (reader *tableReader) build() (schema.Schema, error) {
+	builder := schema.New()
+	for _, column := range reader.columns {
+		switch column.fieldType {
+		case "number":
+			builder.Number(column.name)
+		case "integer":
+			builder.Integer(column.name)
+		case "boolean":
+			builder.Boolean(column.name)
+		case "date":
+			builder.Date(column.name)
+		case "datetime":
+			builder.DateTime(column.name)
+		case "list":
+			builder.List(column.name)
+		default:
+			builder.String(column.name)
+		}
+		if column.description != "" {
+			builder.Description(column.description)
+		}
+		// a primary key's columns can't be NULL, whether or not they're NOT NULL
+		if column.required || slices.ContainsFunc(reader.primaryKey, func(name string) bool { return strings.EqualFold(name, column.name) }) {
+			builder.Required()
+		}
+		if column.unique {
+			builder.Unique()
+		}
+		if column.enum != nil {
+			builder.Enum(column.enum...)
+		}
+		for _, bound := range []struct {
+			value *int64
+			set   func(int64) *schema.Builder
+		}{
+			{column.minLength, builder.MinLength},
+			{column.maxLength, builder.MaxLength},
+			{column.minimum, builder.Min},
+			{column.maximum, builder.Max},
+		} {
+			if bound.value != nil {
+				bound.set(*bound.value)
+			}
+		}
+	}
+
+	// keys are written with the names of their columns, whatever the case of the names in the definitions
+	fieldName := func(name string) string {
+		if column := reader.column(name); column != nil {
+			return column.name
+		}
+		return name
+	}
+	fieldNames := func(names []string) []string {
+		var fieldNames []string
+		for _, name := range names {
+			fieldNames = append(fieldNames, fieldName(name))
+		}
+		return fieldNames
+	}
+	if reader.primaryKey != nil {
+		builder.PrimaryKey(fieldNames(reader.primaryKey)...)
+	}
+	for _, key := range reader.uniqueKeys {
+		builder.UniqueKey(fieldNames(key)...)
+	}
+	for _, key := range reader.foreignKeys {
+		referenced := key.Reference.Fields
+		if key.Reference.Resource == "" {
+			referenced = fieldNames(referenced)
+		}
+		builder.ForeignKey(fieldNames(key.Fields), key.Reference.Resource, referenced)
+	}
+	return builder.Build()
+}
+
+// ParseCreateTables reads the tables created by the CREATE TABLE statements of sql, which can be written for
+// PostgreSQL, SQLite or MySQL. Other statements are skipped. Each column is a field of the type closest to the
+// column's, which is required if the column is NOT NULL or part of the primary key. A PRIMARY KEY, UNIQUE or
+// REFERENCES constraint is a key of the schema, or a unique constraint if it's of one column. A CHECK
+// constraint is translated into enum, min, max, minLength and maxLength constraints if it's made up of
+// comparisons that they can express, e.g. size IN ('S', 'M') AND char_length(code) <= 3.
+//
+// Anything else a statement says about a column or the table's constraints, e.g. a DEFAULT, is listed in
+// the Table's Untranslated. What follows a table's definitions, e.g. ENGINE=InnoDB, is left out, since it's
+// about how the table is stored rather than its data.
+func ParseCreateTables(sql string) ([]Table, error) {
+	tokens, err := tokenize(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	var tables []Table
+	start := 0
+	for index := 0; index <= len(tokens); index++ {
+		if index < len(tokens) && !tokens[index].is(";") {
+			continue
+		}
+		statement := tokens[start:index]
+		start = index + 1
+
+		if !isCreateTable(statement) {
+			continue
+		}
+
+		table, err := parseCreateTable(statement)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
@@ -0,0 +1,174 @@
+package ddl
+
+import (
+	"strings"
+	"tableschema-validator/schema"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseCreateTables(t *testing.T) {
+	sql := `
+-- a PostgreSQL table
+CREATE TABLE IF NOT EXISTS public.accounts (
+  id serial PRIMARY KEY,
+  email varchar(100) NOT NULL UNIQUE,
+  size text CHECK (size IN ('S', 'M')) DEFAULT 'S',
+  score numeric(5,2) CHECK (score >= 0 AND score <= 10 OR score IS NULL),
+  age integer CHECK (age BETWEEN 0 AND 150),
+  code char(3) CHECK (char_length(code) = 3),
+  active boolean NOT NULL,
+  tags text[],
+  created timestamp with time zone,
+  team_id bigint REFERENCES teams (id) ON DELETE CASCADE,
+  parent_id integer REFERENCES accounts,
+  data jsonb,
+  CONSTRAINT positive CHECK (age > 17 OR score > 0),
+  UNIQUE (team_id, email)
+);
+CREATE INDEX accounts_email ON accounts (email);
+
+/* a MySQL one */
+CREATE TABLE ` + "`orders` (\n" +
+		"  `id` int unsigned NOT NULL AUTO_INCREMENT,\n" +
+		"  `status` enum('new','paid') NOT NULL COMMENT 'Order status',\n" +
+		"  `paid` tinyint(1) DEFAULT NULL,\n" +
+		"  `account_id` int NOT NULL,\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  KEY `account` (`account_id`),\n" +
+		"  CONSTRAINT `fk` FOREIGN KEY (`account_id`) REFERENCES `accounts` (`id`)\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;\n" + `
+CREATE TABLE [items] ("id" INTEGER PRIMARY KEY, name, price REAL CHECK (0 <= price), qty INTEGER CHECK (qty > 0)) WITHOUT ROWID
+`
+
+	got, err := ParseCreateTables(sql)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accounts, err := schema.New().
+		Integer("id").Required().
+		String("email").Required().Unique().MaxLength(100).
+		String("size").Enum("S", "M").
+		Number("score").Min(0).Max(10).
+		Integer("age").Min(0).Max(150).
+		String("code").MinLength(3).MaxLength(3).
+		Boolean("active").Required().
+		List("tags").
+		DateTime("created").
+		Integer("team_id").
+		Integer("parent_id").
+		String("data").
+		PrimaryKey("id").
+		UniqueKey("team_id", "email").
+		ForeignKey([]string{"team_id"}, "teams", []string{"id"}).
+		ForeignKey([]string{"parent_id"}, "", []string{"id"}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	orders, err := schema.New().
+		Integer("id").Required().Min(0).
+		String("status").Description("Order status").Required().Enum("new", "paid").
+		Boolean("paid").
+		Integer("account_id").Required().
+		PrimaryKey("id").
+		ForeignKey([]string{"account_id"}, "accounts", []string{"id"}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err := schema.New().
+		Integer("id").Required().
+		String("name").
+		Number("price").Min(0).
+		Integer("qty").Min(1).
+		PrimaryKey("id").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Table{
+		{
+			Name:   "accounts",
+			Schema: accounts,
+			Untranslated: []Untranslated{
+				{Column: "size", SQL: "DEFAULT 'S'", Reason: "a schema has no default values"},
+				{Column: "team_id", SQL: "ON DELETE CASCADE", Reason: "a schema has no equivalent of what a database does to a foreign key's rows"},
+				{Column: "data", SQL: "jsonb", Reason: "JSONB has no equivalent type of field, so data is a string field"},
+				{SQL: "CHECK (age > 17 OR score > 0)", Reason: "this condition can't be expressed with the constraints of a schema"},
+			},
+		},
+		{
+			Name:   "orders",
+			Schema: orders,
+			Untranslated: []Untranslated{
+				{Column: "id", SQL: "AUTO_INCREMENT", Reason: "a schema has no equivalent of values the database generates"},
+				{SQL: `KEY "account" ("account_id")`, Reason: "an index isn't a constraint, so isn't part of a schema"},
+			},
+		},
+		{Name: "items", Schema: items},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestParseCreateTablesRoundTrip(t *testing.T) {
+	want, err := schema.New().
+		Integer("id").Required().Min(1).
+		String("email").Required().Unique().MaxLength(100).
+		String("size").Enum("S", "M", "it's").MinLength(1).
+		Number("score").Min(0).Max(10).
+		Boolean("active").Enum("true").
+		Date("joined").
+		DateTime("updated").
+		Integer("manager").
+		PrimaryKey("id").
+		UniqueKey("manager", "email").
+		ForeignKey([]string{"manager"}, "", []string{"id"}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a table created from a schema has the schema, apart from what a database's types can't tell apart
+	statement, err := CreateTable(want, Options{Dialect: PostgreSQL, Table: "people"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tables, err := ParseCreateTables(statement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) != 1 {
+		t.Fatalf("expected one table, got %d", len(tables))
+	}
+	if diff := cmp.Diff(Table{Name: "people", Schema: want}, tables[0]); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestParseCreateTablesErrors(t *testing.T) {
+	testCases := []struct {
+		sql     string
+		wantErr string
+	}{
+		{sql: "CREATE TABLE t (id int", wantErr: "a ( is never closed"},
+		{sql: "CREATE TABLE t (name 'text)", wantErr: "a ' is never closed"},
+		{sql: "CREATE TABLE t AS SELECT 1", wantErr: "t has no column definitions"},
+		{sql: "CREATE TABLE t (id int, id text)", wantErr: "there is already a column called id"},
+		{sql: "CREATE TABLE t (id int, PRIMARY KEY (key))", wantErr: "the primary key names key, which is not a field"},
+		{sql: "/* never closed", wantErr: "a comment is never closed"},
+	}
+
+	for _, testCase := range testCases {
+		_, err := ParseCreateTables(testCase.sql)
+		if err == nil || !strings.Contains(err.Error(), testCase.wantErr) {
+			t.Errorf("%s: expected an error containing %q, got %v", testCase.sql, testCase.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"tableschema-validator/schema"
+)
+
+// diffReport is the JSON report of the diff command.
+type diffReport struct {
+	Breaking bool            `json:"breaking"`
+	Changes  []schema.Change `json:"changes"`
+}
+
+func runDiff(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("diff", stderr)
+	schemaFlags := addSchemaFlags(flags)
+	output := flags.String("output", "text", `report format, "text" or "json"`)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: tableschema-validator diff [flags] old-schema.json new-schema.json")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Lists the changes between two versions of a schema, and whether each could break a consumer of the data.")
+		fmt.Fprintln(stderr, "Exits with 0 if no change is breaking, 1 if any is, and 2 on a usage or read error.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+
+	paths, err := parseFlags(flags, args)
+	if err != nil {
+		return flagsExitCode(err)
+	}
+	if len(paths) != 2 {
+		fmt.Fprintln(stderr, "tableschema-validator diff: expected an old and a new schema")
+		flags.Usage()
+		return exitError
+	}
+	if *output != "text" && *output != "json" {
+		fmt.Fprintf(stderr, "tableschema-validator diff: unknown output %q, expected text or json\n", *output)
+		return exitError
+	}
+
+	var schemas [2]schema.Schema
+	for index, path := range paths {
+		schemas[index], err = schemaFlags.load(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "tableschema-validator diff: %s: %s\n", path, err.Error())
+			return exitError
+		}
+	}
+
+	changes := schema.Compare(schemas[0], schemas[1])
+	breaking := schema.HasBreakingChanges(changes)
+
+	if *output == "json" {
+		report := diffReport{Breaking: breaking, Changes: changes}
+		if report.Changes == nil {
+			report.Changes = []schema.Change{}
+		}
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			fmt.Fprintf(stderr, "tableschema-validator diff: %s\n", err.Error())
+			return exitError
+		}
+	} else {
+		if len(changes) == 0 {
+			fmt.Fprintln(stdout, "no changes")
+		}
+		for _, change := range changes {
+			classification := "non-breaking"
+			if change.Breaking {
+				classification = "breaking"
+			}
+			fmt.Fprintf(stdout, "%s: %s\n", classification, change.Message)
+		}
+	}
+
+	if breaking {
+		return exitInvalid
+	}
+	return exitValid
+}
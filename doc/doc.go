@@ -0,0 +1,224 @@
+// Package doc renders a schema as documentation for the people who read the data rather than validate it: a
+// data dictionary, in Markdown or as a standalone HTML page, with a row for each field and its constraints in
+// plain English, followed by the table's keys.
+package doc
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+	"tableschema-validator/schema"
+)
+
+// Options are what the documentation is called.
+type Options struct {
+	// Title is the heading of the documentation, e.g. the name of the table. It's left out if it's "".
+	Title string
+}
+
+// field is the documentation of a field.
+type field struct {
+	Name        string
+	Type        string
+	Title       string
+	Description string
+	Example     string
+	// Constraints are the field's constraints in plain English, e.g. "must be one of S, M".
+	Constraints []string
+}
+
+// page is the documentation of a schema.
+type page struct {
+	Title  string
+	Fields []field
+	// Keys are the schema's keys in plain English, e.g. "team_id refers to id of teams".
+	Keys []string
+}
+
+// list returns values as a list in English, e.g. "a, b or c" if conjunction is "or".
+func list(values []string, conjunction string) string {
+	if len(values) < 2 {
+		return strings.Join(values, "")
+	}
+	return strings.Join(values[:len(values)-1], ", ") + " " + conjunction + " " + values[len(values)-1]
+}
+
+// plural returns count with noun, which is made plural if count isn't 1, e.g. "3 characters".
+func plural(count int64, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return strconv.FormatInt(count, 10) + " " + noun + "s"
+}
+
+// between returns the plain English of a lower and upper bound of what format describes, either of which
+// may not be set, e.g. "must be between 1 and 10" or "must be at least 1".
+func between(minimum int64, hasMinimum bool, maximum int64, hasMaximum bool, format func(int64) string) string {
+	switch {
+	case hasMinimum && hasMaximum && minimum == maximum:
+		return "must be exactly " + format(minimum)
+	case hasMinimum && hasMaximum:
+		return fmt.Sprintf("must be between %s and %s", strconv.FormatInt(minimum, 10), format(maximum))
+	case hasMinimum:
+		return "must be at least " + format(minimum)
+	case hasMaximum:
+		return "must be at most " + format(maximum)
+	}
+	return ""
+}
+
+// constraints returns the constraints of descriptor in plain English, in the order they're most often read.
+func constraints(descriptor schema.FieldDescriptor) []string {
+	var result []string
+	if required, _ := descriptor.Constraints["required"].(bool); required {
+		result = append(result, "required")
+	}
+	if unique, _ := descriptor.Constraints["unique"].(bool); unique {
+		result = append(result, "must be unique")
+	}
+	if enum, ok := descriptor.Constraints["enum"].([]string); ok {
+		if len(enum) == 1 {
+			result = append(result, "must be "+enum[0])
+		} else {
+			result = append(result, "must be one of "+strings.Join(enum, ", "))
+		}
+	}
+	if pattern, ok := descriptor.Constraints["pattern"].(string); ok {
+		result = append(result, "must match the regular expression "+pattern)
+	}
+
+	minLength, hasMinLength := descriptor.Constraints["minLength"].(int64)
+	maxLength, hasMaxLength := descriptor.Constraints["maxLength"].(int64)
+	unit := "character"
+	if descriptor.FieldType == "list" {
+		unit = "item"
+	}
+	length := between(minLength, hasMinLength, maxLength, hasMaxLength, func(count int64) string { return plural(count, unit) })
+	if length != "" {
+		if descriptor.FieldType == "list" {
+			length = strings.Replace(length, "must be", "must have", 1)
+		} else {
+			length += " long"
+		}
+		result = append(result, length)
+	}
+
+	minimum, hasMinimum := descriptor.Constraints["min"].(int64)
+	maximum, hasMaximum := descriptor.Constraints["max"].(int64)
+	if bounds := between(minimum, hasMinimum, maximum, hasMaximum, func(value int64) string { return strconv.FormatInt(value, 10) }); bounds != "" {
+		result = append(result, bounds)
+	}
+	return result
+}
+
+// keys returns the keys of tableSchema in plain English.
+func keys(tableSchema schema.Schema) []string {
+	var result []string
+	if len(tableSchema.PrimaryKey) > 0 {
+		result = append(result, "Primary key: "+list(tableSchema.PrimaryKey, "and"))
+	}
+	for _, key := range tableSchema.UniqueKeys {
+		result = append(result, "Unique key: "+list(key, "and")+", which must be unique together")
+	}
+	for _, key := range tableSchema.ForeignKeys {
+		table := key.Reference.Resource
+		if table == "" {
+			table = "this table"
+		}
+		result = append(result, fmt.Sprintf("Foreign key: %s, which must be the %s of a row of %s",
+			list(key.Fields, "and"), list(key.Reference.Fields, "and"), table))
+	}
+	return result
+}
+
+func makePage(tableSchema schema.Schema, options Options) page {
+	result := page{Title: options.Title, Keys: keys(tableSchema)}
+	for _, descriptor := range tableSchema.Fields.List() {
+		result.Fields = append(result.Fields, field{
+			Name:        descriptor.Name,
+			Type:        descriptor.FieldType,
+			Title:       descriptor.Title,
+			Description: descriptor.Description,
+			Example:     descriptor.Example,
+			Constraints: constraints(descriptor),
+		})
+	}
+	return result
+}
+
+// markdownEscaper escapes the characters that Markdown, or a table in it, would treat as markup.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "|", `\|`,
+	"\r\n", " ", "\n", " ",
+)
+
+// Markdown returns the documentation of tableSchema in Markdown: a table of the fields, with their types,
+// titles, descriptions, examples and constraints in plain English, followed by a list of the keys.
+func Markdown(tableSchema schema.Schema, options Options) string {
+	page := makePage(tableSchema, options)
+	var builder strings.Builder
+	if page.Title != "" {
+		fmt.Fprintf(&builder, "# %s\n\n", markdownEscaper.Replace(page.Title))
+	}
+
+	builder.WriteString("| Field | Type | Title | Description | Example | Constraints |\n")
+	builder.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+	for _, field := range page.Fields {
+		cells := []string{field.Name, field.Type, field.Title, field.Description, field.Example, strings.Join(field.Constraints, "; ")}
+		for index, cell := range cells {
+			cells[index] = markdownEscaper.Replace(cell)
+		}
+		fmt.Fprintf(&builder, "| %s |\n", strings.Join(cells, " | "))
+	}
+
+	if len(page.Keys) > 0 {
+		builder.WriteString("\n## Keys\n\n")
+		for _, key := range page.Keys {
+			fmt.Fprintf(&builder, "- %s\n", markdownEscaper.Replace(key))
+		}
+	}
+	return builder.String()
+}
+
+var htmlTemplate = template.Must(template.New("doc").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{with .Title}}{{.}}{{else}}Schema{{end}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.4em 0.6em; text-align: left; vertical-align: top; }
+th { background: #f4f4f4; }
+td ul { margin: 0; padding-left: 1.2em; }
+</style>
+</head>
+<body>
+{{with .Title}}<h1>{{.}}</h1>
+{{end}}<table>
+<thead>
+<tr><th>Field</th><th>Type</th><th>Title</th><th>Description</th><th>Example</th><th>Constraints</th></tr>
+</thead>
+<tbody>
+{{range .Fields}}<tr><td><code>{{.Name}}</code></td><td>{{.Type}}</td><td>{{.Title}}</td><td>{{.Description}}</td><td>{{.Example}}</td><td>{{with .Constraints}}<ul>{{range .}}<li>{{.}}</li>{{end}}</ul>{{end}}</td></tr>
+{{end}}</tbody>
+</table>
+{{with .Keys}}<h2>Keys</h2>
+<ul>
+{{range .}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}</body>
+</html>
+`))
+
+// HTML returns the documentation of tableSchema as a standalone HTML page, with the same table of fields and
+// list of keys as Markdown returns.
+func HTML(tableSchema schema.Schema, options Options) (string, error) {
+	var buffer bytes.Buffer
+	if err := htmlTemplate.Execute(&buffer, makePage(tableSchema, options)); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
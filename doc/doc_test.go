@@ -0,0 +1,106 @@
+package doc
+
+import (
+	"strings"
+	"tableschema-validator/schema"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func accounts(t *testing.T) schema.Schema {
+	t.Helper()
+	tableSchema, err := schema.New().
+		Integer("id").Title("ID").Example("42").Required().Unique().Min(1).
+		String("code").Description("Three letters, e.g. ABC").Required().Pattern("[A-Z]{3}").MinLength(3).MaxLength(3).
+		String("size").Enum("bar", "baz").MaxLength(10).
+		Number("score").Min(0).Max(10).
+		Boolean("active").Enum("true").
+		List("tags").MinLength(1).
+		Integer("team_id").
+		Integer("manager").
+		PrimaryKey("id").
+		UniqueKey("team_id", "code").
+		ForeignKey([]string{"team_id"}, "teams", []string{"id"}).
+		ForeignKey([]string{"manager"}, "", []string{"id"}).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tableSchema
+}
+
+func TestMarkdown(t *testing.T) {
+	got := Markdown(accounts(t), Options{Title: "accounts"})
+
+	want := `# accounts
+
+| Field | Type | Title | Description | Example | Constraints |
+| --- | --- | --- | --- | --- | --- |
+| id | integer | ID |  | 42 | required; must be unique; must be at least 1 |
+| code | string |  | Three letters, e.g. ABC |  | required; must match the regular expression \[A-Z\]{3}; must be exactly 3 characters long |
+| size | string |  |  |  | must be one of bar, baz; must be at most 10 characters long |
+| score | number |  |  |  | must be between 0 and 10 |
+| active | boolean |  |  |  | must be true |
+| tags | list |  |  |  | must have at least 1 item |
+| team\_id | integer |  |  |  |  |
+| manager | integer |  |  |  |  |
+
+## Keys
+
+- Primary key: id
+- Unique key: team\_id and code, which must be unique together
+- Foreign key: team\_id, which must be the id of a row of teams
+- Foreign key: manager, which must be the id of a row of this table
+`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestMarkdownEscapes(t *testing.T) {
+	tableSchema, err := schema.New().String("a|b").Description("one\ntwo <b>").Pattern("x|y").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := Markdown(tableSchema, Options{})
+	want := "| Field | Type | Title | Description | Example | Constraints |\n" +
+		"| --- | --- | --- | --- | --- | --- |\n" +
+		`| a\|b | string |  | one two \<b\> |  | must match the regular expression x\|y |` + "\n"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	got, err := HTML(accounts(t), Options{Title: "<accounts>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<title>&lt;accounts&gt;</title>",
+		"<h1>&lt;accounts&gt;</h1>",
+		"<tr><td><code>id</code></td><td>integer</td><td>ID</td><td></td><td>42</td><td><ul><li>required</li><li>must be unique</li><li>must be at least 1</li></ul></td></tr>",
+		"<tr><td><code>team_id</code></td><td>integer</td><td></td><td></td><td></td><td></td></tr>",
+		"<li>Foreign key: manager, which must be the id of a row of this table</li>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected the page to contain %q, got:\n%s", want, got)
+		}
+	}
+
+	// a schema without keys has no list of them
+	tableSchema, err := schema.New().String("name").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = HTML(tableSchema, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "Keys") || strings.Contains(got, "<h1>") {
+		t.Errorf("expected no heading or keys, got:\n%s", got)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"tableschema-validator/doc"
+)
+
+func runDoc(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("doc", stderr)
+	schemaFlags := addSchemaFlags(flags)
+	format := flags.String("format", "markdown", `format of the documentation: "markdown" or "html"`)
+	title := flags.String("title", "", "heading of the documentation (default the name of the schema file, without its extension)")
+	output := flags.String("output", "", "file to write to (default stdout)")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: tableschema-validator doc [flags] schema.json")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Writes documentation of a schema for the people who read the data: a table of its fields, with their")
+		fmt.Fprintln(stderr, "constraints in plain English, and its keys, in Markdown or as a standalone HTML page.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+
+	paths, err := parseFlags(flags, args)
+	if err != nil {
+		return flagsExitCode(err)
+	}
+	if len(paths) != 1 {
+		fmt.Fprintln(stderr, "tableschema-validator doc: expected one schema")
+		flags.Usage()
+		return exitError
+	}
+	if *format != "markdown" && *format != "html" {
+		fmt.Fprintf(stderr, "tableschema-validator doc: unknown format %q, expected markdown or html\n", *format)
+		return exitError
+	}
+
+	tableSchema, err := schemaFlags.load(paths[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "tableschema-validator doc: %s: %s\n", paths[0], err.Error())
+		return exitError
+	}
+
+	if *title == "" {
+		*title = strings.TrimSuffix(filepath.Base(paths[0]), filepath.Ext(paths[0]))
+	}
+	options := doc.Options{Title: *title}
+	var documentation string
+	if *format == "html" {
+		documentation, err = doc.HTML(tableSchema, options)
+	} else {
+		documentation = doc.Markdown(tableSchema, options)
+	}
+	if err == nil {
+		if *output == "" {
+			_, err = io.WriteString(stdout, documentation)
+		} else {
+			err = os.WriteFile(*output, []byte(documentation), 0o644)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "tableschema-validator doc: %s\n", err.Error())
+		return exitError
+	}
+	return exitValid
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"tableschema-validator/avro"
+	"tableschema-validator/ddl"
+	"tableschema-validator/jsonschema"
+)
+
+func runExport(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("export", stderr)
+	schemaFlags := addSchemaFlags(flags)
+	format := flags.String("format", "", `format to export to: "postgresql", "sqlite", "mysql", "jsonschema" or "avro" (required)`)
+	table := flags.String("table", "", "name of the table or Avro record, or title of a JSON Schema (default the name of the schema file, without its extension)")
+	output := flags.String("output", "", "file to write to (default stdout)")
+	namespace := flags.String("namespace", "", "namespace of an Avro record")
+	decimals := map[string]avro.Decimal{}
+	flags.Func("decimal", "`field=precision,scale` of a field whose Avro values are decimals (can be repeated)", func(value string) error {
+		name, digits, _ := strings.Cut(value, "=")
+		precision, scale, _ := strings.Cut(digits, ",")
+		var decimal avro.Decimal
+		var err error
+		if decimal.Precision, err = strconv.Atoi(precision); err == nil && scale != "" {
+			decimal.Scale, err = strconv.Atoi(scale)
+		}
+		if name == "" || err != nil {
+			return fmt.Errorf("expected field=precision,scale, got %q", value)
+		}
+		decimals[name] = decimal
+		return nil
+	})
+	var times []string
+	flags.Func("time", "`field` whose Avro values are times of day (can be repeated)", func(value string) error {
+		times = append(times, value)
+		return nil
+	})
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: tableschema-validator export --format format [flags] schema.json")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Exports a schema to another format: the CREATE TABLE statement for a table of its rows, for a database,")
+		fmt.Fprintln(stderr, "a JSON Schema for one of its rows as a JSON object, or an Avro record schema for one of its rows.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+
+	paths, err := parseFlags(flags, args)
+	if err != nil {
+		return flagsExitCode(err)
+	}
+	if len(paths) != 1 {
+		fmt.Fprintln(stderr, "tableschema-validator export: expected one schema")
+		flags.Usage()
+		return exitError
+	}
+	if !slices.Contains(ddl.Dialects, ddl.Dialect(*format)) && *format != "jsonschema" && *format != "avro" {
+		fmt.Fprintf(stderr, "tableschema-validator export: unknown format %q, expected postgresql, sqlite, mysql, jsonschema or avro\n", *format)
+		return exitError
+	}
+
+	tableSchema, err := schemaFlags.load(paths[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "tableschema-validator export: %s: %s\n", paths[0], err.Error())
+		return exitError
+	}
+
+	if *table == "" {
+		*table = strings.TrimSuffix(filepath.Base(paths[0]), filepath.Ext(paths[0]))
+		if *format == "avro" {
+			*table = avro.Name(*table)
+		}
+	}
+	var exported []byte
+	switch *format {
+	case "jsonschema":
+		exported, err = jsonschema.Generate(tableSchema, jsonschema.Options{Title: *table})
+	case "avro":
+		exported, err = avro.Generate(tableSchema, avro.Options{Name: *table, Namespace: *namespace, Decimals: decimals, Times: times})
+	default:
+		var statement string
+		statement, err = ddl.CreateTable(tableSchema, ddl.Options{Dialect: ddl.Dialect(*format), Table: *table})
+		exported = []byte(statement)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "tableschema-validator export: %s: %s\n", paths[0], err.Error())
+		return exitError
+	}
+
+	if *output == "" {
+		_, err = stdout.Write(exported)
+	} else {
+		err = os.WriteFile(*output, exported, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "tableschema-validator export: %s\n", err.Error())
+		return exitError
+	}
+	return exitValid
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"tableschema-validator/codegen"
+)
+
+func runGenerate(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("generate", stderr)
+	schemaFlags := addSchemaFlags(flags)
+	schemaPath := flags.String("schema", "", "path to the table schema descriptor (required)")
+	packageName := flags.String("package", "", "name of the package to generate code for (required)")
+	typeName := flags.String("type", "", "name of the generated struct (required)")
+	output := flags.String("output", "", "file to write the code to (default stdout)")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: tableschema-validator generate --schema schema.json --package name --type Name [--output file.go]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Generates a Go struct for the rows of the schema, and a function which parses and checks a row's cells into one.")
+		fmt.Fprintln(stderr, "For use with go:generate, e.g.")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "\t//go:generate go run tableschema-validator generate --schema schema.json --package rows --type Row --output row_gen.go")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+
+	if _, err := parseFlags(flags, args); err != nil {
+		return flagsExitCode(err)
+	}
+	if *schemaPath == "" || *packageName == "" || *typeName == "" {
+		fmt.Fprintln(stderr, "tableschema-validator generate: --schema, --package and --type are required")
+		flags.Usage()
+		return exitError
+	}
+
+	tableSchema, err := schemaFlags.load(*schemaPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "tableschema-validator generate: %s\n", err.Error())
+		return exitError
+	}
+
+	code, err := codegen.Generate(tableSchema, codegen.Options{Package: *packageName, TypeName: *typeName, Source: *schemaPath})
+	if err != nil {
+		fmt.Fprintf(stderr, "tableschema-validator generate: %s\n", err.Error())
+		return exitError
+	}
+
+	if *output == "" {
+		_, err = stdout.Write(code)
+	} else {
+		err = os.WriteFile(*output, code, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "tableschema-validator generate: %s\n", err.Error())
+		return exitError
+	}
+	return exitValid
+}
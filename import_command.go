@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"tableschema-validator/ddl"
+	"tableschema-validator/jsonschema"
+	"tableschema-validator/schema"
+)
+
+func runImport(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("import", stderr)
+	format := flags.String("format", "", `format to import from: "sql" or "jsonschema" (required)`)
+	table := flags.String("table", "", "table to import, if the SQL creates more than one")
+	output := flags.String("output", "", "file to write the schema to, in YAML if it ends in .yaml or .yml (default stdout)")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: tableschema-validator import --format format [flags] [file]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Imports a schema from another format: a CREATE TABLE statement, for PostgreSQL, SQLite or MySQL, or a")
+		fmt.Fprintln(stderr, "JSON Schema of an object, whose properties are the fields.")
+		fmt.Fprintln(stderr, "Reads stdin if no file is given. Anything that couldn't be translated into the schema is listed on stderr.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+
+	paths, err := parseFlags(flags, args)
+	if err != nil {
+		return flagsExitCode(err)
+	}
+	if len(paths) > 1 {
+		fmt.Fprintln(stderr, "tableschema-validator import: expected at most one file")
+		flags.Usage()
+		return exitError
+	}
+	if *format != "sql" && *format != "jsonschema" {
+		fmt.Fprintf(stderr, "tableschema-validator import: unknown format %q, expected sql or jsonschema\n", *format)
+		return exitError
+	}
+
+	name := "stdin"
+	var data []byte
+	if len(paths) == 1 {
+		name = paths[0]
+		data, err = os.ReadFile(paths[0])
+	} else {
+		data, err = io.ReadAll(stdin)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "tableschema-validator import: %s\n", err.Error())
+		return exitError
+	}
+
+	var imported schema.Schema
+	var ok bool
+	if *format == "jsonschema" {
+		imported, ok = importJSONSchema(name, data, stderr)
+	} else {
+		imported, ok = importTable(name, string(data), *table, stderr)
+	}
+	if !ok {
+		return exitError
+	}
+	return writeSchema("import", imported, *output, stdout, stderr)
+}
+
+// importTable returns the schema of the table called table, or of the only table, created by sql, read from
+// the file called name. Anything that couldn't be translated is written to stderr. ok is false if there is
+// no such table.
+func importTable(name string, sql string, table string, stderr io.Writer) (imported schema.Schema, ok bool) {
+	tables, err := ddl.ParseCreateTables(sql)
+	if err != nil {
+		fmt.Fprintf(stderr, "tableschema-validator import: %s: %s\n", name, err.Error())
+		return schema.Schema{}, false
+	}
+	var found *ddl.Table
+	var names []string
+	for index := range tables {
+		names = append(names, tables[index].Name)
+		if table == "" && len(tables) == 1 || strings.EqualFold(tables[index].Name, table) {
+			found = &tables[index]
+		}
+	}
+	switch {
+	case len(tables) == 0:
+		fmt.Fprintf(stderr, "tableschema-validator import: %s: no CREATE TABLE statement found\n", name)
+		return schema.Schema{}, false
+	case found == nil && table == "":
+		fmt.Fprintf(stderr, "tableschema-validator import: %s: creates the tables %s, choose one with --table\n", name, strings.Join(names, ", "))
+		return schema.Schema{}, false
+	case found == nil:
+		fmt.Fprintf(stderr, "tableschema-validator import: %s: has no table %s, only %s\n", name, table, strings.Join(names, ", "))
+		return schema.Schema{}, false
+	}
+
+	for _, untranslated := range found.Untranslated {
+		location := found.Name
+		if untranslated.Column != "" {
+			location += "." + untranslated.Column
+		}
+		fmt.Fprintf(stderr, "tableschema-validator import: %s: not translated: %s: %s\n", location, untranslated.SQL, untranslated.Reason)
+	}
+	return found.Schema, true
+}
+
+// importJSONSchema returns the schema of the rows described by data, a JSON Schema read from the file called
+// name. Anything that couldn't be translated is written to stderr.
+func importJSONSchema(name string, data []byte, stderr io.Writer) (imported schema.Schema, ok bool) {
+	imported, warnings, err := jsonschema.Import(data)
+	for _, warning := range warnings {
+		fmt.Fprintf(stderr, "tableschema-validator import: %s#%s: %s\n", name, warning.Path, warning.Message)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "tableschema-validator import: %s: %s\n", name, err.Error())
+		return schema.Schema{}, false
+	}
+	return imported, true
+}
+
+// writeSchema writes tableSchema's descriptor to the file at path, in YAML if its extension is .yaml or .yml
+// and json otherwise, or as indented json to stdout if path is "", returning the exit code of the command
+// called name.
+func writeSchema(name string, tableSchema schema.Schema, path string, stdout io.Writer, stderr io.Writer) int {
+	var err error
+	if path == "" {
+		var descriptor []byte
+		descriptor, err = json.MarshalIndent(tableSchema, "", "  ")
+		if err == nil {
+			_, err = stdout.Write(append(descriptor, '\n'))
+		}
+	} else {
+		err = schema.SaveSchema(path, tableSchema)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "tableschema-validator %s: %s\n", name, err.Error())
+		return exitError
+	}
+	return exitValid
+}
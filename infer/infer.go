@@ -0,0 +1,299 @@
+// Package infer guesses a schema from a table's data, for when writing one by hand would be tedious.
+// Each column is given the most specific type that its values fit, and the constraints that its values
+// all meet, which makes the result a starting point to be checked rather than a finished schema.
+package infer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"tableschema-validator/schema"
+	"tableschema-validator/source"
+	"tableschema-validator/validate"
+)
+
+// Options control how much of a table is read and how sure Infer has to be before it picks a type or
+// suggests a constraint.
+type Options struct {
+	// SampleSize is the number of data rows read. 0 means every row is read.
+	SampleSize int
+	// Confidence is the share, from 0 to 1, of a column's non-empty values that must be of a type for the
+	// column to be given that type. Below 1, a column with a few stray values, e.g. "n/a" in a column of
+	// numbers, is still given the type of the rest. 0 means the Confidence of DefaultOptions.
+	Confidence float64
+	// MaxEnumValues is the most distinct values a string column can have for it to be given an enum
+	// constraint. 0 means enums aren't suggested.
+	MaxEnumValues int
+}
+
+// DefaultOptions returns Options which read the first 1000 rows, pick a type when 9 in 10 values fit it,
+// and suggest enums of up to 10 values.
+func DefaultOptions() Options {
+	return Options{SampleSize: 1000, Confidence: 0.9, MaxEnumValues: 10}
+}
+
+// The types a column can be inferred as, from most to least specific. A column is given the first type
+// that enough of its values fit.
+var types = []struct {
+	name string
+	fits func(value string) bool
+}{
+	{name: "boolean", fits: isBoolean},
+	{name: "integer", fits: fitsConstraint(validate.EnforceIntegerConstraint)},
+	{name: "number", fits: fitsConstraint(validate.EnforceNumberConstraint)},
+	{name: "date", fits: fitsConstraint(validate.EnforceDateConstraint)},
+	{name: "datetime", fits: fitsConstraint(validate.EnforceDateTimeConstraint)},
+}
+
+// isBoolean reports whether value is a boolean written as a word. 1 and 0 are valid booleans too, but a
+// column of them is more likely to be numbers.
+func isBoolean(value string) bool {
+	return value != "1" && value != "0" && schema.IsBoolean(value)
+}
+
+// fitsConstraint turns the function that validates a type into one that reports whether a value fits it,
+// so that a column is only given a type that validation will accept.
+func fitsConstraint(enforce func(header string, field string) (validate.CellValidationResult, error)) func(string) bool {
+	return func(value string) bool {
+		result, err := enforce("", value)
+		return err == nil && result.IsValid()
+	}
+}
+
+// column collects what is known about a column's values.
+type column struct {
+	name     string
+	empty    int
+	values   []string
+	distinct map[string]int
+}
+
+func (column *column) add(value string) {
+	if value == "" {
+		column.empty++
+		return
+	}
+	column.values = append(column.values, value)
+	column.distinct[value]++
+}
+
+// inferType returns the first type that at least confidence of the column's values fit, or "string".
+func (column *column) inferType(confidence float64) string {
+	if len(column.values) == 0 {
+		return "string"
+	}
+	for _, candidate := range types {
+		fits := 0
+		for _, value := range column.values {
+			if candidate.fits(value) {
+				fits++
+			}
+		}
+		if float64(fits)/float64(len(column.values)) >= confidence {
+			return candidate.name
+		}
+	}
+	return "string"
+}
+
+func (column *column) required() schema.RequiredConstraint {
+	if column.empty == 0 && len(column.values) > 0 {
+		return schema.RequiredConstraint{Selected: true, Value: true}
+	}
+	return schema.RequiredConstraint{}
+}
+
+// unique is suggested if every value is different. Empty cells count as values when checking uniqueness,
+// so a column with more than one can't be unique.
+func (column *column) unique() schema.UniqueContraint {
+	if len(column.values) > 1 && len(column.distinct) == len(column.values) && column.empty <= 1 {
+		return schema.UniqueContraint{Selected: true, Value: true}
+	}
+	return schema.UniqueContraint{}
+}
+
+// enum is suggested if there are few distinct values, each of which appears at least twice on average, so
+// that a column of names that happens to be short isn't mistaken for a set of categories.
+func (column *column) enum(maxValues int) schema.EnumConstraint {
+	if maxValues == 0 || len(column.distinct) == 0 || len(column.distinct) > maxValues || len(column.values) < 2*len(column.distinct) {
+		return schema.EnumConstraint{}
+	}
+	var values []string
+	for value := range column.distinct {
+		values = append(values, value)
+	}
+	slices.Sort(values)
+	return schema.EnumConstraint{Selected: true, Value: values}
+}
+
+// bounds returns the smallest and largest of the column's values that are numbers, rounded outwards to
+// integers since that is what min and max constraints hold. The values of an integer column are read as
+// integers, so that those too big to be exact as floats keep their value. ok is false if there are none, or
+// they are too big for a constraint.
+func (column *column) bounds(fieldType string) (minimum int64, maximum int64, ok bool) {
+	if fieldType == "integer" {
+		for _, value := range column.values {
+			number, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			if !ok {
+				minimum, maximum, ok = number, number, true
+			}
+			minimum, maximum = min(minimum, number), max(maximum, number)
+		}
+		return minimum, maximum, ok
+	}
+
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, value := range column.values {
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			continue
+		}
+		low, high = min(low, number), max(high, number)
+	}
+	if low > high || low < math.MinInt64 || high >= math.MaxInt64 {
+		return 0, 0, false
+	}
+	return int64(math.Floor(low)), int64(math.Ceil(high)), true
+}
+
+func (column *column) minMax(fieldType string) (schema.MinConstraint, schema.MaxConstraint) {
+	minimum, maximum, ok := column.bounds(fieldType)
+	if !ok {
+		return schema.MinConstraint{}, schema.MaxConstraint{}
+	}
+	return schema.MinConstraint{Selected: true, Value: minimum}, schema.MaxConstraint{Selected: true, Value: maximum}
+}
+
+// addField adds a field for column to fields, with the type and constraints that the column's values suggest.
+func (column *column) addField(fields *schema.Fields, options Options) {
+	base := schema.FieldBase{Name: column.name}
+
+	switch column.inferType(options.Confidence) {
+	case "boolean":
+		fields.BooleanFields = append(fields.BooleanFields, schema.BooleanField{
+			FieldBase:   base,
+			Constraints: schema.BooleanConstraints{Required: column.required()},
+		})
+	case "integer":
+		minimum, maximum := column.minMax("integer")
+		fields.IntegerFields = append(fields.IntegerFields, schema.IntegerField{
+			FieldBase:   base,
+			Constraints: schema.IntegerConstraints{Required: column.required(), Unique: column.unique(), Min: minimum, Max: maximum},
+		})
+	case "number":
+		minimum, maximum := column.minMax("number")
+		fields.NumberFields = append(fields.NumberFields, schema.NumberField{
+			FieldBase:   base,
+			Constraints: schema.NumberConstraints{Required: column.required(), Unique: column.unique(), Min: minimum, Max: maximum},
+		})
+	case "date":
+		fields.DateFields = append(fields.DateFields, schema.DateField{
+			FieldBase:   base,
+			Constraints: schema.DateConstraints{Required: column.required(), Unique: column.unique()},
+		})
+	case "datetime":
+		fields.DateTimeFields = append(fields.DateTimeFields, schema.DateTimeField{
+			FieldBase:   base,
+			Constraints: schema.DateTimeConstraints{Required: column.required(), Unique: column.unique()},
+		})
+	default:
+		enum := column.enum(options.MaxEnumValues)
+		unique := column.unique()
+		if enum.Selected {
+			unique = schema.UniqueContraint{}
+		}
+		fields.StringFields = append(fields.StringFields, schema.StringField{
+			FieldBase:   base,
+			Constraints: schema.StringConstraints{Required: column.required(), Unique: unique, Enum: enum},
+		})
+	}
+}
+
+// checkDistinct returns an error if a name is in names more than once, since the columns with the same name
+// would be given the same field, which the values of neither might fit.
+func checkDistinct(names []string) error {
+	for index, name := range names {
+		if slices.Contains(names[:index], name) {
+			return fmt.Errorf("there is more than one column named %q, so each can't be given a field; rename all but one of them", name)
+		}
+	}
+	return nil
+}
+
+// Infer reads up to options.SampleSize rows of table and returns a schema with a field for each of its
+// columns. Columns are named by the table's header, or field1, field2 and so on if it has none. It's an error
+// for two columns to have the same name.
+func Infer(table source.Table, options Options) (schema.Schema, error) {
+	if options.Confidence == 0 {
+		options.Confidence = DefaultOptions().Confidence
+	}
+	header, err := table.Header()
+	if err != nil {
+		return schema.Schema{}, err
+	}
+
+	var columns []*column
+	columnsByName := make(map[string]*column)
+	columnNamed := func(name string) *column {
+		if existing, ok := columnsByName[name]; ok {
+			return existing
+		}
+		created := &column{name: name, distinct: make(map[string]int)}
+		columns = append(columns, created)
+		columnsByName[name] = created
+		return created
+	}
+	if err := checkDistinct(header); err != nil {
+		return schema.Schema{}, err
+	}
+	for _, name := range header {
+		columnNamed(name)
+	}
+
+	rows := 0
+	for options.SampleSize == 0 || rows < options.SampleSize {
+		record, err := table.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return schema.Schema{}, err
+		}
+		rows++
+
+		names := header
+		if record.Keys != nil {
+			if err := checkDistinct(record.Keys); err != nil {
+				return schema.Schema{}, err
+			}
+			names = record.Keys
+		}
+		for index, cell := range record.Cells {
+			name := "field" + strconv.Itoa(index+1)
+			if index < len(names) {
+				name = names[index]
+			}
+			columnNamed(name).add(cell)
+		}
+		// a row with fewer cells than the header has empty cells at the end, as it does when it is validated
+		for index := len(record.Cells); index < len(names); index++ {
+			columnNamed(names[index]).add("")
+		}
+	}
+
+	var fields schema.Fields
+	for _, column := range columns {
+		// a column that a keyed row (e.g. a JSON object) didn't have was empty in that row
+		column.empty += rows - column.empty - len(column.values)
+		column.addField(&fields, options)
+		fields.Order = append(fields.Order, column.name)
+	}
+
+	return schema.MakeSchema(schema.SchemaOptions{Fields: fields}), nil
+}
@@ -0,0 +1,127 @@
+package infer
+
+import (
+	"strings"
+	"tableschema-validator/schema"
+	"tableschema-validator/source"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestInfer(t *testing.T) {
+	data := "id,score,active,joined,updated,size,note\n" +
+		"1,2.5,true,2024-01-01,2024-01-01T09:00:00Z,S,first\n" +
+		"2,3,false,2024-02-01,2024-01-02T09:00:00Z,M,\n" +
+		"3,-1.25,TRUE,2024-03-01,2024-01-03T09:00:00Z,S,third\n" +
+		"4,10,False,2024-04-01,2024-01-04T09:00:00Z,M,fourth\n"
+
+	got, err := Infer(source.NewCSVReader(strings.NewReader(data), source.DefaultDialect()), DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	required := schema.RequiredConstraint{Selected: true, Value: true}
+	unique := schema.UniqueContraint{Selected: true, Value: true}
+	want := schema.MakeSchema(schema.SchemaOptions{Fields: schema.Fields{
+		StringFields: []schema.StringField{
+			{FieldBase: schema.FieldBase{Name: "size"}, Constraints: schema.StringConstraints{Required: required, Enum: schema.EnumConstraint{Selected: true, Value: []string{"M", "S"}}}},
+			{FieldBase: schema.FieldBase{Name: "note"}, Constraints: schema.StringConstraints{Unique: unique}},
+		},
+		NumberFields: []schema.NumberField{
+			{FieldBase: schema.FieldBase{Name: "score"}, Constraints: schema.NumberConstraints{Required: required, Unique: unique, Min: schema.MinConstraint{Selected: true, Value: -2}, Max: schema.MaxConstraint{Selected: true, Value: 10}}},
+		},
+		BooleanFields: []schema.BooleanField{
+			{FieldBase: schema.FieldBase{Name: "active"}, Constraints: schema.BooleanConstraints{Required: required}},
+		},
+		IntegerFields: []schema.IntegerField{
+			{FieldBase: schema.FieldBase{Name: "id"}, Constraints: schema.IntegerConstraints{Required: required, Unique: unique, Min: schema.MinConstraint{Selected: true, Value: 1}, Max: schema.MaxConstraint{Selected: true, Value: 4}}},
+		},
+		DateFields: []schema.DateField{
+			{FieldBase: schema.FieldBase{Name: "joined"}, Constraints: schema.DateConstraints{Required: required, Unique: unique}},
+		},
+		DateTimeFields: []schema.DateTimeField{
+			{FieldBase: schema.FieldBase{Name: "updated"}, Constraints: schema.DateTimeConstraints{Required: required, Unique: unique}},
+		},
+		Order: []string{"id", "score", "active", "joined", "updated", "size", "note"},
+	}})
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestInferConfidence(t *testing.T) {
+	data := "amount\n1\n2\n3\nn/a\n"
+
+	testCases := []struct {
+		confidence float64
+		wantType   string
+	}{
+		{confidence: 1, wantType: "string"},
+		{confidence: 0.75, wantType: "integer"},
+		// 0 is the default, so not every type fits
+		{confidence: 0, wantType: "string"},
+	}
+
+	for _, testCase := range testCases {
+		options := DefaultOptions()
+		options.Confidence = testCase.confidence
+		got, err := Infer(source.NewCSVReader(strings.NewReader(data), source.DefaultDialect()), options)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var gotType string
+		switch {
+		case len(got.Fields.StringFields) == 1:
+			gotType = got.Fields.StringFields[0].FieldType
+		case len(got.Fields.IntegerFields) == 1:
+			gotType = got.Fields.IntegerFields[0].FieldType
+		}
+		if gotType != testCase.wantType {
+			t.Errorf("confidence %v: expected type %s, got %s", testCase.confidence, testCase.wantType, gotType)
+		}
+	}
+}
+
+func TestInferSampleSize(t *testing.T) {
+	data := "value\n1\n2\nthree\n"
+
+	options := DefaultOptions()
+	options.Confidence = 1
+	options.SampleSize = 2
+	got, err := Infer(source.NewCSVReader(strings.NewReader(data), source.DefaultDialect()), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Fields.IntegerFields) != 1 {
+		t.Errorf("expected the rows after the sample to be ignored, got %+v", got.Fields)
+	}
+}
+
+func TestInferIntegerBounds(t *testing.T) {
+	// the values are too big to be exact as floats
+	data := "id\n9007199254740993\n9007199254740995\n"
+
+	got, err := Infer(source.NewCSVReader(strings.NewReader(data), source.DefaultDialect()), DefaultOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Fields.IntegerFields) != 1 {
+		t.Fatalf("expected an integer field, got %+v", got.Fields)
+	}
+	constraints := got.Fields.IntegerFields[0].Constraints
+	if constraints.Min.Value != 9007199254740993 || constraints.Max.Value != 9007199254740995 {
+		t.Errorf("expected a min of 9007199254740993 and a max of 9007199254740995, got %d and %d", constraints.Min.Value, constraints.Max.Value)
+	}
+}
+
+func TestInferDuplicateHeader(t *testing.T) {
+	data := "a,a\n1,x\n2,\n"
+
+	_, err := Infer(source.NewCSVReader(strings.NewReader(data), source.DefaultDialect()), DefaultOptions())
+	if err == nil || err.Error() != `there is more than one column named "a", so each can't be given a field; rename all but one of them` {
+		t.Errorf("expected an error for the duplicate column, got %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"tableschema-validator/infer"
+)
+
+func runInfer(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	defaults := infer.DefaultOptions()
+
+	flags := newFlagSet("infer", stderr)
+	dataFlags := addSourceFlags(flags)
+	sampleSize := flags.Int("sample", defaults.SampleSize, "number of rows to read (0 to read every row)")
+	confidence := flags.Float64("confidence", defaults.Confidence, "share of a column's values, above 0 and up to 1, that must be of a type for the column to be given it")
+	maxEnumValues := flags.Int("max-enum", defaults.MaxEnumValues, "most distinct values a text column can have to be given an enum constraint (0 for no enums)")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: tableschema-validator infer [flags] [file]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Infers a schema from the data in file, or stdin if there is no file or it is -, and writes its descriptor to stdout.")
+		fmt.Fprintln(stderr, "The schema is a guess from the data it was given, and should be checked before it is used.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+
+	paths, err := parseFlags(flags, args)
+	if err != nil {
+		return flagsExitCode(err)
+	}
+	if len(paths) > 1 {
+		fmt.Fprintln(stderr, "tableschema-validator infer: expected at most one file")
+		return exitError
+	}
+	if *confidence <= 0 || *confidence > 1 {
+		fmt.Fprintf(stderr, "tableschema-validator infer: --confidence must be greater than 0 and at most 1, got %v\n", *confidence)
+		return exitError
+	}
+	path := "-"
+	if len(paths) == 1 {
+		path = paths[0]
+	}
+
+	options, err := dataFlags.options()
+	if err != nil {
+		fmt.Fprintf(stderr, "tableschema-validator infer: %s\n", err.Error())
+		return exitError
+	}
+
+	file, name, err := openSource(path, stdin, options)
+	if err != nil {
+		fmt.Fprintf(stderr, "tableschema-validator infer: %s\n", err.Error())
+		return exitError
+	}
+	defer file.Close()
+
+	inferred, err := infer.Infer(file, infer.Options{SampleSize: *sampleSize, Confidence: *confidence, MaxEnumValues: *maxEnumValues})
+	if err != nil {
+		fmt.Fprintf(stderr, "tableschema-validator infer: %s: %s\n", name, err.Error())
+		return exitError
+	}
+
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(inferred); err != nil {
+		fmt.Fprintf(stderr, "tableschema-validator infer: %s\n", err.Error())
+		return exitError
+	}
+	return exitValid
+}
@@ -0,0 +1,511 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"regexp/syntax"
+	"slices"
+	"strconv"
+	"strings"
+	"tableschema-validator/schema"
+)
+
+// A Warning is part of a JSON Schema that Import couldn't translate into the schema, or could only translate
+// loosely.
+type Warning struct {
+	// Path is a JSON pointer to the part of the JSON Schema, e.g. /properties/age/multipleOf.
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// emailPattern is the pattern of a string of format email: something either side of an @.
+const emailPattern = `[^@\s]+@[^@\s]+`
+
+// rootKeywords are the keywords of the JSON Schema of an object that Import understands, or that don't
+// affect the schema, such as $id.
+var rootKeywords = []string{"$schema", "$id", "$comment", "title", "description", "type", "properties", "required",
+	"additionalProperties", "definitions", "$defs"}
+
+// importer collects the warnings for a JSON Schema as it's translated.
+type importer struct {
+	// document is the whole JSON Schema, for resolving references
+	document any
+	warnings []Warning
+}
+
+func (importer *importer) warn(path string, format string, args ...any) {
+	importer.warnings = append(importer.warnings, Warning{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// pointerToken escapes a key for a JSON pointer.
+func pointerToken(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// resolve follows the $ref of definition, if it has one, to the definition it refers to in the document,
+// returning it and its path. Only references within the document, e.g. #/definitions/email, can be followed;
+// ok is false if definition's can't be.
+func (importer *importer) resolve(definition map[string]any, path string) (resolved map[string]any, resolvedPath string, ok bool) {
+	for followed := 0; ; followed++ {
+		ref, isRef := definition["$ref"].(string)
+		if !isRef {
+			return definition, path, true
+		}
+		if followed == 10 {
+			importer.warn(path+"/$ref", "%s refers to itself, through other references", ref)
+			return nil, "", false
+		}
+		if !strings.HasPrefix(ref, "#") {
+			importer.warn(path+"/$ref", "%s isn't in this document, so can't be followed", ref)
+			return nil, "", false
+		}
+
+		var target any = importer.document
+		for _, token := range strings.Split(strings.TrimPrefix(ref, "#"), "/")[1:] {
+			token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+			object, isObject := target.(map[string]any)
+			if !isObject {
+				target = nil
+				break
+			}
+			target = object[token]
+		}
+		next, isObject := target.(map[string]any)
+		if !isObject {
+			importer.warn(path+"/$ref", "%s doesn't refer to a definition in this document", ref)
+			return nil, "", false
+		}
+		definition, path = next, strings.TrimPrefix(ref, "#")
+	}
+}
+
+// types returns the types of definition, apart from null: a field of any type can be empty.
+func types(definition map[string]any) []string {
+	var nonNull []string
+	switch typed := definition["type"].(type) {
+	case string:
+		nonNull = []string{typed}
+	case []any:
+		for _, value := range typed {
+			if name, ok := value.(string); ok {
+				nonNull = append(nonNull, name)
+			}
+		}
+	}
+	return slices.DeleteFunc(nonNull, func(name string) bool { return name == "null" })
+}
+
+// enumValues returns the values of definition's enum, or of its const as an enum of one, leaving out null.
+func enumValues(definition map[string]any) ([]any, bool) {
+	if constant, ok := definition["const"]; ok {
+		return slices.DeleteFunc([]any{constant}, func(value any) bool { return value == nil }), true
+	}
+	values, ok := definition["enum"].([]any)
+	return slices.DeleteFunc(slices.Clone(values), func(value any) bool { return value == nil }), ok
+}
+
+// valuesType returns the JSON type all of values are, or "" if they're of different types.
+func valuesType(values []any) string {
+	valuesType := ""
+	for _, value := range values {
+		var valueType string
+		switch typed := value.(type) {
+		case string:
+			valueType = "string"
+		case bool:
+			valueType = "boolean"
+		case json.Number:
+			valueType = "number"
+			if _, err := typed.Int64(); err == nil {
+				valueType = "integer"
+			}
+		default:
+			return ""
+		}
+		switch {
+		case valuesType == "" || valuesType == valueType:
+			valuesType = valueType
+		case valuesType == "integer" && valueType == "number" || valuesType == "number" && valueType == "integer":
+			valuesType = "number"
+		default:
+			return ""
+		}
+	}
+	return valuesType
+}
+
+// text returns a JSON scalar as a cell would hold it, e.g. "true" for true.
+func text(value any) (string, bool) {
+	switch typed := value.(type) {
+	case string:
+		return typed, true
+	case bool:
+		return strconv.FormatBool(typed), true
+	case json.Number:
+		return typed.String(), true
+	}
+	return "", false
+}
+
+// wholePattern returns the pattern that a whole value has to match for it to contain a match of the JSON
+// Schema pattern, which only has to match part of it. A pattern anchored at both ends, e.g. ^[A-Z]+$, only
+// needs its anchors removing.
+func wholePattern(pattern string) (string, error) {
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", err
+	}
+	if parsed.Op == syntax.OpConcat && len(parsed.Sub) >= 2 &&
+		parsed.Sub[0].Op == syntax.OpBeginText && parsed.Sub[len(parsed.Sub)-1].Op == syntax.OpEndText {
+		inner := &syntax.Regexp{Op: syntax.OpConcat, Flags: parsed.Flags, Sub: parsed.Sub[1 : len(parsed.Sub)-1]}
+		if len(inner.Sub) == 1 {
+			inner = inner.Sub[0]
+		}
+		if inner.Op == syntax.OpCapture {
+			inner = inner.Sub[0]
+		}
+		return inner.String(), nil
+	}
+	return `(?s:.*)(?:` + pattern + `)(?s:.*)`, nil
+}
+
+// startField starts a field of fieldType called name.
+func startField(builder *schema.Builder, fieldType string, name string) {
+	map[string]func(string) *schema.Builder{
+		"string":   builder.String,
+		"number":   builder.Number,
+		"integer":  builder.Integer,
+		"boolean":  builder.Boolean,
+		"date":     builder.Date,
+		"datetime": builder.DateTime,
+		"list":     builder.List,
+	}[fieldType](name)
+}
+
+// fieldType returns the type of field for definition, or "" if it can't be a field, warning about anything
+// it can't be translated into.
+func (importer *importer) fieldType(name string, definition map[string]any, path string) string {
+	jsonTypes := types(definition)
+	if len(jsonTypes) == 0 {
+		if enum, ok := enumValues(definition); ok && valuesType(enum) != "" {
+			jsonTypes = []string{valuesType(enum)}
+		} else {
+			importer.warn(path, "%s has no type, so it's a string field", name)
+			return "string"
+		}
+	}
+	if len(jsonTypes) > 1 {
+		importer.warn(path+"/type", "%s can be any of %s, so it's a string field", name, strings.Join(jsonTypes, ", "))
+		return "string"
+	}
+
+	switch jsonTypes[0] {
+	case "string":
+		format, _ := definition["format"].(string)
+		switch format {
+		case "":
+			return "string"
+		case "date":
+			return "date"
+		case "date-time":
+			return "datetime"
+		case "email":
+			return "string"
+		}
+		importer.warn(path+"/format", "%s has the format %s, which no type of field has, so it's a string field", name, format)
+		return "string"
+	case "integer", "number", "boolean":
+		return jsonTypes[0]
+	case "array":
+		return "list"
+	}
+	importer.warn(path, "%s is a JSON %s, which can't be a field", name, jsonTypes[0])
+	return ""
+}
+
+// fieldKeywords are the keywords of a property that Import translates into a field, or that don't affect it.
+var fieldKeywords = []string{"type", "format", "title", "description", "examples", "enum", "const", "pattern", "minLength", "maxLength",
+	"minItems", "maxItems", "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "$comment"}
+
+// bound returns the integer bound of keyword, an inclusive or exclusive minimum or maximum, for a field of
+// type fieldType. ok is false if the field has no such bound, or one its constraints can't express.
+func (importer *importer) bound(name string, fieldType string, definition map[string]any, keyword string, path string) (int64, bool) {
+	value, isNumber := definition[keyword].(json.Number)
+	if !isNumber {
+		if _, present := definition[keyword]; present {
+			importer.warn(path+"/"+keyword, "%s has a %s that isn't a number", name, keyword)
+		}
+		return 0, false
+	}
+	float, err := value.Float64()
+	if err != nil {
+		return 0, false
+	}
+
+	exclusive := strings.HasPrefix(keyword, "exclusive")
+	lower := strings.HasSuffix(keyword, "inimum")
+	bound := float
+	switch {
+	// an integer field's values are whole, so a bound of either kind is the first whole number it allows
+	case fieldType == "integer" && lower && exclusive:
+		bound = math.Floor(float) + 1
+	case fieldType == "integer" && lower:
+		bound = math.Ceil(float)
+	case fieldType == "integer" && exclusive:
+		bound = math.Ceil(float) - 1
+	case fieldType == "integer":
+		bound = math.Floor(float)
+	case exclusive || float != math.Trunc(float):
+		importer.warn(path+"/"+keyword, "%s has a %s of %s, which a min or max constraint can't express", name, keyword, value)
+		return 0, false
+	}
+	if bound < math.MinInt64 || bound >= math.MaxInt64 {
+		importer.warn(path+"/"+keyword, "%s has a %s of %s, which is too big for a constraint", name, keyword, value)
+		return 0, false
+	}
+	return int64(bound), true
+}
+
+// addField adds a field for the property called name, whose definition is at path, to builder.
+func (importer *importer) addField(builder *schema.Builder, name string, definition map[string]any, path string, required bool) {
+	definition, path, ok := importer.resolve(definition, path)
+	if !ok {
+		return
+	}
+	fieldType := importer.fieldType(name, definition, path)
+	if fieldType == "" {
+		return
+	}
+
+	startField(builder, fieldType, name)
+	if required {
+		builder.Required()
+	}
+	if title, ok := definition["title"].(string); ok {
+		builder.Title(title)
+	}
+	if description, ok := definition["description"].(string); ok {
+		builder.Description(description)
+	}
+	if examples, ok := definition["examples"].([]any); ok && len(examples) > 0 {
+		if example, ok := text(examples[0]); ok {
+			builder.Example(example)
+		}
+	}
+
+	if enum, ok := enumValues(definition); ok {
+		enumKey := "enum"
+		if _, isConst := definition["const"]; isConst {
+			enumKey = "const"
+		}
+		var values []string
+		for _, value := range enum {
+			if text, ok := text(value); ok && !slices.Contains(values, text) {
+				values = append(values, text)
+			}
+		}
+		switch {
+		case fieldType != "string" && fieldType != "boolean":
+			importer.warn(path+"/"+enumKey, "%s is of type %s, which has no enum constraint", name, fieldType)
+		case len(values) > 0:
+			builder.Enum(values...)
+		}
+	}
+
+	pattern, hasPattern := definition["pattern"].(string)
+	if format, _ := definition["format"].(string); format == "email" && fieldType == "string" {
+		if hasPattern {
+			importer.warn(path+"/format", "%s has a pattern as well as the format email, so only the pattern is kept", name)
+		} else {
+			builder.Pattern(emailPattern)
+		}
+	}
+	if hasPattern {
+		whole, err := wholePattern(pattern)
+		if err == nil {
+			_, err = regexp.Compile(whole)
+		}
+		switch {
+		// JSON Schema ignores a pattern for values that aren't strings
+		case fieldType != "string":
+			importer.warn(path+"/pattern", "%s is of type %s, which has no pattern constraint", name, fieldType)
+		case err != nil:
+			importer.warn(path+"/pattern", "%s has a pattern that Go can't read: %s", name, err.Error())
+		default:
+			builder.Pattern(whole)
+		}
+	}
+
+	lengths := map[string]func(int64) *schema.Builder{"minLength": builder.MinLength, "maxLength": builder.MaxLength}
+	if fieldType == "list" {
+		lengths = map[string]func(int64) *schema.Builder{"minItems": builder.MinLength, "maxItems": builder.MaxLength}
+	}
+	for _, keyword := range []string{"minLength", "maxLength", "minItems", "maxItems"} {
+		value, present := definition[keyword]
+		if !present {
+			continue
+		}
+		set, ok := lengths[keyword]
+		if !ok || fieldType != "string" && fieldType != "list" {
+			importer.warn(path+"/"+keyword, "%s is of type %s, which has no %s constraint", name, fieldType, keyword)
+			continue
+		}
+		number, _ := value.(json.Number)
+		length, err := number.Int64()
+		if err != nil {
+			importer.warn(path+"/"+keyword, "%s has a %s that isn't an integer", name, keyword)
+			continue
+		}
+		set(length)
+	}
+
+	// a value has to be within both bounds of a side if both are given, so only the tighter one is kept
+	var minimum, maximum int64
+	var hasMinimum, hasMaximum bool
+	for _, keyword := range []string{"minimum", "exclusiveMinimum", "maximum", "exclusiveMaximum"} {
+		if _, present := definition[keyword]; !present {
+			continue
+		}
+		if fieldType != "integer" && fieldType != "number" {
+			importer.warn(path+"/"+keyword, "%s is of type %s, which has no %s constraint", name, fieldType, keyword)
+			continue
+		}
+		bound, ok := importer.bound(name, fieldType, definition, keyword, path)
+		switch {
+		case !ok:
+		case strings.HasSuffix(keyword, "inimum"):
+			if !hasMinimum || bound > minimum {
+				minimum, hasMinimum = bound, true
+			}
+		case !hasMaximum || bound < maximum:
+			maximum, hasMaximum = bound, true
+		}
+	}
+	if hasMinimum {
+		builder.Min(minimum)
+	}
+	if hasMaximum {
+		builder.Max(maximum)
+	}
+
+	for _, keyword := range sortedKeys(definition) {
+		switch {
+		case slices.Contains(fieldKeywords, keyword):
+		case keyword == "default":
+			importer.warn(path+"/default", "a schema has no default values")
+		case keyword == "properties" || keyword == "items":
+			importer.warn(path+"/"+keyword, "%s has nested definitions, which a field can't have", name)
+		default:
+			importer.warn(path+"/"+pointerToken(keyword), "%s isn't supported", keyword)
+		}
+	}
+}
+
+func sortedKeys(object map[string]any) []string {
+	var keys []string
+	for key := range object {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// propertyNames returns the keys of the JSON object properties in the order they're written.
+func propertyNames(properties json.RawMessage) ([]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(properties))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, errors.New("properties isn't an object")
+	}
+	var names []string
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, token.(string))
+		var skipped json.RawMessage
+		if err := decoder.Decode(&skipped); err != nil {
+			return nil, err
+		}
+	}
+	return names, nil
+}
+
+// Import translates a JSON Schema for a flat JSON object, e.g. a record, into a schema with a field for each of
+// its properties, in the order they're written. A property's type is the field's type, with a string of format
+// date or date-time a date or datetime field, and an array a list field. A required property is a required
+// field. Title, description, the first of the examples, enum (or const), pattern, minLength, maxLength,
+// minimum and maximum are translated into the field's constraints, as are minItems and maxItems of an array
+// and an exclusiveMinimum or exclusiveMaximum of an integer. A string of format email is given a pattern that
+// checks for an @. References within the document, e.g. to #/definitions/email, are followed; nothing else is
+// read, so a reference to another document is a warning.
+//
+// The Warnings list everything else, e.g. a nested object, which can't be a field, or a multipleOf. It's an
+// error for data not to be well-formed JSON, or for it not to describe an object.
+func Import(data []byte) (schema.Schema, []Warning, error) {
+	importer := &importer{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&importer.document); err != nil {
+		return schema.Schema{}, nil, fmt.Errorf("the JSON Schema isn't well-formed JSON: %w", err)
+	}
+	root, ok := importer.document.(map[string]any)
+	if !ok {
+		return schema.Schema{}, nil, errors.New("the JSON Schema isn't an object")
+	}
+	properties, _ := root["properties"].(map[string]any)
+	if jsonTypes := types(root); len(jsonTypes) > 0 && !slices.Equal(jsonTypes, []string{"object"}) || properties == nil {
+		return schema.Schema{}, nil, errors.New("the JSON Schema doesn't describe an object with properties")
+	}
+
+	var raw struct {
+		Properties json.RawMessage `json:"properties"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return schema.Schema{}, nil, err
+	}
+	names, err := propertyNames(raw.Properties)
+	if err != nil {
+		return schema.Schema{}, nil, err
+	}
+
+	var required []string
+	requiredValues, _ := root["required"].([]any)
+	for _, value := range requiredValues {
+		if name, ok := value.(string); ok {
+			required = append(required, name)
+		}
+	}
+
+	builder := schema.New()
+	for _, name := range names {
+		path := "/properties/" + pointerToken(name)
+		definition, ok := properties[name].(map[string]any)
+		if !ok {
+			importer.warn(path, "%s is defined as true or false rather than with keywords, so it can't be a field", name)
+			continue
+		}
+		importer.addField(builder, name, definition, path, slices.Contains(required, name))
+	}
+
+	for index, name := range required {
+		if _, ok := properties[name]; !ok {
+			importer.warn(fmt.Sprintf("/required/%d", index), "%s is required, but isn't a property", name)
+		}
+	}
+	for _, keyword := range sortedKeys(root) {
+		if !slices.Contains(rootKeywords, keyword) {
+			importer.warn("/"+pointerToken(keyword), "%s isn't supported", keyword)
+		}
+	}
+
+	tableSchema, err := builder.Build()
+	if err != nil {
+		return schema.Schema{}, importer.warnings, err
+	}
+	return tableSchema, importer.warnings, nil
+}
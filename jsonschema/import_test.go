@@ -0,0 +1,163 @@
+package jsonschema
+
+import (
+	"strings"
+	"tableschema-validator/schema"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestImport(t *testing.T) {
+	document := `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "account",
+  "type": "object",
+  "properties": {
+    "id": {"type": "integer", "exclusiveMinimum": 0, "title": "ID", "examples": [42]},
+    "email": {"$ref": "#/definitions/email"},
+    "code": {"type": "string", "pattern": "^[A-Z]{3}$", "minLength": 3},
+    "size": {"enum": ["S", "M", null]},
+    "score": {"type": ["number", "null"], "minimum": 0, "maximum": 9.5, "multipleOf": 0.5},
+    "active": {"type": "boolean", "const": true},
+    "joined": {"type": "string", "format": "date", "default": "2024-01-01"},
+    "updated": {"type": "string", "format": "date-time"},
+    "tags": {"type": "array", "items": {"type": "string"}, "maxItems": 3},
+    "address": {"type": "object", "properties": {"city": {"type": "string"}}},
+    "website": {"type": "string", "format": "uri"},
+    "note": {"type": "string", "pattern": "urgent"},
+    "starts": {"type": "string", "format": "date", "pattern": "^2024"},
+    "rank": {"type": "integer", "pattern": "^1", "minimum": 5, "exclusiveMinimum": 0, "maximum": 10, "exclusiveMaximum": 8}
+  },
+  "required": ["id", "email", "phone"],
+  "definitions": {
+    "email": {"type": "string", "format": "email", "description": "Where to write to"}
+  },
+  "additionalProperties": false,
+  "allOf": []
+}`
+
+	got, warnings, err := Import([]byte(document))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := schema.New().
+		Integer("id").Required().Title("ID").Example("42").Min(1).
+		String("email").Required().Description("Where to write to").Pattern(emailPattern).
+		String("code").Pattern("[A-Z]{3}").MinLength(3).
+		String("size").Enum("S", "M").
+		Number("score").Min(0).
+		Boolean("active").Enum("true").
+		Date("joined").
+		DateTime("updated").
+		List("tags").MaxLength(3).
+		String("website").
+		String("note").Pattern("(?s:.*)(?:urgent)(?s:.*)").
+		Date("starts").
+		Integer("rank").Min(5).Max(7).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	wantWarnings := []Warning{
+		{Path: "/properties/score/maximum", Message: "score has a maximum of 9.5, which a min or max constraint can't express"},
+		{Path: "/properties/score/multipleOf", Message: "multipleOf isn't supported"},
+		{Path: "/properties/joined/default", Message: "a schema has no default values"},
+		{Path: "/properties/tags/items", Message: "tags has nested definitions, which a field can't have"},
+		{Path: "/properties/address", Message: "address is a JSON object, which can't be a field"},
+		{Path: "/properties/website/format", Message: "website has the format uri, which no type of field has, so it's a string field"},
+		{Path: "/properties/starts/pattern", Message: "starts is of type date, which has no pattern constraint"},
+		{Path: "/properties/rank/pattern", Message: "rank is of type integer, which has no pattern constraint"},
+		{Path: "/required/2", Message: "phone is required, but isn't a property"},
+		{Path: "/allOf", Message: "allOf isn't supported"},
+	}
+	if diff := cmp.Diff(wantWarnings, warnings); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestImportRoundTrip(t *testing.T) {
+	// a JSON Schema generated from a schema is imported as the schema, apart from what a row can't say
+	tableSchema, err := schema.New().
+		Integer("id").Required().Title("ID").Min(1).Max(100).
+		String("code").Required().Pattern("[A-Z]{3}").MinLength(1).
+		String("size").Enum("S", "M").MinLength(1).
+		Boolean("active").
+		Date("joined").
+		List("tags").MinLength(1).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generated, err := Generate(tableSchema, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, warnings, err := Import(generated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(tableSchema, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", warnings)
+	}
+}
+
+func TestImportExternalReferences(t *testing.T) {
+	// references to other documents are neither fetched nor an error, however they're written
+	document := `{
+  "type": "object",
+  "properties": {
+    "id": {"type": "integer"},
+    "email": {"$ref": "https://example.com/schemas/email.json"},
+    "name": {"$ref": "common.json#/definitions/name"}
+  }
+}`
+
+	got, warnings, err := Import([]byte(document))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := schema.New().Integer("id").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	wantWarnings := []Warning{
+		{Path: "/properties/email/$ref", Message: "https://example.com/schemas/email.json isn't in this document, so can't be followed"},
+		{Path: "/properties/name/$ref", Message: "common.json#/definitions/name isn't in this document, so can't be followed"},
+	}
+	if diff := cmp.Diff(wantWarnings, warnings); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	testCases := []struct {
+		document string
+		wantErr  string
+	}{
+		{document: `{"type": "object", "properties": {"id": {"type": "integer"}}`, wantErr: "isn't well-formed JSON"},
+		{document: `{"type": "objects", "properties": {}}`, wantErr: "doesn't describe an object with properties"},
+		{document: `{"type": "array", "items": {"type": "string"}}`, wantErr: "doesn't describe an object with properties"},
+		{document: `{"type": "object", "properties": {"address": {"type": "object"}}}`, wantErr: "schema does not match the tableschema profile"},
+	}
+
+	for _, testCase := range testCases {
+		_, _, err := Import([]byte(testCase.document))
+		if err == nil || !strings.Contains(err.Error(), testCase.wantErr) {
+			t.Errorf("%s: expected an error containing %q, got %v", testCase.document, testCase.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+// Package jsonschema turns a schema into a JSON Schema (draft-07) for one of its rows, as a JSON object with a
+// property for each field, so that the same descriptor can check the rows of a file here and single records
+// with JSON Schema tooling elsewhere, e.g. in an API.
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"tableschema-validator/schema"
+)
+
+// Draft07 is the $schema of a JSON Schema written to draft-07.
+const Draft07 = "http://json-schema.org/draft-07/schema#"
+
+// Options are what the JSON Schema is called, and whether it allows properties that aren't fields.
+type Options struct {
+	// ID is the $id of the JSON Schema. It's left out if it's "".
+	ID string
+	// Title is the title of the JSON Schema. It's left out if it's "".
+	Title string
+	// AdditionalProperties allows a row to have properties that aren't fields of the schema.
+	AdditionalProperties bool
+}
+
+// property is the JSON Schema of a field's values.
+type property struct {
+	// Type is a JSON type, or a list of them for a field whose value can be null
+	Type        any    `json:"type"`
+	Format      string `json:"format,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Examples    []any  `json:"examples,omitempty"`
+	Enum        []any  `json:"enum,omitempty"`
+	Pattern     string `json:"pattern,omitempty"`
+	MinLength   *int64 `json:"minLength,omitempty"`
+	MaxLength   *int64 `json:"maxLength,omitempty"`
+	Minimum     *int64 `json:"minimum,omitempty"`
+	Maximum     *int64 `json:"maximum,omitempty"`
+	MinItems    *int64 `json:"minItems,omitempty"`
+	MaxItems    *int64 `json:"maxItems,omitempty"`
+}
+
+// properties are the properties of a row, which are marshalled in the order of the schema's fields rather
+// than the order of a map's keys.
+type properties struct {
+	names  []string
+	values []property
+}
+
+func (properties properties) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteString("{")
+	for index, name := range properties.names {
+		if index > 0 {
+			buffer.WriteString(",")
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(properties.values[index])
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(key)
+		buffer.WriteString(":")
+		buffer.Write(value)
+	}
+	buffer.WriteString("}")
+	return buffer.Bytes(), nil
+}
+
+// document is a JSON Schema for a row.
+type document struct {
+	Schema               string     `json:"$schema"`
+	ID                   string     `json:"$id,omitempty"`
+	Title                string     `json:"title,omitempty"`
+	Type                 string     `json:"type"`
+	Properties           properties `json:"properties"`
+	Required             []string   `json:"required"`
+	AdditionalProperties bool       `json:"additionalProperties"`
+}
+
+// jsonTypes are the JSON types of the values of each type of field, and their formats.
+var jsonTypes = map[string]struct{ jsonType, format string }{
+	"string":   {jsonType: "string"},
+	"number":   {jsonType: "number"},
+	"integer":  {jsonType: "integer"},
+	"boolean":  {jsonType: "boolean"},
+	"date":     {jsonType: "string", format: "date"},
+	"datetime": {jsonType: "string", format: "date-time"},
+	"list":     {jsonType: "array"},
+}
+
+// jsonValue returns text, a value of a field of type fieldType written as it would be in a cell, as the
+// JSON value it would be in a row, e.g. 5 rather than "5" for an integer field. ok is false if text isn't
+// a valid value of the field's type.
+func jsonValue(fieldType string, text string) (value any, ok bool) {
+	switch fieldType {
+	case "integer":
+		integer, err := strconv.ParseInt(text, 10, 64)
+		return integer, err == nil
+	case "number":
+		number, err := strconv.ParseFloat(text, 64)
+		return number, err == nil
+	case "boolean":
+		switch {
+		case schema.IsTrue(text):
+			return true, true
+		case schema.IsFalse(text):
+			return false, true
+		}
+		return nil, false
+	case "list":
+		return nil, false
+	}
+	return text, true
+}
+
+// fieldProperty returns the JSON Schema of the values of field, which can be null if nullable is true. It's an
+// error for an enum value not to be a value of the field's type, since the JSON Schema couldn't have it.
+func fieldProperty(field schema.FieldDescriptor, nullable bool) (property, error) {
+	jsonType := jsonTypes[field.FieldType]
+	result := property{
+		Type:        jsonType.jsonType,
+		Format:      jsonType.format,
+		Title:       field.Title,
+		Description: field.Description,
+	}
+	if nullable {
+		result.Type = []string{jsonType.jsonType, "null"}
+	}
+	if example, ok := jsonValue(field.FieldType, field.Example); ok && field.Example != "" {
+		result.Examples = []any{example}
+	}
+
+	if enum, ok := field.Constraints["enum"].([]string); ok {
+		for _, text := range enum {
+			value, ok := jsonValue(field.FieldType, text)
+			if !ok {
+				return property{}, fmt.Errorf("%s has the enum value %q, which isn't a valid %s", field.Name, text, field.FieldType)
+			}
+			if !containsValue(result.Enum, value) {
+				result.Enum = append(result.Enum, value)
+			}
+		}
+		if nullable {
+			result.Enum = append(result.Enum, nil)
+		}
+	}
+	if pattern, ok := field.Constraints["pattern"].(string); ok {
+		// a JSON Schema pattern can match part of a value, but a schema's has to match all of it
+		result.Pattern = "^(?:" + pattern + ")$"
+	}
+
+	bound := func(constraint string) *int64 {
+		if value, ok := field.Constraints[constraint].(int64); ok {
+			return &value
+		}
+		return nil
+	}
+	if field.FieldType == "list" {
+		result.MinItems, result.MaxItems = bound("minLength"), bound("maxLength")
+	} else {
+		result.MinLength, result.MaxLength = bound("minLength"), bound("maxLength")
+	}
+	result.Minimum, result.Maximum = bound("min"), bound("max")
+
+	// a required string field can't be empty, but a JSON string can be
+	if !nullable && field.FieldType == "string" && (result.MinLength == nil || *result.MinLength < 1) {
+		one := int64(1)
+		result.MinLength = &one
+	}
+
+	return result, nil
+}
+
+func containsValue(values []any, value any) bool {
+	for _, existing := range values {
+		if existing == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Generate returns a JSON Schema, written to draft-07, for a row of tableSchema as a JSON object. Each field
+// is a property of the row, whose JSON type is that of the field's values: an integer field's values are
+// integers, a boolean field's are true or false, a list field's are arrays, and a date or datetime field's
+// are strings of format date or date-time. A required field is a required property; any other can be left
+// out or null, and a required string field's property has a minLength of at least 1, since its value can't be
+// empty. Title, description, example, enum, pattern, minLength, maxLength, min and max are carried over, with
+// a list's lengths as minItems and maxItems. It's an error for an enum value not to be a value of its field's
+// type.
+//
+// unique constraints and keys aren't, since they're about a whole table rather than a row. Note that a
+// date-time has to have a timezone, where a datetime field's value can leave it out.
+func Generate(tableSchema schema.Schema, options Options) ([]byte, error) {
+	fields := tableSchema.Fields.List()
+	if len(fields) == 0 {
+		return nil, errors.New("the schema has no fields")
+	}
+
+	result := document{
+		Schema:               Draft07,
+		ID:                   options.ID,
+		Title:                options.Title,
+		Type:                 "object",
+		Required:             []string{},
+		AdditionalProperties: options.AdditionalProperties,
+	}
+	for _, field := range fields {
+		required, _ := field.Constraints["required"].(bool)
+		if required {
+			result.Required = append(result.Required, field.Name)
+		}
+		property, err := fieldProperty(field, !required)
+		if err != nil {
+			return nil, err
+		}
+		result.Properties.names = append(result.Properties.names, field.Name)
+		result.Properties.values = append(result.Properties.values, property)
+	}
+
+	generated, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(generated, '\n'), nil
+}
@@ -0,0 +1,183 @@
+package jsonschema
+
+import (
+	"tableschema-validator/schema"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func accounts(t *testing.T) schema.Schema {
+	t.Helper()
+	tableSchema, err := schema.New().
+		Integer("id").Title("ID").Example("42").Required().Unique().Min(1).
+		String("code").Description("Account code").Required().Pattern("[A-Z]{3}").
+		String("size").Enum("S", "M").
+		Number("score").Min(0).Max(10).
+		Boolean("active").Enum("true", "TRUE").
+		Date("joined").
+		DateTime("updated").
+		List("tags").MaxLength(3).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tableSchema
+}
+
+func TestGenerate(t *testing.T) {
+	got, err := Generate(accounts(t), Options{Title: "accounts"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "accounts",
+  "type": "object",
+  "properties": {
+    "id": {
+      "type": "integer",
+      "title": "ID",
+      "examples": [
+        42
+      ],
+      "minimum": 1
+    },
+    "code": {
+      "type": "string",
+      "description": "Account code",
+      "pattern": "^(?:[A-Z]{3})$",
+      "minLength": 1
+    },
+    "size": {
+      "type": [
+        "string",
+        "null"
+      ],
+      "enum": [
+        "S",
+        "M",
+        null
+      ]
+    },
+    "score": {
+      "type": [
+        "number",
+        "null"
+      ],
+      "minimum": 0,
+      "maximum": 10
+    },
+    "active": {
+      "type": [
+        "boolean",
+        "null"
+      ],
+      "enum": [
+        true,
+        null
+      ]
+    },
+    "joined": {
+      "type": [
+        "string",
+        "null"
+      ],
+      "format": "date"
+    },
+    "updated": {
+      "type": [
+        "string",
+        "null"
+      ],
+      "format": "date-time"
+    },
+    "tags": {
+      "type": [
+        "array",
+        "null"
+      ],
+      "maxItems": 3
+    }
+  },
+  "required": [
+    "id",
+    "code"
+  ],
+  "additionalProperties": false
+}
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestGenerateValidatesRows(t *testing.T) {
+	generated, err := Generate(accounts(t), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rowSchema, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(generated))
+	if err != nil {
+		t.Fatalf("expected a valid JSON Schema, got %s", err.Error())
+	}
+
+	testCases := []struct {
+		row   string
+		valid bool
+	}{
+		{row: `{"id": 1, "code": "ABC"}`, valid: true},
+		{row: `{"id": 1, "code": "ABC", "size": null, "score": 9.5, "active": true, "joined": "2024-01-31", "updated": "2024-01-31T09:30:00Z", "tags": ["a"]}`, valid: true},
+		{row: `{"code": "ABC"}`, valid: false},
+		{row: `{"id": 0, "code": "ABC"}`, valid: false},
+		{row: `{"id": 1.5, "code": "ABC"}`, valid: false},
+		{row: `{"id": 1, "code": "ABCD"}`, valid: false},
+		{row: `{"id": 1, "code": "ABC", "size": "L"}`, valid: false},
+		{row: `{"id": 1, "code": "ABC", "active": false}`, valid: false},
+		{row: `{"id": 1, "code": "ABC", "joined": "31/01/2024"}`, valid: false},
+		{row: `{"id": 1, "code": "ABC", "tags": ["a", "b", "c", "d"]}`, valid: false},
+		{row: `{"id": 1, "code": "ABC", "extra": 1}`, valid: false},
+	}
+
+	for _, testCase := range testCases {
+		result, err := rowSchema.Validate(gojsonschema.NewStringLoader(testCase.row))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Valid() != testCase.valid {
+			t.Errorf("%s: expected valid to be %v, got errors %v", testCase.row, testCase.valid, result.Errors())
+		}
+	}
+
+	// a row can have other properties if they're allowed
+	generated, err = Generate(accounts(t), Options{AdditionalProperties: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(generated), gojsonschema.NewStringLoader(`{"id": 1, "code": "ABC", "extra": 1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Valid() {
+		t.Errorf("expected a row with another property to be valid, got %v", result.Errors())
+	}
+}
+
+func TestGenerateInvalidEnum(t *testing.T) {
+	tableSchema, err := schema.New().Boolean("active").Enum("true", "yes").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Generate(tableSchema, Options{})
+	if err == nil || err.Error() != `active has the enum value "yes", which isn't a valid boolean` {
+		t.Errorf("expected an error for an enum value that isn't a boolean, got %v", err)
+	}
+}
+
+func TestGenerateNoFields(t *testing.T) {
+	if _, err := Generate(schema.MakeSchema(schema.SchemaOptions{}), Options{}); err == nil {
+		t.Error("expected an error for a schema without fields")
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"tableschema-validator/schema"
+)
+
+// lintReport is the JSON report of the lint command, for one schema.
+type lintReport struct {
+	Schema   string           `json:"schema"`
+	Findings []schema.Finding `json:"findings"`
+}
+
+// lintSchema returns the findings for the schema at path: every way it breaks the tableschema profile, as
+// an error, and every problem schema.Lint finds with it that isn't at the same place.
+func lintSchema(path string, schemaFlags schemaFlags) ([]schema.Finding, error) {
+	tableSchema, err := schemaFlags.load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := []schema.Finding{}
+	var profileErr *schema.ProfileError
+	if err := schema.Validate(tableSchema); errors.As(err, &profileErr) {
+		for _, violation := range profileErr.Violations {
+			findings = append(findings, schema.Finding{Severity: schema.SeverityError, Path: violation.Path, Message: violation.Message})
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	profileFindings := len(findings)
+	for _, finding := range schema.Lint(tableSchema) {
+		reported := slices.ContainsFunc(findings[:profileFindings], func(existing schema.Finding) bool { return existing.Path == finding.Path })
+		if !reported {
+			findings = append(findings, finding)
+		}
+	}
+	return findings, nil
+}
+
+func runLint(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("lint", stderr)
+	schemaFlags := addSchemaFlags(flags)
+	output := flags.String("output", "text", `report format, "text" or "json"`)
+	strict := flags.Bool("strict", false, "treat warnings as errors")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: tableschema-validator lint [flags] schema.json ...")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Checks each schema against the tableschema profile, and for constraints that contradict each other.")
+		fmt.Fprintln(stderr, "Exits with 0 if no schema has errors, 1 if any does, and 2 on a usage or read error.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+
+	paths, err := parseFlags(flags, args)
+	if err != nil {
+		return flagsExitCode(err)
+	}
+	if len(paths) == 0 {
+		fmt.Fprintln(stderr, "tableschema-validator lint: expected a schema")
+		flags.Usage()
+		return exitError
+	}
+	if *output != "text" && *output != "json" {
+		fmt.Fprintf(stderr, "tableschema-validator lint: unknown output %q, expected text or json\n", *output)
+		return exitError
+	}
+
+	reports := []lintReport{}
+	exitCode := exitValid
+	for _, path := range paths {
+		findings, err := lintSchema(path, schemaFlags)
+		if err != nil {
+			fmt.Fprintf(stderr, "tableschema-validator lint: %s: %s\n", path, err.Error())
+			exitCode = exitError
+			continue
+		}
+		reports = append(reports, lintReport{Schema: path, Findings: findings})
+
+		failed := schema.HasErrors(findings) || (*strict && len(findings) > 0)
+		if failed && exitCode == exitValid {
+			exitCode = exitInvalid
+		}
+
+		if *output == "text" {
+			if len(findings) == 0 {
+				fmt.Fprintf(stdout, "%s: no problems found\n", path)
+			}
+			for _, finding := range findings {
+				fmt.Fprintf(stdout, "%s: %s: %s: %s\n", path, finding.Severity, finding.Path, finding.Message)
+			}
+		}
+	}
+
+	if *output == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(reports); err != nil {
+			fmt.Fprintf(stderr, "tableschema-validator lint: %s\n", err.Error())
+			return exitError
+		}
+	}
+	return exitCode
+}
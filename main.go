@@ -1,46 +1,114 @@
+// Command tableschema-validator checks tabular data against a Table Schema.
+//
+// Usage:
+//
+//	tableschema-validator <command> [flags] [arguments]
+//
+// Run `tableschema-validator help` for the list of commands. Every command exits with 0 on success, 1 if
+// what it checked was invalid, and 2 if it was used incorrectly or couldn't read its input.
 package main
 
 import (
-	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"tableschema-validator/schema"
+	"io"
+	"os"
 )
 
-func main() {
-	mySchema := schema.MakeSchema(schema.SchemaOptions{
-		Fields: schema.Fields{
-			NumberFields: []schema.NumberField{
-				{
-					FieldBase: schema.FieldBase{Name: "baz"},
-					Constraints: schema.NumberConstraints{
-						Min: schema.MinConstraint{Selected: true, Value: 11},
-					},
-				},
-			},
-			StringFields: []schema.StringField{
-				{
-					FieldBase: schema.FieldBase{Name: "foo"},
-					Constraints: schema.StringConstraints{
-						Required: schema.RequiredConstraint{Selected: true, Value: true},
-						Enum:     schema.EnumConstraint{Selected: true, Value: []string{"bar", "baz"}},
-						Pattern:  schema.PatternConstraint{Selected: true, Value: ".+|$"},
-					},
-				},
-				{
-					FieldBase: schema.FieldBase{Name: "bar"},
-					Constraints: schema.StringConstraints{
-						MinLength: schema.MinLengthConstraint{Selected: true, Value: 10},
-						Required:  schema.RequiredConstraint{Selected: true, Value: true},
-						Enum:      schema.EnumConstraint{Selected: true, Value: []string{"bar", "baz"}},
-					},
-				},
-			},
-		},
-	})
-
-	t, err := json.MarshalIndent(mySchema, "", " ")
-	if err != nil {
-		panic(err)
+const (
+	exitValid   = 0
+	exitInvalid = 1
+	exitError   = 2
+)
+
+// A command is a subcommand of the program. run is passed the arguments after the command's name.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int
+}
+
+var commands = []command{
+	{name: "validate", summary: "validate data files against a schema", run: runValidate},
+	{name: "infer", summary: "infer a schema from a data file", run: runInfer},
+	{name: "diff", summary: "compare two versions of a schema for breaking changes", run: runDiff},
+	{name: "lint", summary: "check schemas for mistakes and contradictory constraints", run: runLint},
+	{name: "generate", summary: "generate a Go struct and parser for a schema", run: runGenerate},
+	{name: "export", summary: "export a schema as a SQL CREATE TABLE statement, a JSON Schema or an Avro schema", run: runExport},
+	{name: "import", summary: "import a schema from a SQL CREATE TABLE statement or a JSON Schema", run: runImport},
+	{name: "doc", summary: "write documentation of a schema in Markdown or HTML", run: runDoc},
+	{name: "convert", summary: "convert a schema descriptor between json and YAML", run: runConvert},
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: tableschema-validator <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, command := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", command.name, command.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run `tableschema-validator <command> -h` for the flags of a command.")
+}
+
+// run runs the command named by args[0], returning the code the program should exit with.
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitError
+	}
+
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		usage(stdout)
+		return exitValid
+	}
+
+	for _, command := range commands {
+		if command.name == args[0] {
+			return command.run(args[1:], stdin, stdout, stderr)
+		}
+	}
+
+	fmt.Fprintf(stderr, "tableschema-validator: unknown command %q\n\n", args[0])
+	usage(stderr)
+	return exitError
+}
+
+// newFlagSet returns a FlagSet for a command, which reports errors rather than exiting on them.
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet("tableschema-validator "+name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	return flags
+}
+
+// parseFlags parses flags wherever they appear among args, rather than only before the first argument
+// as flag.FlagSet.Parse does, so that e.g. `validate data.csv --schema schema.json` works. It returns
+// the arguments that aren't flags.
+func parseFlags(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
 	}
-	fmt.Println(string(t))
+}
+
+// flagsExitCode is the exit code for an error from parseFlags: asking for help isn't a failure.
+func flagsExitCode(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return exitValid
+	}
+	return exitError
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
 }
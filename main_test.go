@@ -0,0 +1,605 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"tableschema-validator/schema"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// writeFiles writes files, keyed by name, to a temporary directory, returning the directory.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	directory := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(directory, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return directory
+}
+
+func TestRunSchemaBase(t *testing.T) {
+	directory := writeFiles(t, map[string]string{
+		"base.json": `{"fields": [{"name": "id", "type": "integer", "constraints": {"required": true}}], "primaryKey": ["id"]}`,
+	})
+	if err := os.Mkdir(filepath.Join(directory, "tables"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	accounts := filepath.Join(directory, "tables", "accounts.json")
+	if err := os.WriteFile(accounts, []byte(`{"extends": "../base.json", "fields": [{"name": "plan"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name     string
+		args     []string
+		exitCode int
+		stderr   string
+	}{
+		{name: "default base", args: []string{"lint", accounts}, exitCode: exitError, stderr: "../base.json is outside"},
+		{name: "base above", args: []string{"lint", "--base", directory, accounts}, exitCode: exitValid},
+		{name: "base below", args: []string{"lint", "--base", filepath.Join(directory, "tables"), accounts}, exitCode: exitError, stderr: "../base.json is outside"},
+		{name: "validate", args: []string{"validate", "--base", filepath.Join(directory, "tables"), "--schema", accounts, "-"}, exitCode: exitError, stderr: "../base.json is outside"},
+	}
+
+	for _, testCase := range testCases {
+		var stdout, stderr bytes.Buffer
+		exitCode := run(testCase.args, strings.NewReader("id,plan\n1,a\n"), &stdout, &stderr)
+		if exitCode != testCase.exitCode {
+			t.Errorf("%s: expected exit code %d, got %d (stderr: %s)", testCase.name, testCase.exitCode, exitCode, stderr.String())
+		}
+		if !strings.Contains(stderr.String(), testCase.stderr) {
+			t.Errorf("%s: expected stderr to contain %q, got %s", testCase.name, testCase.stderr, stderr.String())
+		}
+	}
+}
+
+func TestRunValidate(t *testing.T) {
+	directory := writeFiles(t, map[string]string{
+		"schema.json":      `{"fields": [{"name": "id", "constraints": {"required": true}}, {"name": "score", "type": "number"}]}`,
+		"valid.csv":        "id,score\na,1\nb,2\n",
+		"invalid.csv":      "id,score\na,1\n,x\n",
+		"datapackage.json": `{"resources": [{"name": "scores", "path": "valid.csv", "schema": "schema.json"}]}`,
+	})
+	schemaPath := filepath.Join(directory, "schema.json")
+	resourceSchemaPath := filepath.Join(directory, "datapackage.json#/resources/0/schema")
+	validPath := filepath.Join(directory, "valid.csv")
+	invalidPath := filepath.Join(directory, "invalid.csv")
+
+	testCases := []struct {
+		name     string
+		args     []string
+		stdin    string
+		exitCode int
+		stdout   string
+	}{
+		{name: "valid", args: []string{"validate", "--schema", schemaPath, validPath}, exitCode: exitValid, stdout: validPath + ": valid, 2 rows\n"},
+		{
+			name:     "invalid",
+			args:     []string{"validate", validPath, invalidPath, "--schema", schemaPath},
+			exitCode: exitInvalid,
+			stdout: validPath + ": valid, 2 rows\n" +
+				invalidPath + ": invalid, 1 of 2 rows invalid\n" +
+				"  id was marked as required, but not provided (row 3, line 3)\n" +
+				"  score was marked as a number, but its value x could not be parsed as a number (row 3, line 3)\n",
+		},
+		{name: "resource schema", args: []string{"validate", "--schema", resourceSchemaPath, validPath}, exitCode: exitValid, stdout: validPath + ": valid, 2 rows\n"},
+		{name: "missing resource", args: []string{"validate", "--schema", filepath.Join(directory, "datapackage.json#/resources/1/schema"), validPath}, exitCode: exitError},
+		{name: "stdin", args: []string{"validate", "--schema", schemaPath}, stdin: "id,score\nc,3\n", exitCode: exitValid, stdout: "stdin: valid, 1 row\n"},
+		{name: "missing file", args: []string{"validate", "--schema", schemaPath, invalidPath, filepath.Join(directory, "missing.csv")}, exitCode: exitError},
+		{name: "missing schema flag", args: []string{"validate", validPath}, exitCode: exitError},
+		{name: "unknown command", args: []string{"check"}, exitCode: exitError},
+		{name: "no command", args: nil, exitCode: exitError},
+	}
+
+	for _, testCase := range testCases {
+		var stdout, stderr bytes.Buffer
+		exitCode := run(testCase.args, strings.NewReader(testCase.stdin), &stdout, &stderr)
+		if exitCode != testCase.exitCode {
+			t.Errorf("%s: expected exit code %d, got %d (stderr: %s)", testCase.name, testCase.exitCode, exitCode, stderr.String())
+		}
+		if testCase.stdout != "" {
+			if diff := cmp.Diff(testCase.stdout, stdout.String()); diff != "" {
+				t.Errorf("%s (-want +got):\n%s", testCase.name, diff)
+			}
+		}
+	}
+}
+
+func TestRunValidateJSONReport(t *testing.T) {
+	directory := writeFiles(t, map[string]string{
+		"schema.json": `{"fields": [{"name": "id", "constraints": {"required": true}}]}`,
+		"data.csv":    "id\na\n\"b\"c\n\n",
+	})
+
+	var stdout, stderr bytes.Buffer
+	exitCode := run([]string{"validate", "--output", "json", "--schema", filepath.Join(directory, "schema.json"), filepath.Join(directory, "data.csv")}, nil, &stdout, &stderr)
+	if exitCode != exitInvalid {
+		t.Errorf("Expected exit code %d, got %d (stderr: %s)", exitInvalid, exitCode, stderr.String())
+	}
+
+	var got validationReport
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("Failed to read JSON report with error %s", err.Error())
+	}
+
+	offset := int64(5)
+	expected := validationReport{
+		Valid: false,
+		Sources: []sourceReport{
+			{
+				Source:       filepath.Join(directory, "data.csv"),
+				RowsExamined: 2,
+				InvalidRows:  1,
+				Errors: []errorReport{
+					{Row: 3, Line: 3, Offset: &offset, Constraint: "source-error", Reason: `the record could not be parsed: extraneous or missing " in quoted-field (row 3, line 3)`},
+				},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestRunInfer(t *testing.T) {
+	directory := writeFiles(t, map[string]string{
+		"data.csv": "id,score,when\n1,2.5,2024-01-01\n2,3,2024-01-02\n",
+	})
+	dataPath := filepath.Join(directory, "data.csv")
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := run([]string{"infer", dataPath}, strings.NewReader(""), &stdout, &stderr); exitCode != exitValid {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", exitValid, exitCode, stderr.String())
+	}
+
+	var descriptor struct {
+		Fields []struct {
+			Name string `json:"name"`
+			Type string `json:"type"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &descriptor); err != nil {
+		t.Fatalf("expected a JSON descriptor, got %s", stdout.String())
+	}
+	types := make(map[string]string)
+	for _, field := range descriptor.Fields {
+		types[field.Name] = field.Type
+	}
+	if diff := cmp.Diff(map[string]string{"id": "integer", "score": "number", "when": "date"}, types); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	// the data a schema was inferred from is valid against it
+	schemaPath := filepath.Join(directory, "schema.json")
+	if err := os.WriteFile(schemaPath, stdout.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Reset()
+	if exitCode := run([]string{"validate", "--schema", schemaPath, dataPath}, strings.NewReader(""), &stdout, &stderr); exitCode != exitValid {
+		t.Errorf("expected the data to be valid against its inferred schema, got %s", stdout.String())
+	}
+
+	for _, args := range [][]string{
+		{"infer", filepath.Join(directory, "missing.csv")},
+		{"infer", "--confidence", "2", dataPath},
+		{"infer", "--confidence", "0", dataPath},
+		{"infer", dataPath, dataPath},
+	} {
+		if exitCode := run(args, strings.NewReader(""), &stdout, &stderr); exitCode != exitError {
+			t.Errorf("%v: expected exit code %d, got %d", args, exitError, exitCode)
+		}
+	}
+}
+
+func TestRunDiff(t *testing.T) {
+	directory := writeFiles(t, map[string]string{
+		"v1.json": `{"fields": [{"name": "id", "constraints": {"required": true}}, {"name": "score", "type": "number"}]}`,
+		"v2.json": `{"fields": [{"name": "id", "constraints": {"required": true}}, {"name": "score", "type": "integer"}, {"name": "note"}]}`,
+		"v3.json": `{"fields": [{"name": "id"}]}`,
+	})
+	v1 := filepath.Join(directory, "v1.json")
+	v2 := filepath.Join(directory, "v2.json")
+	v3 := filepath.Join(directory, "v3.json")
+
+	testCases := []struct {
+		name     string
+		args     []string
+		exitCode int
+		stdout   string
+	}{
+		{name: "unchanged", args: []string{"diff", v1, v1}, exitCode: exitValid, stdout: "no changes\n"},
+		{
+			name:     "non-breaking",
+			args:     []string{"diff", v1, v2},
+			exitCode: exitValid,
+			stdout:   "non-breaking: the type of score was changed from number to integer\nnon-breaking: note was added, with type string\n",
+		},
+		{
+			name:     "breaking",
+			args:     []string{"diff", v2, v3},
+			exitCode: exitInvalid,
+			stdout:   "breaking: the required constraint of id was removed\nbreaking: score was removed\nbreaking: note was removed\n",
+		},
+		{name: "one schema", args: []string{"diff", v1}, exitCode: exitError},
+		{name: "missing schema", args: []string{"diff", v1, filepath.Join(directory, "missing.json")}, exitCode: exitError},
+	}
+
+	for _, testCase := range testCases {
+		var stdout, stderr bytes.Buffer
+		exitCode := run(testCase.args, strings.NewReader(""), &stdout, &stderr)
+		if exitCode != testCase.exitCode {
+			t.Errorf("%s: expected exit code %d, got %d (stderr: %s)", testCase.name, testCase.exitCode, exitCode, stderr.String())
+		}
+		if testCase.stdout != "" {
+			if diff := cmp.Diff(testCase.stdout, stdout.String()); diff != "" {
+				t.Errorf("%s (-want +got):\n%s", testCase.name, diff)
+			}
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	if exitCode := run([]string{"diff", "--output", "json", v2, v3}, strings.NewReader(""), &stdout, &stderr); exitCode != exitInvalid {
+		t.Fatalf("expected exit code %d, got %d (stderr: %s)", exitInvalid, exitCode, stderr.String())
+	}
+	var report diffReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("expected a JSON report, got %s", stdout.String())
+	}
+	if !report.Breaking || len(report.Changes) != 3 || report.Changes[1].Kind != "field-removed" {
+		t.Errorf("unexpected report %s", stdout.String())
+	}
+}
+
+func TestRunLint(t *testing.T) {
+	directory := writeFiles(t, map[string]string{
+		"clean.json":    `{"fields": [{"name": "id", "constraints": {"required": true}}]}`,
+		"warning.json":  `{"fields": [{"name": "kind", "constraints": {"enum": ["only"]}}]}`,
+		"conflict.json": `{"fields": [{"name": "bar", "constraints": {"minLength": 10, "enum": ["bar", "baz"], "pattern": "[a-z"}}]}`,
+	})
+	clean := filepath.Join(directory, "clean.json")
+	warning := filepath.Join(directory, "warning.json")
+	conflict := filepath.Join(directory, "conflict.json")
+
+	testCases := []struct {
+		name     string
+		args     []string
+		exitCode int
+		stdout   string
+	}{
+		{name: "clean", args: []string{"lint", clean}, exitCode: exitValid, stdout: clean + ": no problems found\n"},
+		{
+			name:     "warning",
+			args:     []string{"lint", warning},
+			exitCode: exitValid,
+			stdout:   warning + ": warning: /fields/0/constraints/enum: kind has an enum of one value, so every row has to have the same value\n",
+		},
+		{name: "strict warning", args: []string{"lint", "--strict", warning}, exitCode: exitInvalid},
+		{
+			name:     "conflict",
+			args:     []string{"lint", conflict},
+			exitCode: exitInvalid,
+			stdout: conflict + ": error: /fields/0/constraints/pattern: [a-z is not a valid regular expression: error parsing regexp: missing closing ]: `[a-z`\n" +
+				conflict + `: warning: /fields/0/constraints/enum: bar has the enum value "bar", which is shorter than the minLength of 10, so can never be valid` + "\n" +
+				conflict + `: warning: /fields/0/constraints/enum: bar has the enum value "baz", which is shorter than the minLength of 10, so can never be valid` + "\n" +
+				conflict + ": error: /fields/0/constraints/enum: bar has no enum value that can be valid, so no value can be\n",
+		},
+		{name: "missing schema", args: []string{"lint", filepath.Join(directory, "missing.json")}, exitCode: exitError},
+		{name: "no schema", args: []string{"lint"}, exitCode: exitError},
+	}
+
+	for _, testCase := range testCases {
+		var stdout, stderr bytes.Buffer
+		exitCode := run(testCase.args, strings.NewReader(""), &stdout, &stderr)
+		if exitCode != testCase.exitCode {
+			t.Errorf("%s: expected exit code %d, got %d (stderr: %s)", testCase.name, testCase.exitCode, exitCode, stderr.String())
+		}
+		if testCase.stdout != "" {
+			if diff := cmp.Diff(testCase.stdout, stdout.String()); diff != "" {
+				t.Errorf("%s (-want +got):\n%s", testCase.name, diff)
+			}
+		}
+	}
+}
+
+func TestRunExport(t *testing.T) {
+	directory := writeFiles(t, map[string]string{
+		"accounts.json": `{"fields": [{"name": "id", "type": "integer", "constraints": {"required": true}}], "primaryKey": ["id"]}`,
+		"broken.json":   `{"fields": [{"name": "id"}], "primaryKey": ["key"]}`,
+	})
+	accounts := filepath.Join(directory, "accounts.json")
+
+	testCases := []struct {
+		name     string
+		args     []string
+		exitCode int
+		stdout   string
+	}{
+		{
+			name:     "postgresql",
+			args:     []string{"export", "--format", "postgresql", accounts},
+			exitCode: exitValid,
+			stdout:   "CREATE TABLE \"accounts\" (\n  \"id\" BIGINT NOT NULL,\n  PRIMARY KEY (\"id\")\n);\n",
+		},
+		{
+			name:     "table name",
+			args:     []string{"export", accounts, "--format", "mysql", "--table", "users"},
+			exitCode: exitValid,
+			stdout:   "CREATE TABLE `users` (\n  `id` BIGINT NOT NULL,\n  PRIMARY KEY (`id`)\n);\n",
+		},
+		{
+			name:     "json schema",
+			args:     []string{"export", "--format", "jsonschema", accounts},
+			exitCode: exitValid,
+			stdout: `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "accounts",
+  "type": "object",
+  "properties": {
+    "id": {
+      "type": "integer"
+    }
+  },
+  "required": [
+    "id"
+  ],
+  "additionalProperties": false
+}
+`,
+		},
+		{
+			name:     "avro",
+			args:     []string{"export", "--format", "avro", "--namespace", "com.example", "--decimal", "id=12", accounts},
+			exitCode: exitValid,
+			stdout: `{
+  "type": "record",
+  "name": "accounts",
+  "namespace": "com.example",
+  "fields": [
+    {
+      "name": "id",
+      "type": {
+        "type": "bytes",
+        "logicalType": "decimal",
+        "precision": 12
+      }
+    }
+  ]
+}
+`,
+		},
+		{name: "bad decimal", args: []string{"export", "--format", "avro", "--decimal", "id=ten,2", accounts}, exitCode: exitError},
+		{name: "unknown time", args: []string{"export", "--format", "avro", "--time", "opens", accounts}, exitCode: exitError},
+		{name: "unknown format", args: []string{"export", "--format", "oracle", accounts}, exitCode: exitError},
+		{name: "no format", args: []string{"export", accounts}, exitCode: exitError},
+		{name: "unknown key", args: []string{"export", "--format", "sqlite", filepath.Join(directory, "broken.json")}, exitCode: exitError},
+		{name: "no schema", args: []string{"export", "--format", "sqlite"}, exitCode: exitError},
+	}
+
+	for _, testCase := range testCases {
+		var stdout, stderr bytes.Buffer
+		exitCode := run(testCase.args, strings.NewReader(""), &stdout, &stderr)
+		if exitCode != testCase.exitCode {
+			t.Errorf("%s: expected exit code %d, got %d (stderr: %s)", testCase.name, testCase.exitCode, exitCode, stderr.String())
+		}
+		if testCase.stdout != "" {
+			if diff := cmp.Diff(testCase.stdout, stdout.String()); diff != "" {
+				t.Errorf("%s (-want +got):\n%s", testCase.name, diff)
+			}
+		}
+	}
+}
+
+func TestRunImport(t *testing.T) {
+	directory := writeFiles(t, map[string]string{
+		"one.sql":  "CREATE TABLE accounts (id integer PRIMARY KEY, plan text DEFAULT 'free');",
+		"two.sql":  "CREATE TABLE a (id integer); CREATE TABLE b (name text NOT NULL);",
+		"row.json": `{"type": "object", "properties": {"id": {"type": "integer"}, "name": {"type": "string", "default": ""}}}`,
+	})
+	one := filepath.Join(directory, "one.sql")
+	two := filepath.Join(directory, "two.sql")
+	row := filepath.Join(directory, "row.json")
+
+	testCases := []struct {
+		name     string
+		args     []string
+		stdin    string
+		exitCode int
+		fields   []string
+		stderr   string
+	}{
+		{
+			name:     "one table",
+			args:     []string{"import", "--format", "sql", one},
+			exitCode: exitValid,
+			fields:   []string{"id", "plan"},
+			stderr:   "tableschema-validator import: accounts.plan: not translated: DEFAULT 'free': a schema has no default values\n",
+		},
+		{name: "chosen table", args: []string{"import", two, "--format", "sql", "--table", "b"}, exitCode: exitValid, fields: []string{"name"}},
+		{name: "stdin", args: []string{"import", "--format", "sql"}, stdin: "create table c (x int)", exitCode: exitValid, fields: []string{"x"}},
+		{name: "several tables", args: []string{"import", "--format", "sql", two}, exitCode: exitError},
+		{name: "unknown table", args: []string{"import", "--format", "sql", "--table", "c", two}, exitCode: exitError},
+		{name: "no tables", args: []string{"import", "--format", "sql"}, stdin: "SELECT 1;", exitCode: exitError},
+		{
+			name:     "json schema",
+			args:     []string{"import", "--format", "jsonschema", row},
+			exitCode: exitValid,
+			fields:   []string{"id", "name"},
+			stderr:   "tableschema-validator import: " + row + "#/properties/name/default: a schema has no default values\n",
+		},
+		{name: "json schema stdin", args: []string{"import", "--format", "jsonschema"}, stdin: `{"properties": {"x": {"type": "number"}}}`, exitCode: exitValid, fields: []string{"x"}},
+		{name: "not an object", args: []string{"import", "--format", "jsonschema"}, stdin: `{"type": "string"}`, exitCode: exitError},
+		{name: "unknown format", args: []string{"import", "--format", "xml", one}, exitCode: exitError},
+	}
+
+	for _, testCase := range testCases {
+		var stdout, stderr bytes.Buffer
+		exitCode := run(testCase.args, strings.NewReader(testCase.stdin), &stdout, &stderr)
+		if exitCode != testCase.exitCode {
+			t.Errorf("%s: expected exit code %d, got %d (stderr: %s)", testCase.name, testCase.exitCode, exitCode, stderr.String())
+		}
+		if testCase.fields != nil {
+			tableSchema, err := schema.ParseSchema(stdout.Bytes())
+			if err != nil {
+				t.Fatalf("%s: expected a descriptor, got %s", testCase.name, stdout.String())
+			}
+			if diff := cmp.Diff(testCase.fields, tableSchema.Fields.Names()); diff != "" {
+				t.Errorf("%s (-want +got):\n%s", testCase.name, diff)
+			}
+		}
+		if testCase.stderr != "" {
+			if diff := cmp.Diff(testCase.stderr, stderr.String()); diff != "" {
+				t.Errorf("%s (-want +got):\n%s", testCase.name, diff)
+			}
+		}
+	}
+}
+
+func TestRunDoc(t *testing.T) {
+	directory := writeFiles(t, map[string]string{
+		"accounts.json": `{"fields": [{"name": "size", "type": "string", "constraints": {"enum": ["S", "M"]}}]}`,
+	})
+	accounts := filepath.Join(directory, "accounts.json")
+	output := filepath.Join(directory, "accounts.html")
+
+	testCases := []struct {
+		name     string
+		args     []string
+		exitCode int
+		stdout   string
+	}{
+		{
+			name:     "markdown",
+			args:     []string{"doc", accounts},
+			exitCode: exitValid,
+			stdout:   "# accounts\n\n| Field | Type | Title | Description | Example | Constraints |\n| --- | --- | --- | --- | --- | --- |\n| size | string |  |  |  | must be one of S, M |\n",
+		},
+		{name: "html", args: []string{"doc", "--format", "html", "--title", "Accounts", "--output", output, accounts}, exitCode: exitValid},
+		{name: "unknown format", args: []string{"doc", "--format", "pdf", accounts}, exitCode: exitError},
+		{name: "no schema", args: []string{"doc"}, exitCode: exitError},
+		{name: "missing schema", args: []string{"doc", filepath.Join(directory, "missing.json")}, exitCode: exitError},
+	}
+
+	for _, testCase := range testCases {
+		var stdout, stderr bytes.Buffer
+		exitCode := run(testCase.args, strings.NewReader(""), &stdout, &stderr)
+		if exitCode != testCase.exitCode {
+			t.Errorf("%s: expected exit code %d, got %d (stderr: %s)", testCase.name, testCase.exitCode, exitCode, stderr.String())
+		}
+		if testCase.stdout != "" {
+			if diff := cmp.Diff(testCase.stdout, stdout.String()); diff != "" {
+				t.Errorf("%s (-want +got):\n%s", testCase.name, diff)
+			}
+		}
+	}
+
+	page, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(page), "<h1>Accounts</h1>") {
+		t.Errorf("expected an HTML page titled Accounts, got:\n%s", page)
+	}
+}
+
+func TestRunConvert(t *testing.T) {
+	directory := writeFiles(t, map[string]string{
+		"accounts.json": `{"title": "Accounts", "fields": [{"name": "id", "type": "integer", "constraints": {"required": true}}], "primaryKey": ["id"]}`,
+		"accounts.yaml": "# accounts\ntitle: Accounts\nfields:\n  - name: id\n    type: integer\n    constraints: {required: true}\nprimaryKey: [id]\n",
+		"broken.yml":    "fields:\n  - name: id\n   type: integer\n",
+		"base.json":     `{"fields": [{"name": "id", "type": "integer"}]}`,
+		"plans.json":    `{"extends": "base.json", "fields": [{"name": "plan"}]}`,
+	})
+	accountsJSON := filepath.Join(directory, "accounts.json")
+	accountsYAML := filepath.Join(directory, "accounts.yaml")
+	output := filepath.Join(directory, "converted.yml")
+
+	// the descriptor keeps the order of its keys and the properties a schema doesn't have, and isn't flattened
+	yamlDescriptor := `title: Accounts
+fields:
+  - name: id
+    type: integer
+    constraints:
+      required: true
+primaryKey: [id]
+`
+	jsonDescriptor := `{
+  "title": "Accounts",
+  "fields": [
+    {
+      "name": "id",
+      "type": "integer",
+      "constraints": {
+        "required": true
+      }
+    }
+  ],
+  "primaryKey": [
+    "id"
+  ]
+}
+`
+
+	testCases := []struct {
+		name     string
+		args     []string
+		exitCode int
+		stdout   string
+	}{
+		{name: "json to yaml", args: []string{"convert", accountsJSON}, exitCode: exitValid, stdout: yamlDescriptor},
+		{name: "yaml to yaml", args: []string{"convert", "--to", "yaml", accountsYAML}, exitCode: exitValid, stdout: yamlDescriptor},
+		{name: "yaml to json", args: []string{"convert", accountsYAML}, exitCode: exitValid, stdout: jsonDescriptor},
+		{name: "composed", args: []string{"convert", filepath.Join(directory, "plans.json")}, exitCode: exitValid, stdout: "extends: base.json\nfields:\n  - name: plan\n"},
+		{name: "missing schema", args: []string{"convert", filepath.Join(directory, "missing.json")}, exitCode: exitError},
+		{name: "fragment", args: []string{"convert", accountsJSON + "#/fields"}, exitCode: exitError},
+		{name: "yaml to json file", args: []string{"convert", "--output", output, accountsYAML}, exitCode: exitValid},
+		{name: "broken yaml", args: []string{"convert", filepath.Join(directory, "broken.yml")}, exitCode: exitError},
+		{name: "unknown format", args: []string{"convert", "--to", "toml", accountsJSON}, exitCode: exitError},
+		{name: "format and file", args: []string{"convert", "--to", "json", "--output", output, accountsJSON}, exitCode: exitError},
+		{name: "two schemas", args: []string{"convert", accountsJSON, accountsYAML}, exitCode: exitError},
+		{name: "no schema", args: []string{"convert"}, exitCode: exitError},
+	}
+
+	for _, testCase := range testCases {
+		var stdout, stderr bytes.Buffer
+		exitCode := run(testCase.args, strings.NewReader(""), &stdout, &stderr)
+		if exitCode != testCase.exitCode {
+			t.Errorf("%s: expected exit code %d, got %d (stderr: %s)", testCase.name, testCase.exitCode, exitCode, stderr.String())
+		}
+		if testCase.stdout != "" {
+			if diff := cmp.Diff(testCase.stdout, stdout.String()); diff != "" {
+				t.Errorf("%s (-want +got):\n%s", testCase.name, diff)
+			}
+		}
+	}
+
+	// the converted file is YAML, since it ends in .yml, and is the same schema
+	converted, err := schema.LoadSchema(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original, err := schema.LoadSchema(accountsJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(original, converted); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+	if data, _ := os.ReadFile(output); string(data) != yamlDescriptor {
+		t.Errorf("expected the output file to be YAML, got %s", data)
+	}
+
+	// a YAML schema can be used wherever a json one can
+	var stdout, stderr bytes.Buffer
+	if exitCode := run([]string{"lint", accountsYAML}, strings.NewReader(""), &stdout, &stderr); exitCode != exitValid {
+		t.Errorf("expected a YAML schema to lint, got exit code %d (stdout: %s, stderr: %s)", exitCode, stdout.String(), stderr.String())
+	}
+}
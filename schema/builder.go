@@ -0,0 +1,281 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// A Builder builds a Schema a field at a time, as an alternative to writing out its SchemaOptions. Each
+// method that adds a field, e.g. String, starts a field, and the methods that set constraints, e.g. Required,
+// set them on the field started last:
+//
+//	schema.New().String("foo").Required().Enum("bar", "baz").Number("baz").Min(11).Build()
+//
+// Constraints are Selected as they're set, and fields are listed in the order they were added. A mistake,
+// such as a constraint the field's type doesn't have, is recorded rather than reported straight away, so
+// that calls can be chained, and Build returns every mistake made.
+type Builder struct {
+	fields Fields
+	// keys holds the primary, unique and foreign keys, which aren't on any one field
+	keys SchemaOptions
+	// current is the name of the slice of fields that the field being built is in, e.g. StringFields
+	current string
+	errs    []error
+}
+
+// New returns a Builder for a schema without any fields.
+func New() *Builder {
+	return &Builder{}
+}
+
+func (builder *Builder) addError(format string, args ...any) *Builder {
+	builder.errs = append(builder.errs, fmt.Errorf(format, args...))
+	return builder
+}
+
+// field returns the field being built, or an invalid Value if no field has been added.
+func (builder *Builder) field() reflect.Value {
+	if builder.current == "" {
+		return reflect.Value{}
+	}
+	typedFields := reflect.ValueOf(&builder.fields).Elem().FieldByName(builder.current)
+	return typedFields.Index(typedFields.Len() - 1)
+}
+
+// add starts a field named name in the slice of fields called typedFields.
+func (builder *Builder) add(typedFields string, name string) *Builder {
+	if name == "" {
+		builder.addError("field %d has no name", len(builder.fields.Order))
+	} else if slices.Contains(builder.fields.Order, name) {
+		builder.addError("there is already a field named %s", name)
+	}
+
+	slice := reflect.ValueOf(&builder.fields).Elem().FieldByName(typedFields)
+	field := reflect.New(slice.Type().Elem()).Elem()
+	field.FieldByName("Name").SetString(name)
+	field.FieldByName("FieldType").SetString(strings.ToLower(strings.TrimSuffix(typedFields, "Fields")))
+	slice.Set(reflect.Append(slice, field))
+
+	builder.fields.Order = append(builder.fields.Order, name)
+	builder.current = typedFields
+	return builder
+}
+
+// String starts a string field called name.
+func (builder *Builder) String(name string) *Builder {
+	return builder.add("StringFields", name)
+}
+
+// Number starts a number field called name.
+func (builder *Builder) Number(name string) *Builder {
+	return builder.add("NumberFields", name)
+}
+
+// Boolean starts a boolean field called name.
+func (builder *Builder) Boolean(name string) *Builder {
+	return builder.add("BooleanFields", name)
+}
+
+// List starts a list field called name.
+func (builder *Builder) List(name string) *Builder {
+	return builder.add("ListFields", name)
+}
+
+// Integer starts an integer field called name.
+func (builder *Builder) Integer(name string) *Builder {
+	return builder.add("IntegerFields", name)
+}
+
+// Date starts a date field called name.
+func (builder *Builder) Date(name string) *Builder {
+	return builder.add("DateFields", name)
+}
+
+// DateTime starts a datetime field called name.
+func (builder *Builder) DateTime(name string) *Builder {
+	return builder.add("DateTimeFields", name)
+}
+
+// describe sets one of the descriptive properties of the field being built, e.g. its Title.
+func (builder *Builder) describe(property string, value string) *Builder {
+	field := builder.field()
+	if !field.IsValid() {
+		return builder.addError("the %s was set before a field was added", strings.ToLower(property))
+	}
+	field.FieldByName(property).SetString(value)
+	return builder
+}
+
+// Title sets the title of the field being built.
+func (builder *Builder) Title(title string) *Builder {
+	return builder.describe("Title", title)
+}
+
+// Description sets the description of the field being built.
+func (builder *Builder) Description(description string) *Builder {
+	return builder.describe("Description", description)
+}
+
+// Example sets the example value of the field being built.
+func (builder *Builder) Example(example string) *Builder {
+	return builder.describe("Example", example)
+}
+
+// constrain selects the constraint called name, e.g. MinLength, on the field being built, with value. check,
+// if it isn't nil, returns why value isn't a valid value for the constraint, if it isn't.
+func (builder *Builder) constrain(name string, value any, check func() error) *Builder {
+	jsonKey := strings.ToLower(name[0:1]) + name[1:]
+
+	field := builder.field()
+	if !field.IsValid() {
+		return builder.addError("the %s constraint was set before a field was added", jsonKey)
+	}
+	fieldName := field.FieldByName("Name").String()
+	constraint := field.FieldByName("Constraints").FieldByName(name)
+	if !constraint.IsValid() {
+		return builder.addError("%s is of type %s, which has no %s constraint", fieldName, field.FieldByName("FieldType").String(), jsonKey)
+	}
+	if check != nil {
+		if err := check(); err != nil {
+			return builder.addError("the %s constraint of %s is invalid: %w", jsonKey, fieldName, err)
+		}
+	}
+
+	constraint.FieldByName("Selected").SetBool(true)
+	constraint.FieldByName("Value").Set(reflect.ValueOf(value))
+	return builder
+}
+
+// Required marks the field being built as required.
+func (builder *Builder) Required() *Builder {
+	return builder.constrain("Required", true, nil)
+}
+
+// Unique marks the field being built as unique.
+func (builder *Builder) Unique() *Builder {
+	return builder.constrain("Unique", true, nil)
+}
+
+// Pattern constrains the values of the field being built to match pattern, which has to be a valid
+// regular expression.
+func (builder *Builder) Pattern(pattern string) *Builder {
+	return builder.constrain("Pattern", pattern, func() error {
+		_, err := regexp.Compile(pattern)
+		return err
+	})
+}
+
+// Enum constrains the values of the field being built to be one of values, of which there has to be at
+// least one, with no duplicates.
+func (builder *Builder) Enum(values ...string) *Builder {
+	return builder.constrain("Enum", slices.Clone(values), func() error {
+		if len(values) == 0 {
+			return errors.New("it has no values")
+		}
+		for index, value := range values {
+			if slices.Contains(values[:index], value) {
+				return fmt.Errorf("it has the value %s more than once", value)
+			}
+		}
+		return nil
+	})
+}
+
+// checkLength returns a check that length, for a MinLength or MaxLength constraint, isn't negative.
+func checkLength(length int64) func() error {
+	return func() error {
+		if length < 0 {
+			return fmt.Errorf("%d is negative", length)
+		}
+		return nil
+	}
+}
+
+// MinLength constrains the values of the field being built to be at least length long.
+func (builder *Builder) MinLength(length int64) *Builder {
+	return builder.constrain("MinLength", length, checkLength(length))
+}
+
+// MaxLength constrains the values of the field being built to be at most length long.
+func (builder *Builder) MaxLength(length int64) *Builder {
+	return builder.constrain("MaxLength", length, checkLength(length))
+}
+
+// Min constrains the values of the field being built to be at least minimum.
+func (builder *Builder) Min(minimum int64) *Builder {
+	return builder.constrain("Min", minimum, nil)
+}
+
+// Max constrains the values of the field being built to be at most maximum.
+func (builder *Builder) Max(maximum int64) *Builder {
+	return builder.constrain("Max", maximum, nil)
+}
+
+// PrimaryKey sets the primary key to the fields called names, whose values together identify each row.
+func (builder *Builder) PrimaryKey(names ...string) *Builder {
+	builder.keys.PrimaryKey = slices.Clone(names)
+	return builder
+}
+
+// UniqueKey adds a unique key of the fields called names, whose values together are different in each row.
+func (builder *Builder) UniqueKey(names ...string) *Builder {
+	builder.keys.UniqueKeys = append(builder.keys.UniqueKeys, slices.Clone(names))
+	return builder
+}
+
+// ForeignKey adds a foreign key from the fields called names to the fields called referenceNames of the
+// resource called resource, or of the schema's own table if resource is "".
+func (builder *Builder) ForeignKey(names []string, resource string, referenceNames []string) *Builder {
+	builder.keys.ForeignKeys = append(builder.keys.ForeignKeys, ForeignKey{
+		Fields:    slices.Clone(names),
+		Reference: ForeignKeyReference{Resource: resource, Fields: slices.Clone(referenceNames)},
+	})
+	return builder
+}
+
+// checkKeys returns a mistake for each name in a key that isn't the name of a field. They aren't recorded, since
+// the keys are checked each time the schema is built.
+func (builder *Builder) checkKeys() []error {
+	var errs []error
+	check := func(key string, names FieldNames) {
+		if len(names) == 0 {
+			errs = append(errs, fmt.Errorf("the %s has no fields", key))
+		}
+		for _, name := range names {
+			if !slices.Contains(builder.fields.Order, name) {
+				errs = append(errs, fmt.Errorf("the %s names %s, which is not a field", key, name))
+			}
+		}
+	}
+	if builder.keys.PrimaryKey != nil {
+		check("primary key", builder.keys.PrimaryKey)
+	}
+	for index, key := range builder.keys.UniqueKeys {
+		check(fmt.Sprintf("unique key %d", index), key)
+	}
+	for index, key := range builder.keys.ForeignKeys {
+		check(fmt.Sprintf("foreign key %d", index), key.Fields)
+		if key.Reference.Resource == "" {
+			check(fmt.Sprintf("reference of foreign key %d", index), key.Reference.Fields)
+		}
+		if len(key.Fields) != len(key.Reference.Fields) {
+			errs = append(errs, fmt.Errorf("foreign key %d has %d fields, but refers to %d", index, len(key.Fields), len(key.Reference.Fields)))
+		}
+	}
+	return errs
+}
+
+// Build returns the schema that was built, or the mistakes made building it. A schema that doesn't match
+// the tableschema profile is a mistake too, which is reported as a *ProfileError.
+func (builder *Builder) Build() (Schema, error) {
+	if errs := append(slices.Clone(builder.errs), builder.checkKeys()...); len(errs) > 0 {
+		return Schema{}, errors.Join(errs...)
+	}
+	options := builder.keys
+	options.Fields = builder.fields
+	return MakeValidSchema(options)
+}
@@ -0,0 +1,156 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBuilder(t *testing.T) {
+	got, err := New().
+		String("foo").Required().Enum("bar", "baz").
+		Number("baz").Title("Baz").Min(11).
+		Boolean("qux").
+		String("code").Pattern("[A-Z]+").MinLength(2).MaxLength(4).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema with error %s", err.Error())
+	}
+
+	expected := MakeSchema(SchemaOptions{Fields: Fields{
+		StringFields: []StringField{
+			{
+				FieldBase: FieldBase{Name: "foo"},
+				Constraints: StringConstraints{
+					Required: RequiredConstraint{Selected: true, Value: true},
+					Enum:     EnumConstraint{Selected: true, Value: []string{"bar", "baz"}},
+				},
+			},
+			{
+				FieldBase: FieldBase{Name: "code"},
+				Constraints: StringConstraints{
+					Pattern:   PatternConstraint{Selected: true, Value: "[A-Z]+"},
+					MinLength: MinLengthConstraint{Selected: true, Value: 2},
+					MaxLength: MaxLengthConstraint{Selected: true, Value: 4},
+				},
+			},
+		},
+		NumberFields: []NumberField{
+			{FieldBase: FieldBase{Name: "baz", Title: "Baz"}, Constraints: NumberConstraints{Min: MinConstraint{Selected: true, Value: 11}}},
+		},
+		BooleanFields: []BooleanField{
+			{FieldBase: FieldBase{Name: "qux"}},
+		},
+		Order: []string{"foo", "baz", "qux", "code"},
+	}})
+
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	// the fields are marshalled in the order they were added, rather than by type
+	marshalled, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("Failed to marshall schema to JSON with error %s", err.Error())
+	}
+	var descriptor struct {
+		Fields []struct {
+			Name string `json:"name"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal(marshalled, &descriptor); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, field := range descriptor.Fields {
+		names = append(names, field.Name)
+	}
+	if diff := cmp.Diff([]string{"foo", "baz", "qux", "code"}, names); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestBuilderKeys(t *testing.T) {
+	got, err := New().
+		Integer("id").Required().
+		String("email").
+		Integer("parent").
+		PrimaryKey("id").
+		UniqueKey("email", "parent").
+		ForeignKey([]string{"parent"}, "", []string{"id"}).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build schema with error %s", err.Error())
+	}
+
+	want := SchemaOptions{
+		Fields:      got.Fields,
+		PrimaryKey:  FieldNames{"id"},
+		UniqueKeys:  []FieldNames{{"email", "parent"}},
+		ForeignKeys: []ForeignKey{{Fields: FieldNames{"parent"}, Reference: ForeignKeyReference{Fields: FieldNames{"id"}}}},
+	}
+	if diff := cmp.Diff(want, got.SchemaOptions); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		builder *Builder
+		want    string
+	}{
+		{name: "constraint before field", builder: New().Required().String("foo"), want: "the required constraint was set before a field was added"},
+		{name: "checked constraint before field", builder: New().Enum().String("foo"), want: "the enum constraint was set before a field was added"},
+		{name: "title before field", builder: New().Title("Foo"), want: "the title was set before a field was added"},
+		{name: "constraint for another type", builder: New().Boolean("foo").Unique(), want: "foo is of type boolean, which has no unique constraint"},
+		{name: "invalid pattern", builder: New().String("foo").Pattern("[a-z"), want: "the pattern constraint of foo is invalid: error parsing regexp: missing closing ]: `[a-z`"},
+		{name: "empty enum", builder: New().String("foo").Enum(), want: "the enum constraint of foo is invalid: it has no values"},
+		{name: "duplicate enum value", builder: New().String("foo").Enum("bar", "bar"), want: "the enum constraint of foo is invalid: it has the value bar more than once"},
+		{name: "negative length", builder: New().String("foo").MinLength(-1), want: "the minLength constraint of foo is invalid: -1 is negative"},
+		{name: "duplicate field", builder: New().String("foo").Number("foo"), want: "there is already a field named foo"},
+		{name: "no name", builder: New().String(""), want: "field 0 has no name"},
+		{name: "unknown key field", builder: New().String("foo").PrimaryKey("bar"), want: "the primary key names bar, which is not a field"},
+		{name: "empty unique key", builder: New().String("foo").UniqueKey(), want: "the unique key 0 has no fields"},
+		{
+			name:    "mismatched foreign key",
+			builder: New().String("foo").ForeignKey([]string{"foo"}, "bars", []string{"a", "b"}),
+			want:    "foreign key 0 has 1 fields, but refers to 2",
+		},
+		{
+			name:    "several mistakes",
+			builder: New().String("foo").Min(1).Integer("bar").Enum("a"),
+			want:    "foo is of type string, which has no min constraint\nbar is of type integer, which has no enum constraint",
+		},
+	}
+
+	for _, testCase := range testCases {
+		// building again reports the same mistakes, rather than the keys' twice
+		for range 2 {
+			_, err := testCase.builder.Build()
+			if err == nil {
+				t.Errorf("%s: expected an error", testCase.name)
+				break
+			}
+			if diff := cmp.Diff(testCase.want, err.Error()); diff != "" {
+				t.Errorf("%s (-want +got):\n%s", testCase.name, diff)
+			}
+		}
+	}
+
+	// a key can be fixed before building again
+	builder := New().String("foo").PrimaryKey("bar")
+	if _, err := builder.Build(); err == nil {
+		t.Error("expected an error for the primary key")
+	}
+	if _, err := builder.PrimaryKey("foo").Build(); err != nil {
+		t.Errorf("expected the fixed primary key to build, got %v", err)
+	}
+
+	var profileErr *ProfileError
+	if _, err := New().Build(); !errors.As(err, &profileErr) {
+		t.Errorf("expected a schema without fields to be reported as a ProfileError, got %v", err)
+	}
+}
@@ -0,0 +1,247 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+)
+
+// compositionKeys are the keys of a descriptor that compose it from others. They're resolved away, so aren't
+// part of the Schema.
+var compositionKeys = []string{"extends", "include", "overrides"}
+
+// appendedKeys are the keys whose lists are added to those of a base descriptor, rather than replacing them.
+var appendedKeys = []string{"uniqueKeys", "foreignKeys"}
+
+// composedField is a field of a descriptor being composed, and the file it came from.
+type composedField struct {
+	name   string
+	source string
+	// descriptor is the field's json, compacted so that two definitions can be compared.
+	descriptor json.RawMessage
+}
+
+// descriptor returns the descriptor reference refers to from the file at from, or from the base directory if
+// from is "", flattened: without any of the compositionKeys. The references in extends and include are
+// relative to the file the descriptor is in.
+func (resolver *Resolver) descriptor(reference string, from string) (map[string]json.RawMessage, error) {
+	location, err := resolver.locate(reference, from)
+	if err != nil {
+		return nil, err
+	}
+	// composing a descriptor is on the stack until it's flattened, so that a base or library composed from it
+	// is found to refer to it
+	leave, err := resolver.enter("composed "+location.file+"#"+location.pointer, location.String())
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	value, file, err := resolver.resolve(reference, from, true)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var descriptor map[string]json.RawMessage
+	if err := json.Unmarshal(data, &descriptor); err != nil {
+		return nil, fmt.Errorf("could not read schema: %w", err)
+	}
+
+	var extends string
+	var include []string
+	var overrides map[string]json.RawMessage
+	values := map[string]any{"extends": &extends, "include": &include, "overrides": &overrides}
+	for _, key := range compositionKeys {
+		if data, ok := descriptor[key]; ok {
+			if err := json.Unmarshal(data, values[key]); err != nil {
+				return nil, fmt.Errorf("could not read schema: invalid %s: %w", key, err)
+			}
+		}
+	}
+	// resolveReference returns the flattened descriptor at reference, relative to this one. Its errors say which
+	// descriptor they're in, apart from a cycle's, which names them all.
+	resolveReference := func(reference string) (map[string]json.RawMessage, error) {
+		descriptor, err := resolver.descriptor(reference, file)
+		return descriptor, nested(reference, err)
+	}
+
+	result := map[string]json.RawMessage{}
+	var fields []composedField
+	if extends != "" {
+		base, err := resolveReference(extends)
+		if err != nil {
+			return nil, err
+		}
+		result = base
+		if fields, err = descriptorFields(base, extends, nil); err != nil {
+			return nil, err
+		}
+	}
+	for _, library := range include {
+		libraryDescriptor, err := resolveReference(library)
+		if err != nil {
+			return nil, err
+		}
+		if fields, err = descriptorFields(libraryDescriptor, library, fields); err != nil {
+			return nil, err
+		}
+	}
+	if fields, err = descriptorFields(descriptor, filepath.Base(file), fields); err != nil {
+		return nil, err
+	}
+
+	for key, value := range descriptor {
+		switch {
+		case key == "fields" || slices.Contains(compositionKeys, key):
+		case slices.Contains(appendedKeys, key) && result[key] != nil:
+			if result[key], err = appendList(result[key], value); err != nil {
+				return nil, fmt.Errorf("could not read schema: invalid %s: %w", key, err)
+			}
+		default:
+			result[key] = value
+		}
+	}
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		index := slices.IndexFunc(fields, func(field composedField) bool { return field.name == name })
+		if index < 0 {
+			return nil, fmt.Errorf("overrides %s, which isn't a field", name)
+		}
+		if fields[index].descriptor, err = override(fields[index].descriptor, overrides[name]); err != nil {
+			return nil, fmt.Errorf("could not override %s: %w", name, err)
+		}
+	}
+
+	if len(fields) > 0 || descriptor["fields"] != nil {
+		list := make([]json.RawMessage, len(fields))
+		for index, field := range fields {
+			list[index] = field.descriptor
+		}
+		if result["fields"], err = json.Marshal(list); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// descriptorFields returns fields with the fields of descriptor, from source, after them. It's an error for a
+// field to be defined by an earlier source differently.
+func descriptorFields(descriptor map[string]json.RawMessage, source string, fields []composedField) ([]composedField, error) {
+	data, ok := descriptor["fields"]
+	if !ok {
+		return fields, nil
+	}
+	var list []json.RawMessage
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("could not read schema: invalid fields: %w", err)
+	}
+
+	earlier := len(fields)
+	for _, data := range list {
+		var named struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(data, &named); err != nil {
+			return nil, fmt.Errorf("could not read schema: invalid field: %w", err)
+		}
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, data); err != nil {
+			return nil, err
+		}
+		field := composedField{name: named.Name, source: source, descriptor: compacted.Bytes()}
+
+		index := slices.IndexFunc(fields[:earlier], func(existing composedField) bool { return existing.name == field.name })
+		switch {
+		case index < 0:
+			fields = append(fields, field)
+		case !bytes.Equal(fields[index].descriptor, field.descriptor):
+			return nil, fmt.Errorf("%s and %s both define the field %s, differently; change a field with overrides rather than defining it again",
+				fields[index].source, source, field.name)
+		}
+	}
+	return fields, nil
+}
+
+// appendList returns the json list base with the values of list that it doesn't already have after them.
+func appendList(base json.RawMessage, list json.RawMessage) (json.RawMessage, error) {
+	var baseValues, values []json.RawMessage
+	if err := json.Unmarshal(base, &baseValues); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(list, &values); err != nil {
+		return nil, err
+	}
+	for _, value := range values {
+		duplicate := slices.ContainsFunc(baseValues, func(existing json.RawMessage) bool {
+			var compactedExisting, compactedValue bytes.Buffer
+			return json.Compact(&compactedExisting, existing) == nil && json.Compact(&compactedValue, value) == nil &&
+				bytes.Equal(compactedExisting.Bytes(), compactedValue.Bytes())
+		})
+		if !duplicate {
+			baseValues = append(baseValues, value)
+		}
+	}
+	return json.Marshal(baseValues)
+}
+
+// override returns field with the properties of changes in place of its own. The constraints of changes are
+// added to the field's, and a property or constraint of null is removed.
+func override(field json.RawMessage, changes json.RawMessage) (json.RawMessage, error) {
+	var properties, changed map[string]json.RawMessage
+	if err := json.Unmarshal(field, &properties); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(changes, &changed); err != nil {
+		return nil, err
+	}
+
+	for key, value := range changed {
+		switch {
+		case key == "name" || key == "type":
+			var current, replacement any
+			json.Unmarshal(properties[key], &current)
+			json.Unmarshal(value, &replacement)
+			if current != replacement {
+				return nil, fmt.Errorf("an override can't change a field's %s", key)
+			}
+		case key == "constraints" && string(value) != "null":
+			constraints := map[string]json.RawMessage{}
+			if properties[key] != nil {
+				if err := json.Unmarshal(properties[key], &constraints); err != nil {
+					return nil, err
+				}
+			}
+			var changedConstraints map[string]json.RawMessage
+			if err := json.Unmarshal(value, &changedConstraints); err != nil {
+				return nil, err
+			}
+			for constraint, constraintValue := range changedConstraints {
+				if string(constraintValue) == "null" {
+					delete(constraints, constraint)
+				} else {
+					constraints[constraint] = constraintValue
+				}
+			}
+			data, err := json.Marshal(constraints)
+			if err != nil {
+				return nil, err
+			}
+			properties[key] = data
+		case string(value) == "null":
+			delete(properties, key)
+		default:
+			properties[key] = value
+		}
+	}
+	return json.Marshal(properties)
+}
@@ -0,0 +1,148 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// writeDescriptors writes each descriptor to the file at its path, relative to a temporary directory, and
+// returns the directory.
+func writeDescriptors(t *testing.T, descriptors map[string]string) string {
+	t.Helper()
+	directory := t.TempDir()
+	for path, descriptor := range descriptors {
+		path = filepath.Join(directory, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(descriptor), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return directory
+}
+
+func TestResolveSchema(t *testing.T) {
+	directory := writeDescriptors(t, map[string]string{
+		"base.json": `{
+  "include": ["libraries/tenant.json"],
+  "fields": [{"name": "id", "type": "integer", "constraints": {"required": true}}],
+  "primaryKey": ["id"],
+  "uniqueKeys": [["id", "tenant_id"]]
+}`,
+		"libraries/tenant.json": `{"fields": [{"name": "tenant_id", "type": "string", "constraints": {"pattern": "[a-z]+"}}]}`,
+		"libraries/audit.yaml": `# audit timestamps, on every table
+fields:
+  - name: created_at
+    type: datetime
+    constraints: {required: true}
+  - name: updated_at
+    type: datetime
+`,
+		"tables/accounts.json": `{
+  "extends": "../base.json",
+  "include": ["../libraries/audit.yaml", "../libraries/tenant.json"],
+  "fields": [{"name": "plan", "type": "string"}],
+  "overrides": {
+    "tenant_id": {"title": "Tenant", "constraints": {"required": true, "pattern": null}},
+    "created_at": {"constraints": null, "description": "When the row was added"}
+  },
+  "uniqueKeys": [["tenant_id", "plan"], ["id", "tenant_id"]]
+}`,
+	})
+
+	// the base descriptor is in the directory above, so that has to be the base directory
+	options := LoadOptions{Base: directory}
+	got, err := LoadSchemaWithOptions(filepath.Join(directory, "tables", "accounts.json"), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the base's fields come first, starting with those of its library, which is included again with the same
+	// definition
+	want, err := New().
+		String("tenant_id").Title("Tenant").Required().
+		Integer("id").Required().
+		DateTime("created_at").Description("When the row was added").
+		DateTime("updated_at").
+		String("plan").
+		PrimaryKey("id").
+		UniqueKey("id", "tenant_id").
+		UniqueKey("tenant_id", "plan").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	// as does a Resolver for the directory
+	resolver, err := NewResolver(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resolved, err := resolver.Load("tables/accounts.json"); err != nil {
+		t.Error(err)
+	} else if diff := cmp.Diff(want, resolved); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	// but not LoadSchema or ResolveSchema, whose base is the descriptor's own directory, or a base directory the
+	// base descriptor is outside
+	if _, err := LoadSchema(filepath.Join(directory, "tables", "accounts.json")); err == nil || !strings.Contains(err.Error(), "../base.json: ../base.json is outside") {
+		t.Errorf("expected an error for a base outside the descriptor's directory, got %v", err)
+	}
+	if _, err := ResolveSchema(filepath.Join(directory, "tables", "accounts.json")); err == nil || !strings.Contains(err.Error(), "../base.json is outside") {
+		t.Errorf("expected an error for a base outside the descriptor's directory, got %v", err)
+	}
+	options = LoadOptions{Base: filepath.Join(directory, "tables")}
+	_, err = LoadSchemaWithOptions(filepath.Join(directory, "tables", "accounts.json"), options)
+	if err == nil || !strings.Contains(err.Error(), "../base.json: ../base.json is outside") {
+		t.Errorf("expected an error for a base outside the directory, got %v", err)
+	}
+	_, err = LoadSchemaWithOptions(filepath.Join(directory, "base.json"), options)
+	if err == nil || !strings.Contains(err.Error(), "base.json is outside") {
+		t.Errorf("expected an error for a descriptor outside the directory, got %v", err)
+	}
+}
+
+func TestResolveSchemaErrors(t *testing.T) {
+	directory := writeDescriptors(t, map[string]string{
+		"a.json":            `{"extends": "b.json", "fields": [{"name": "a"}]}`,
+		"b.json":            `{"include": ["a.json"]}`,
+		"self.json":         `{"include": ["./self.json"]}`,
+		"base.json":         `{"fields": [{"name": "id", "type": "integer"}]}`,
+		"conflict.json":     `{"extends": "base.json", "fields": [{"name": "id", "type": "string"}]}`,
+		"unknown.json":      `{"extends": "base.json", "overrides": {"code": {"title": "Code"}}}`,
+		"type.json":         `{"extends": "base.json", "overrides": {"id": {"type": "number"}}}`,
+		"missing.json":      `{"extends": "nowhere.json"}`,
+		"include.json":      `{"include": "base.json"}`,
+		"bad-override.json": `{"extends": "base.json", "overrides": {"id": {"constraints": {"minimum": "low"}}}}`,
+	})
+
+	testCases := []struct {
+		path    string
+		wantErr string
+	}{
+		{path: "a.json", wantErr: "a descriptor refers to itself: a.json -> b.json -> a.json"},
+		{path: "self.json", wantErr: "a descriptor refers to itself: self.json -> self.json"},
+		{path: "conflict.json", wantErr: "base.json and conflict.json both define the field id, differently"},
+		{path: "unknown.json", wantErr: "overrides code, which isn't a field"},
+		{path: "type.json", wantErr: "could not override id: an override can't change a field's type"},
+		{path: "missing.json", wantErr: "nowhere.json: open "},
+		{path: "include.json", wantErr: "invalid include"},
+		{path: "bad-override.json", wantErr: "invalid min constraint"},
+	}
+
+	for _, testCase := range testCases {
+		_, err := ResolveSchema(filepath.Join(directory, testCase.path))
+		if err == nil || !strings.Contains(err.Error(), testCase.wantErr) {
+			t.Errorf("%s: expected an error containing %q, got %v", testCase.path, testCase.wantErr, err)
+		}
+	}
+}
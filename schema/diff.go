@@ -0,0 +1,201 @@
+package schema
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// A ChangeKind is the kind of difference between two versions of a schema.
+type ChangeKind string
+
+const (
+	FieldAdded          ChangeKind = "field-added"
+	FieldRemoved        ChangeKind = "field-removed"
+	FieldRenamed        ChangeKind = "field-renamed"
+	TypeChanged         ChangeKind = "type-changed"
+	ConstraintTightened ChangeKind = "constraint-tightened"
+	ConstraintLoosened  ChangeKind = "constraint-loosened"
+	// ConstraintChanged is a change to a constraint that is neither tighter nor looser, e.g. a new pattern.
+	ConstraintChanged ChangeKind = "constraint-changed"
+)
+
+// A Change is a difference between two versions of a schema. A change is Breaking if a consumer of data
+// that relied on the old schema could be given data it doesn't expect, e.g. because a field it reads was
+// removed or a constraint it relies on was loosened. Tightening a constraint isn't breaking, since data
+// that meets the new schema also meets the old one.
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	// Field is the name of the field in the old schema, or in the new schema if it was added.
+	Field string `json:"field"`
+	// Constraint is the name of the constraint that changed, if it was a constraint.
+	Constraint string `json:"constraint,omitempty"`
+	// Old and New are what changed, e.g. the field's type or the constraint's value. Old is unset if the
+	// constraint was added, and New if it was removed.
+	Old      any    `json:"old,omitempty"`
+	New      any    `json:"new,omitempty"`
+	Breaking bool   `json:"breaking"`
+	Message  string `json:"message"`
+}
+
+// HasBreakingChanges reports whether any of changes is breaking.
+func HasBreakingChanges(changes []Change) bool {
+	return slices.ContainsFunc(changes, func(change Change) bool { return change.Breaking })
+}
+
+// isNarrowerType reports whether every valid value of newType is a valid value of oldType, so that changing
+// a field's type from oldType to newType can't give a consumer a value it can't read. Every value is text,
+// so a string field's type can be narrowed to anything.
+func isNarrowerType(oldType string, newType string) bool {
+	return oldType == "string" || (oldType == "number" && newType == "integer")
+}
+
+// normaliseConstraints drops constraints which, though selected, don't constrain anything, e.g. required
+// false, so that they compare equal to constraints that aren't there.
+func normaliseConstraints(constraints map[string]any) map[string]any {
+	normalised := make(map[string]any)
+	for key, value := range constraints {
+		if value == false {
+			continue
+		}
+		normalised[key] = value
+	}
+	return normalised
+}
+
+// compareConstraint returns whether a constraint present in both versions of a field was tightened,
+// loosened or otherwise changed.
+func compareConstraint(key string, oldValue any, newValue any) ChangeKind {
+	switch oldTyped := oldValue.(type) {
+	case int64:
+		newTyped := newValue.(int64)
+		// a higher minimum is tighter, as is a lower maximum
+		if strings.HasPrefix(key, "min") == (newTyped > oldTyped) {
+			return ConstraintTightened
+		}
+		return ConstraintLoosened
+	case []string:
+		newTyped := newValue.([]string)
+		isSubset := func(subset []string, superset []string) bool {
+			return !slices.ContainsFunc(subset, func(value string) bool { return !slices.Contains(superset, value) })
+		}
+		if isSubset(newTyped, oldTyped) {
+			return ConstraintTightened
+		}
+		if isSubset(oldTyped, newTyped) {
+			return ConstraintLoosened
+		}
+	}
+	return ConstraintChanged
+}
+
+// compareConstraints returns the changes between the constraints of two versions of a field.
+func compareConstraints(field string, oldField FieldDescriptor, newField FieldDescriptor) []Change {
+	var changes []Change
+
+	oldConstraints := normaliseConstraints(oldField.Constraints)
+	newConstraints := normaliseConstraints(newField.Constraints)
+	keys := slices.Sorted(maps.Keys(oldConstraints))
+	for _, key := range slices.Sorted(maps.Keys(newConstraints)) {
+		if _, ok := oldConstraints[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	for _, key := range keys {
+		oldValue, hadConstraint := oldConstraints[key]
+		newValue, hasConstraint := newConstraints[key]
+
+		change := Change{Field: field, Constraint: key, Old: oldValue, New: newValue}
+		switch {
+		case !hasConstraint:
+			change.Kind = ConstraintLoosened
+			change.Message = fmt.Sprintf("the %s constraint of %s was removed", key, field)
+		case !hadConstraint:
+			change.Kind = ConstraintTightened
+			change.Message = fmt.Sprintf("%s was given a %s constraint of %v", field, key, newValue)
+		case reflect.DeepEqual(oldValue, newValue):
+			continue
+		default:
+			change.Kind = compareConstraint(key, oldValue, newValue)
+			change.Message = fmt.Sprintf("the %s constraint of %s was changed from %v to %v", key, field, oldValue, newValue)
+		}
+		change.Breaking = change.Kind != ConstraintTightened
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// isRenameOf reports whether a field that was added could be a field that was removed under a new name,
+// because it has the same type and constraints.
+func isRenameOf(added FieldDescriptor, removed FieldDescriptor) bool {
+	return added.FieldType == removed.FieldType &&
+		reflect.DeepEqual(normaliseConstraints(added.Constraints), normaliseConstraints(removed.Constraints))
+}
+
+// Compare returns the changes made to the fields of old to give updated. Fields are matched by name. A field
+// that was removed is reported as renamed if a field with the same type and constraints was added, though
+// only a person can tell whether it really was.
+func Compare(old Schema, updated Schema) []Change {
+	var changes []Change
+
+	oldFields := old.Fields.List()
+	newFields := updated.Fields.List()
+	newByName := make(map[string]FieldDescriptor)
+	for _, field := range newFields {
+		newByName[field.Name] = field
+	}
+	oldByName := make(map[string]FieldDescriptor)
+	for _, field := range oldFields {
+		oldByName[field.Name] = field
+	}
+
+	var added []FieldDescriptor
+	for _, field := range newFields {
+		if _, ok := oldByName[field.Name]; !ok {
+			added = append(added, field)
+		}
+	}
+
+	for _, oldField := range oldFields {
+		newField, ok := newByName[oldField.Name]
+		if !ok {
+			renamed := slices.IndexFunc(added, func(candidate FieldDescriptor) bool { return isRenameOf(candidate, oldField) })
+			if renamed >= 0 {
+				changes = append(changes, Change{
+					Kind: FieldRenamed, Field: oldField.Name, Old: oldField.Name, New: added[renamed].Name, Breaking: true,
+					Message: fmt.Sprintf("%s was removed, and may have been renamed to %s", oldField.Name, added[renamed].Name),
+				})
+				added = slices.Delete(added, renamed, renamed+1)
+				continue
+			}
+			changes = append(changes, Change{
+				Kind: FieldRemoved, Field: oldField.Name, Breaking: true,
+				Message: fmt.Sprintf("%s was removed", oldField.Name),
+			})
+			continue
+		}
+
+		if oldField.FieldType != newField.FieldType {
+			changes = append(changes, Change{
+				Kind: TypeChanged, Field: oldField.Name, Old: oldField.FieldType, New: newField.FieldType,
+				Breaking: !isNarrowerType(oldField.FieldType, newField.FieldType),
+				Message:  fmt.Sprintf("the type of %s was changed from %s to %s", oldField.Name, oldField.FieldType, newField.FieldType),
+			})
+		}
+		changes = append(changes, compareConstraints(oldField.Name, oldField, newField)...)
+	}
+
+	// a new field can't break a consumer, which doesn't know to read it
+	for _, field := range added {
+		changes = append(changes, Change{
+			Kind: FieldAdded, Field: field.Name, New: field.FieldType,
+			Message: fmt.Sprintf("%s was added, with type %s", field.Name, field.FieldType),
+		})
+	}
+
+	return changes
+}
@@ -0,0 +1,74 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCompare(t *testing.T) {
+	old := MakeSchema(SchemaOptions{Fields: Fields{
+		StringFields: []StringField{
+			{FieldBase: FieldBase{Name: "id"}, Constraints: StringConstraints{Required: RequiredConstraint{Selected: true, Value: true}}},
+			{FieldBase: FieldBase{Name: "size"}, Constraints: StringConstraints{Enum: EnumConstraint{Selected: true, Value: []string{"S", "M", "L"}}}},
+			{FieldBase: FieldBase{Name: "code"}, Constraints: StringConstraints{Pattern: PatternConstraint{Selected: true, Value: "[A-Z]+"}}},
+			{FieldBase: FieldBase{Name: "note"}},
+		},
+		NumberFields: []NumberField{
+			{FieldBase: FieldBase{Name: "score"}, Constraints: NumberConstraints{Min: MinConstraint{Selected: true, Value: 0}, Max: MaxConstraint{Selected: true, Value: 10}}},
+			{FieldBase: FieldBase{Name: "amount"}},
+		},
+		BooleanFields: []BooleanField{
+			{FieldBase: FieldBase{Name: "active"}},
+		},
+	}})
+	updated := MakeSchema(SchemaOptions{Fields: Fields{
+		StringFields: []StringField{
+			{FieldBase: FieldBase{Name: "id"}, Constraints: StringConstraints{Required: RequiredConstraint{Selected: true, Value: false}}},
+			{FieldBase: FieldBase{Name: "size"}, Constraints: StringConstraints{Enum: EnumConstraint{Selected: true, Value: []string{"S", "M"}}}},
+			{FieldBase: FieldBase{Name: "code"}, Constraints: StringConstraints{Pattern: PatternConstraint{Selected: true, Value: "[a-z]+"}}},
+			{FieldBase: FieldBase{Name: "comment"}},
+			{FieldBase: FieldBase{Name: "active"}},
+		},
+		NumberFields: []NumberField{
+			{FieldBase: FieldBase{Name: "score"}, Constraints: NumberConstraints{Min: MinConstraint{Selected: true, Value: 1}, Max: MaxConstraint{Selected: true, Value: 20}}},
+		},
+		IntegerFields: []IntegerField{
+			{FieldBase: FieldBase{Name: "amount"}},
+			{FieldBase: FieldBase{Name: "count"}, Constraints: IntegerConstraints{Required: RequiredConstraint{Selected: true, Value: true}}},
+		},
+	}})
+
+	want := []Change{
+		{Kind: ConstraintLoosened, Field: "id", Constraint: "required", Old: true, Breaking: true, Message: "the required constraint of id was removed"},
+		{Kind: ConstraintTightened, Field: "size", Constraint: "enum", Old: []string{"S", "M", "L"}, New: []string{"S", "M"}, Message: "the enum constraint of size was changed from [S M L] to [S M]"},
+		{Kind: ConstraintChanged, Field: "code", Constraint: "pattern", Old: "[A-Z]+", New: "[a-z]+", Breaking: true, Message: "the pattern constraint of code was changed from [A-Z]+ to [a-z]+"},
+		{Kind: FieldRenamed, Field: "note", Old: "note", New: "comment", Breaking: true, Message: "note was removed, and may have been renamed to comment"},
+		{Kind: ConstraintLoosened, Field: "score", Constraint: "max", Old: int64(10), New: int64(20), Breaking: true, Message: "the max constraint of score was changed from 10 to 20"},
+		{Kind: ConstraintTightened, Field: "score", Constraint: "min", Old: int64(0), New: int64(1), Message: "the min constraint of score was changed from 0 to 1"},
+		{Kind: TypeChanged, Field: "amount", Old: "number", New: "integer", Message: "the type of amount was changed from number to integer"},
+		{Kind: TypeChanged, Field: "active", Old: "boolean", New: "string", Breaking: true, Message: "the type of active was changed from boolean to string"},
+		{Kind: FieldAdded, Field: "count", New: "integer", Message: "count was added, with type integer"},
+	}
+
+	got := Compare(old, updated)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+	if !HasBreakingChanges(got) {
+		t.Errorf("expected the changes to be breaking")
+	}
+
+	if changes := Compare(old, old); len(changes) != 0 {
+		t.Errorf("expected no changes between a schema and itself, got %v", changes)
+	}
+	removed := Compare(updated, MakeSchema(SchemaOptions{Fields: Fields{StringFields: updated.Fields.StringFields}}))
+	wantRemoved := []Change{
+		{Kind: FieldRemoved, Field: "score", Breaking: true, Message: "score was removed"},
+		{Kind: FieldRemoved, Field: "amount", Breaking: true, Message: "amount was removed"},
+		{Kind: FieldRemoved, Field: "count", Breaking: true, Message: "count was removed"},
+	}
+	if diff := cmp.Diff(wantRemoved, removed); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
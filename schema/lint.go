@@ -0,0 +1,188 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// A Severity is how serious a Finding is.
+type Severity string
+
+const (
+	// SeverityError is for a field that no value can be valid in, or whose constraints make no sense.
+	SeverityError Severity = "error"
+	// SeverityWarning is for constraints which can be met, but probably aren't what was meant.
+	SeverityWarning Severity = "warning"
+)
+
+// A Finding is a problem Lint found with a schema.
+type Finding struct {
+	Severity Severity `json:"severity"`
+	// Path is a JSON pointer to the part of the schema's descriptor at fault, e.g. /fields/0/constraints/enum.
+	Path    string `json:"path"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// enumValueProblem returns why value, from the enum constraint of field, could never be valid, or "" if it could.
+func enumValueProblem(field FieldDescriptor, value string, pattern *regexp.Regexp) string {
+	length := int64(utf8.RuneCountInString(value))
+	if minLength, ok := field.Constraints["minLength"].(int64); ok && length < minLength {
+		return fmt.Sprintf("is shorter than the minLength of %d", minLength)
+	}
+	if maxLength, ok := field.Constraints["maxLength"].(int64); ok && length > maxLength {
+		return fmt.Sprintf("is longer than the maxLength of %d", maxLength)
+	}
+	if pattern != nil && !pattern.MatchString(value) {
+		return fmt.Sprintf("does not match the pattern %s", field.Constraints["pattern"])
+	}
+	if field.FieldType == "boolean" && !IsBoolean(value) {
+		return "is not a boolean"
+	}
+	return ""
+}
+
+// lintField returns the findings for the field at index.
+func lintField(index int, field FieldDescriptor) []Finding {
+	var findings []Finding
+	add := func(severity Severity, constraint string, format string, args ...any) {
+		findings = append(findings, Finding{
+			Severity: severity,
+			Path:     fmt.Sprintf("/fields/%d/constraints/%s", index, constraint),
+			Field:    field.Name,
+			Message:  field.Name + " " + fmt.Sprintf(format, args...),
+		})
+	}
+
+	minimum, hasMinimum := field.Constraints["min"].(int64)
+	maximum, hasMaximum := field.Constraints["max"].(int64)
+	if hasMinimum && hasMaximum && minimum > maximum {
+		add(SeverityError, "min", "has a min of %d, which is greater than its max of %d, so no value can be valid", minimum, maximum)
+	}
+
+	minLength, hasMinLength := field.Constraints["minLength"].(int64)
+	maxLength, hasMaxLength := field.Constraints["maxLength"].(int64)
+	if hasMinLength && hasMaxLength && minLength > maxLength {
+		add(SeverityError, "minLength", "has a minLength of %d, which is greater than its maxLength of %d, so no value can be valid", minLength, maxLength)
+	}
+	required, _ := field.Constraints["required"].(bool)
+	if required && hasMaxLength && maxLength == 0 && field.FieldType == "string" {
+		add(SeverityError, "maxLength", "is required, but has a maxLength of 0, so no value can be valid")
+	}
+
+	var pattern *regexp.Regexp
+	if source, ok := field.Constraints["pattern"].(string); ok {
+		if _, err := regexp.Compile(source); err != nil {
+			add(SeverityError, "pattern", "has a pattern %s which is not a valid regular expression: %s", source, err.Error())
+		} else {
+			// a pattern has to match the whole of a value, not just part of it
+			pattern = regexp.MustCompile("^(?:" + source + ")$")
+		}
+	}
+
+	if enum, ok := field.Constraints["enum"].([]string); ok {
+		invalid := 0
+		for _, value := range enum {
+			if problem := enumValueProblem(field, value, pattern); problem != "" {
+				invalid++
+				add(SeverityWarning, "enum", "has the enum value %q, which %s, so can never be valid", value, problem)
+			}
+		}
+		if len(enum) > 0 && invalid == len(enum) {
+			add(SeverityError, "enum", "has no enum value that can be valid, so no value can be")
+		}
+		if len(enum) == 1 {
+			add(SeverityWarning, "enum", "has an enum of one value, so every row has to have the same value")
+		}
+	}
+
+	unique, _ := field.Constraints["unique"].(bool)
+	if unique && !required {
+		add(SeverityWarning, "unique", "is unique but not required, so at most one row can leave it empty")
+	}
+
+	for _, constraint := range field.IgnoredConstraints {
+		if constraint == "unique" && field.FieldType == "boolean" {
+			add(SeverityWarning, "unique", "is a boolean, which can't be unique, since a table could only have two rows")
+		} else {
+			add(SeverityWarning, strings.NewReplacer("~", "~0", "/", "~1").Replace(constraint), "has a %s constraint, which a %s field can't have, so it's ignored", constraint, field.FieldType)
+		}
+	}
+
+	return findings
+}
+
+// lintKeys returns the findings for the schema's primary, unique and foreign keys: names that aren't the names
+// of fields, which no row could satisfy, and primary key fields that aren't required.
+func lintKeys(schema Schema) []Finding {
+	var findings []Finding
+	fields := make(map[string]FieldDescriptor)
+	for _, field := range schema.Fields.List() {
+		fields[field.Name] = field
+	}
+	checkNames := func(path string, names FieldNames) {
+		for index, name := range names {
+			if _, ok := fields[name]; !ok {
+				findings = append(findings, Finding{
+					Severity: SeverityError,
+					Path:     fmt.Sprintf("%s/%d", path, index),
+					Field:    name,
+					Message:  fmt.Sprintf("%s is not the name of a field", name),
+				})
+			}
+		}
+	}
+
+	checkNames("/primaryKey", schema.PrimaryKey)
+	for index, name := range schema.PrimaryKey {
+		field, ok := fields[name]
+		if required, _ := field.Constraints["required"].(bool); ok && !required {
+			findings = append(findings, Finding{
+				Severity: SeverityWarning,
+				Path:     fmt.Sprintf("/primaryKey/%d", index),
+				Field:    name,
+				Message:  fmt.Sprintf("%s is part of the primary key, so can't be empty, but isn't required", name),
+			})
+		}
+	}
+
+	for index, key := range schema.UniqueKeys {
+		checkNames(fmt.Sprintf("/uniqueKeys/%d", index), key)
+	}
+
+	for index, key := range schema.ForeignKeys {
+		path := fmt.Sprintf("/foreignKeys/%d", index)
+		checkNames(path+"/fields", key.Fields)
+		if key.Reference.Resource == "" {
+			checkNames(path+"/reference/fields", key.Reference.Fields)
+		}
+		if len(key.Fields) != len(key.Reference.Fields) {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Path:     path + "/reference/fields",
+				Message:  fmt.Sprintf("foreign key %d has %d fields, but refers to %d", index, len(key.Fields), len(key.Reference.Fields)),
+			})
+		}
+	}
+	return findings
+}
+
+// Lint returns the ways schema's constraints contradict each other, such as a min greater than its max or
+// an enum value that doesn't match the pattern, which make values that should be valid impossible, constraints
+// that a field's type doesn't have, such as unique on a boolean, and keys that name fields the schema doesn't
+// have. A schema can match the tableschema profile, as Validate checks, and still have these problems.
+func Lint(schema Schema) []Finding {
+	var findings []Finding
+	for index, field := range schema.Fields.List() {
+		findings = append(findings, lintField(index, field)...)
+	}
+	return append(findings, lintKeys(schema)...)
+}
+
+// HasErrors reports whether any of findings is an error rather than a warning.
+func HasErrors(findings []Finding) bool {
+	return slices.ContainsFunc(findings, func(finding Finding) bool { return finding.Severity == SeverityError })
+}
@@ -0,0 +1,124 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLint(t *testing.T) {
+	required := RequiredConstraint{Selected: true, Value: true}
+	schema := MakeSchema(SchemaOptions{Fields: Fields{
+		StringFields: []StringField{
+			{
+				FieldBase: FieldBase{Name: "foo"},
+				Constraints: StringConstraints{
+					Required: required,
+					Enum:     EnumConstraint{Selected: true, Value: []string{"bar", "baz"}},
+					Pattern:  PatternConstraint{Selected: true, Value: "ba[rx]"},
+				},
+			},
+			{
+				FieldBase: FieldBase{Name: "bar"},
+				Constraints: StringConstraints{
+					MinLength: MinLengthConstraint{Selected: true, Value: 10},
+					Required:  required,
+					Enum:      EnumConstraint{Selected: true, Value: []string{"bar", "baz"}},
+				},
+			},
+			{
+				FieldBase: FieldBase{Name: "code"},
+				Constraints: StringConstraints{
+					Required:  required,
+					Unique:    UniqueContraint{Selected: true, Value: true},
+					Pattern:   PatternConstraint{Selected: true, Value: "[A-Z"},
+					MinLength: MinLengthConstraint{Selected: true, Value: 3},
+					MaxLength: MaxLengthConstraint{Selected: true, Value: 2},
+				},
+			},
+		},
+		NumberFields: []NumberField{
+			{FieldBase: FieldBase{Name: "score"}, Constraints: NumberConstraints{Min: MinConstraint{Selected: true, Value: 11}, Max: MaxConstraint{Selected: true, Value: 10}}},
+		},
+		BooleanFields: []BooleanField{
+			{FieldBase: FieldBase{Name: "active"}, Constraints: BooleanConstraints{Enum: EnumConstraint{Selected: true, Value: []string{"yes"}}}},
+		},
+		IntegerFields: []IntegerField{
+			{FieldBase: FieldBase{Name: "id"}, Constraints: IntegerConstraints{Unique: UniqueContraint{Selected: true, Value: true}, Min: MinConstraint{Selected: true, Value: 1}, Max: MaxConstraint{Selected: true, Value: 1}}},
+		},
+	}})
+
+	want := []Finding{
+		{Severity: SeverityWarning, Path: "/fields/0/constraints/enum", Field: "foo", Message: `foo has the enum value "baz", which does not match the pattern ba[rx], so can never be valid`},
+		{Severity: SeverityWarning, Path: "/fields/1/constraints/enum", Field: "bar", Message: `bar has the enum value "bar", which is shorter than the minLength of 10, so can never be valid`},
+		{Severity: SeverityWarning, Path: "/fields/1/constraints/enum", Field: "bar", Message: `bar has the enum value "baz", which is shorter than the minLength of 10, so can never be valid`},
+		{Severity: SeverityError, Path: "/fields/1/constraints/enum", Field: "bar", Message: "bar has no enum value that can be valid, so no value can be"},
+		{Severity: SeverityError, Path: "/fields/2/constraints/minLength", Field: "code", Message: "code has a minLength of 3, which is greater than its maxLength of 2, so no value can be valid"},
+		{Severity: SeverityError, Path: "/fields/2/constraints/pattern", Field: "code", Message: "code has a pattern [A-Z which is not a valid regular expression: error parsing regexp: missing closing ]: `[A-Z`"},
+		{Severity: SeverityError, Path: "/fields/3/constraints/min", Field: "score", Message: "score has a min of 11, which is greater than its max of 10, so no value can be valid"},
+		{Severity: SeverityWarning, Path: "/fields/4/constraints/enum", Field: "active", Message: `active has the enum value "yes", which is not a boolean, so can never be valid`},
+		{Severity: SeverityError, Path: "/fields/4/constraints/enum", Field: "active", Message: "active has no enum value that can be valid, so no value can be"},
+		{Severity: SeverityWarning, Path: "/fields/4/constraints/enum", Field: "active", Message: "active has an enum of one value, so every row has to have the same value"},
+		{Severity: SeverityWarning, Path: "/fields/5/constraints/unique", Field: "id", Message: "id is unique but not required, so at most one row can leave it empty"},
+	}
+
+	got := Lint(schema)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+	if !HasErrors(got) {
+		t.Errorf("expected the findings to include errors")
+	}
+
+	if findings := Lint(MakeSchema(SchemaOptions{Fields: Fields{StringFields: []StringField{{FieldBase: FieldBase{Name: "id"}}}}})); len(findings) != 0 {
+		t.Errorf("expected no findings, got %v", findings)
+	}
+}
+
+func TestLintKeys(t *testing.T) {
+	required := RequiredConstraint{Selected: true, Value: true}
+	schema := MakeSchema(SchemaOptions{
+		Fields: Fields{
+			StringFields: []StringField{
+				{FieldBase: FieldBase{Name: "id"}, Constraints: StringConstraints{Required: required}},
+				{FieldBase: FieldBase{Name: "team"}},
+				{FieldBase: FieldBase{Name: "parent"}},
+			},
+		},
+		PrimaryKey: FieldNames{"id", "team"},
+		UniqueKeys: []FieldNames{{"team", "email"}},
+		ForeignKeys: []ForeignKey{
+			{Fields: FieldNames{"team"}, Reference: ForeignKeyReference{Resource: "teams", Fields: FieldNames{"id", "name"}}},
+			{Fields: FieldNames{"parent"}, Reference: ForeignKeyReference{Fields: FieldNames{"key"}}},
+		},
+	})
+
+	want := []Finding{
+		{Severity: SeverityWarning, Path: "/primaryKey/1", Field: "team", Message: "team is part of the primary key, so can't be empty, but isn't required"},
+		{Severity: SeverityError, Path: "/uniqueKeys/0/1", Field: "email", Message: "email is not the name of a field"},
+		{Severity: SeverityError, Path: "/foreignKeys/0/reference/fields", Message: "foreign key 0 has 1 fields, but refers to 2"},
+		{Severity: SeverityError, Path: "/foreignKeys/1/reference/fields/0", Field: "key", Message: "key is not the name of a field"},
+	}
+
+	if diff := cmp.Diff(want, Lint(schema)); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestLintIgnoredConstraints(t *testing.T) {
+	schema, err := ParseSchema([]byte(`{"fields": [
+  {"name": "active", "type": "boolean", "constraints": {"unique": true, "required": true}},
+  {"name": "joined", "type": "date", "constraints": {"min": "2024-01-01"}}
+]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Finding{
+		{Severity: SeverityWarning, Path: "/fields/0/constraints/unique", Field: "active", Message: "active is a boolean, which can't be unique, since a table could only have two rows"},
+		{Severity: SeverityWarning, Path: "/fields/1/constraints/min", Field: "joined", Message: "joined has a min constraint, which a date field can't have, so it's ignored"},
+	}
+	if diff := cmp.Diff(want, Lint(schema)); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
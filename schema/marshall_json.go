@@ -7,38 +7,19 @@ import (
 	"strings"
 )
 
-func trimCharacters(array string, prefix string, suffix string) string {
-	if strings.HasPrefix(array, prefix) {
-		array = array[1:]
-	}
-
-	if strings.HasSuffix(array, suffix) {
-		array = array[:len(array)-1]
-	}
-	return array
-}
-
 // Fields.MarshalJSON turns a Fields object created by this package into a valid
 // tableschema json string. The values of the Fields struct are separated from their
-// keys and turned into a mixed-type json list.  
+// keys and turned into a mixed-type json list, in the order of Fields.Order.  
 func (fields Fields) MarshalJSON() ([]byte, error) {
 	var fieldStrings []string
 
-	val := reflect.ValueOf(fields)
-
-	for i := 0; i < val.NumField(); i++ {
-		field := val.Field(i).Interface()
-		fieldMarshalled, err := json.Marshal(field)
+	for _, field := range fields.values() {
+		fieldMarshalled, err := json.Marshal(field.Interface())
 		if err != nil {
 			return nil, err
 		}
 
-		jsonValue := string(fieldMarshalled)
-
-		if jsonValue != `null` {
-			fieldStrings = append(fieldStrings, trimCharacters(jsonValue, `[`, `]`))
-		}
-
+		fieldStrings = append(fieldStrings, string(fieldMarshalled))
 	}
 
 	marshalled := fmt.Sprintf(`[%s]`, strings.Join(fieldStrings, `,`))
@@ -56,7 +37,7 @@ func (constraint Constraint[any]) MarshalJSON() ([]byte, error) {
 	return json.Marshal(constraint.Value)
 }
 
-func constraintsMarshaller[anyConstraintSet StringConstraints | NumberConstraints | BooleanConstraints | ListConstraints](constraints anyConstraintSet) ([]byte, error) {
+func constraintsMarshaller[anyConstraintSet StringConstraints | NumberConstraints | BooleanConstraints | ListConstraints | IntegerConstraints | DateConstraints | DateTimeConstraints](constraints anyConstraintSet) ([]byte, error) {
 	var fields []string
 
 	val := reflect.ValueOf(constraints)
@@ -99,3 +80,15 @@ func (constraints BooleanConstraints) MarshalJSON() ([]byte, error) {
 func (constraints ListConstraints) MarshalJSON() ([]byte, error) {
 	return constraintsMarshaller(constraints)
 }
+
+func (constraints IntegerConstraints) MarshalJSON() ([]byte, error) {
+	return constraintsMarshaller(constraints)
+}
+
+func (constraints DateConstraints) MarshalJSON() ([]byte, error) {
+	return constraintsMarshaller(constraints)
+}
+
+func (constraints DateTimeConstraints) MarshalJSON() ([]byte, error) {
+	return constraintsMarshaller(constraints)
+}
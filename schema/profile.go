@@ -0,0 +1,148 @@
+package schema
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// profile is the JSON schema of a tableschema descriptor, the one MakeSchema's $schema refers to, embedded
+// so that schemas can be checked without fetching it.
+//
+//go:embed profile/tableschema.json
+var profile []byte
+
+var loadProfile = sync.OnceValues(func() (*gojsonschema.Schema, error) {
+	return gojsonschema.NewSchema(gojsonschema.NewBytesLoader(profile))
+})
+
+// A Violation is a way in which a schema's descriptor breaks the tableschema profile.
+type Violation struct {
+	// Path is a JSON pointer to the part of the descriptor at fault, e.g. /fields/0/constraints/enum.
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// A ProfileError lists every Violation found in a schema.
+type ProfileError struct {
+	Violations []Violation
+}
+
+func (err *ProfileError) Error() string {
+	var violations []string
+	for _, violation := range err.Violations {
+		violations = append(violations, violation.Path+": "+violation.Message)
+	}
+	return "schema does not match the tableschema profile: " + strings.Join(violations, "; ")
+}
+
+// jsonPointer turns the path gojsonschema reports a violation at into a JSON pointer, escaping ~ and / in
+// its tokens.
+func jsonPointer(context *gojsonschema.JsonContext) string {
+	// joined with a character that can't be in a descriptor's keys, so that tokens are kept apart
+	tokens := strings.Split(context.String("\x00"), "\x00")[1:]
+	pointer := ""
+	for _, token := range tokens {
+		pointer += "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+	}
+	return pointer
+}
+
+// fieldTypes are the types of field that this package can describe.
+var fieldTypes = []string{"string", "number", "boolean", "list", "integer", "date", "datetime"}
+
+// isNoise reports whether a violation gojsonschema reports only follows from another. A field has to
+// match one of the profile's field definitions, which are chosen between by type, so a field that breaks
+// its definition is also reported as matching none of them, and as having the type of whichever other
+// definition gojsonschema found the closest match.
+func isNoise(result gojsonschema.ResultError, path string, descriptor map[string]any) bool {
+	if result.Type() == "number_one_of" || result.Type() == "number_any_of" {
+		return true
+	}
+	var index int
+	if _, err := fmt.Sscanf(path, "/fields/%d/type", &index); err != nil || path != fmt.Sprintf("/fields/%d/type", index) {
+		return false
+	}
+	fields, _ := descriptor["fields"].([]any)
+	if index >= len(fields) {
+		return false
+	}
+	field, _ := fields[index].(map[string]any)
+	fieldType, _ := field["type"].(string)
+	return slices.Contains(fieldTypes, fieldType)
+}
+
+// Validate checks schema's descriptor against the tableschema profile, and for problems the profile can't
+// describe: patterns that aren't valid regular expressions, and fields that share a name. It returns a
+// *ProfileError listing every problem found, or nil if there are none.
+func Validate(schema Schema) error {
+	compiled, err := loadProfile()
+	if err != nil {
+		return fmt.Errorf("could not load the tableschema profile: %w", err)
+	}
+
+	descriptor, err := json.Marshal(schema)
+	if err != nil {
+		return err
+	}
+	result, err := compiled.Validate(gojsonschema.NewBytesLoader(descriptor))
+	if err != nil {
+		return err
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(descriptor, &decoded); err != nil {
+		return err
+	}
+
+	var violations []Violation
+	for _, resultErr := range result.Errors() {
+		path := jsonPointer(resultErr.Context())
+		if isNoise(resultErr, path, decoded) {
+			continue
+		}
+		violations = append(violations, Violation{Path: path, Message: resultErr.Description()})
+	}
+
+	firstIndexOf := make(map[string]int)
+	for index, field := range schema.Fields.List() {
+		if first, ok := firstIndexOf[field.Name]; ok {
+			violations = append(violations, Violation{
+				Path:    fmt.Sprintf("/fields/%d/name", index),
+				Message: fmt.Sprintf("%s is also the name of field %d", field.Name, first),
+			})
+		} else {
+			firstIndexOf[field.Name] = index
+		}
+
+		if pattern, ok := field.Constraints["pattern"].(string); ok {
+			if _, err := regexp.Compile(pattern); err != nil {
+				violations = append(violations, Violation{
+					Path:    fmt.Sprintf("/fields/%d/constraints/pattern", index),
+					Message: fmt.Sprintf("%s is not a valid regular expression: %s", pattern, err.Error()),
+				})
+			}
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ProfileError{Violations: violations}
+}
+
+// MakeValidSchema is MakeSchema for options that may not describe a valid schema. It returns a
+// *ProfileError listing every problem with the schema if it isn't valid.
+func MakeValidSchema(options SchemaOptions) (Schema, error) {
+	schema := MakeSchema(options)
+	if err := Validate(schema); err != nil {
+		return Schema{}, err
+	}
+	return schema, nil
+}
@@ -0,0 +1,62 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidate(t *testing.T) {
+	valid := MakeSchema(SchemaOptions{Fields: Fields{
+		StringFields: []StringField{
+			{FieldBase: FieldBase{Name: "id"}, Constraints: StringConstraints{Required: RequiredConstraint{Selected: true, Value: true}, Pattern: PatternConstraint{Selected: true, Value: "[A-Z]+"}}},
+		},
+		NumberFields:   []NumberField{{FieldBase: FieldBase{Name: "score"}, Constraints: NumberConstraints{Min: MinConstraint{Selected: true, Value: 0}}}},
+		BooleanFields:  []BooleanField{{FieldBase: FieldBase{Name: "active"}}},
+		ListFields:     []ListField{{FieldBase: FieldBase{Name: "tags"}}},
+		IntegerFields:  []IntegerField{{FieldBase: FieldBase{Name: "count"}}},
+		DateFields:     []DateField{{FieldBase: FieldBase{Name: "joined"}}},
+		DateTimeFields: []DateTimeField{{FieldBase: FieldBase{Name: "updated"}}},
+	}})
+	if err := Validate(valid); err != nil {
+		t.Errorf("expected a valid schema, got %s", err.Error())
+	}
+
+	testCases := []struct {
+		name    string
+		options SchemaOptions
+		want    []Violation
+	}{
+		{
+			name:    "no fields",
+			options: SchemaOptions{},
+			want:    []Violation{{Path: "/fields", Message: "Array must have at least 1 items"}},
+		},
+		{
+			name: "invalid constraints",
+			options: SchemaOptions{Fields: Fields{StringFields: []StringField{
+				{FieldBase: FieldBase{Name: "size"}, Constraints: StringConstraints{Enum: EnumConstraint{Selected: true, Value: []string{}}}},
+				{FieldBase: FieldBase{Name: "code"}, Constraints: StringConstraints{Pattern: PatternConstraint{Selected: true, Value: "[A-Z"}}},
+				{FieldBase: FieldBase{Name: "size"}},
+			}}},
+			want: []Violation{
+				{Path: "/fields/0/constraints/enum", Message: "Array must have at least 1 items"},
+				{Path: "/fields/1/constraints/pattern", Message: "[A-Z is not a valid regular expression: error parsing regexp: missing closing ]: `[A-Z`"},
+				{Path: "/fields/2/name", Message: "size is also the name of field 0"},
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		_, err := MakeValidSchema(testCase.options)
+		var profileErr *ProfileError
+		if !errors.As(err, &profileErr) {
+			t.Errorf("%s: expected a ProfileError, got %v", testCase.name, err)
+			continue
+		}
+		if diff := cmp.Diff(testCase.want, profileErr.Violations); diff != "" {
+			t.Errorf("%s (-want +got):\n%s", testCase.name, diff)
+		}
+	}
+}
@@ -0,0 +1,402 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"tableschema-validator/yaml"
+)
+
+// A Resolver loads schemas from the descriptors in the files under a base directory, following the references
+// between them. A reference is the path of a file, relative to the descriptor it's in, which can end in a JSON
+// pointer fragment picking out part of the file, e.g. datapackage.json#/resources/0/schema, or be only a
+// fragment, for part of the same file. References are followed:
+//
+//   - where a schema is given as a string rather than an object, as the Table Schema profile allows, which is
+//     the path of its descriptor;
+//   - in an object {"$ref": reference}, anywhere in a descriptor, which is replaced by what it refers to;
+//   - in the extends and include of a composed descriptor.
+//
+// A reference can't be to a file outside the base directory. Each file is read once, however many times it's
+// referred to, so a Resolver should be used for the schemas of files that change together.
+type Resolver struct {
+	// base is the absolute path of the directory references can't leave.
+	base string
+	// documents are the files read so far, decoded, by absolute path.
+	documents map[string]any
+	// stack is what is being resolved, each of which refers to the next.
+	stack []resolving
+}
+
+// resolving is a reference being resolved.
+type resolving struct {
+	// key is what is referred to, which is the same for each reference to it.
+	key string
+	// name is what is referred to, for messages.
+	name string
+}
+
+// cycleError is the error of resolving something that refers to itself. cycle is the names of what refers to
+// each other, starting and ending with the same one.
+type cycleError struct {
+	cycle []string
+}
+
+func (err cycleError) Error() string {
+	return "a descriptor refers to itself: " + strings.Join(err.cycle, " -> ")
+}
+
+// location is the file a reference is to, and the JSON pointer to the part of it.
+type location struct {
+	file    string
+	pointer string
+}
+
+func (location location) String() string {
+	if location.pointer == "" {
+		return filepath.Base(location.file)
+	}
+	return filepath.Base(location.file) + "#" + location.pointer
+}
+
+// NewResolver returns a Resolver for the descriptors in the files under the directory base.
+func NewResolver(base string) (*Resolver, error) {
+	absolute, err := filepath.Abs(base)
+	if err == nil {
+		absolute, err = filepath.EvalSymlinks(absolute)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Resolver{base: absolute, documents: map[string]any{}}, nil
+}
+
+// LoadOptions are how LoadSchemaWithOptions follows the references in a descriptor.
+type LoadOptions struct {
+	// Base is the directory references can't leave, which the descriptor has to be in. "" is the descriptor's
+	// own directory, so that only the files beside and below it can be referred to.
+	Base string
+}
+
+// ResolveSchema reads a Schema from the descriptor at path, which can end in a JSON pointer fragment, with a
+// Resolver, flattening the descriptors it's composed from into it. It's LoadSchemaWithOptions with the
+// default LoadOptions, so references can only be to the files in the descriptor's directory or below it.
+//
+// A descriptor can extend a base, given as a reference in "extends", include the fields of the field
+// libraries referred to in "include", and change the fields it gets from them in "overrides":
+//
+//	{
+//	  "extends": "base.json",
+//	  "include": ["libraries/audit.json"],
+//	  "fields": [{"name": "plan", "type": "string"}],
+//	  "overrides": {"tenant_id": {"title": "Tenant", "constraints": {"required": true}}}
+//	}
+//
+// The schema's fields are the base's, then each library's, then the descriptor's own. A field can only be
+// defined once, unless each definition is the same, e.g. when a library is included by both a descriptor and
+// its base. An override replaces the title, description, example or any other property of a field, apart from
+// its name and type, and adds to or replaces its constraints; a null removes one. The descriptor has its base's
+// keys and properties too, with its own uniqueKeys and foreignKeys added to the base's and anything else
+// replacing it, but only the fields of a library are included. A base or library can itself be composed, but
+// not from a descriptor that's composed from it.
+func ResolveSchema(path string) (Schema, error) {
+	return LoadSchemaWithOptions(path, LoadOptions{})
+}
+
+// LoadSchemaWithOptions reads a Schema from the descriptor at path, as ResolveSchema does, with a Resolver for
+// options.Base.
+func LoadSchemaWithOptions(path string, options LoadOptions) (Schema, error) {
+	file, fragment := path, ""
+	if _, err := os.Stat(path); err != nil && strings.Contains(path, "#") {
+		file, fragment, _ = strings.Cut(path, "#")
+		fragment = "#" + fragment
+	}
+	absolute, err := filepath.Abs(file)
+	if err != nil {
+		return Schema{}, err
+	}
+
+	base := options.Base
+	if base == "" {
+		base = filepath.Dir(absolute)
+	}
+	resolver, err := NewResolver(base)
+	if err != nil {
+		return Schema{}, err
+	}
+	return resolver.Load(absolute + fragment)
+}
+
+// within reports whether path is in directory, or is directory.
+func within(directory string, path string) bool {
+	relative, err := filepath.Rel(directory, path)
+	return err == nil && relative != ".." && !strings.HasPrefix(relative, ".."+string(filepath.Separator))
+}
+
+// Load returns the Schema of the descriptor at reference, relative to the base directory, flattening the
+// descriptors it's composed from into it. If the descriptor is in a data package, i.e. reference is to part
+// of a file with a list of resources, a foreign key's reference to another resource has to be to fields of
+// that resource's schema.
+func (resolver *Resolver) Load(reference string) (Schema, error) {
+	descriptor, err := resolver.descriptor(reference, "")
+	if err != nil {
+		return Schema{}, err
+	}
+	data, err := json.Marshal(descriptor)
+	if err != nil {
+		return Schema{}, err
+	}
+	tableSchema, err := ParseSchema(data)
+	if err != nil {
+		return Schema{}, err
+	}
+	if err := resolver.checkResources(reference, tableSchema); err != nil {
+		return Schema{}, err
+	}
+	return tableSchema, nil
+}
+
+// locate returns the location reference refers to from the file at from, or from the base directory if from
+// is "".
+func (resolver *Resolver) locate(reference string, from string) (location, error) {
+	path, pointer, _ := strings.Cut(reference, "#")
+	pointer, err := url.PathUnescape(pointer)
+	if err != nil || pointer != "" && !strings.HasPrefix(pointer, "/") {
+		return location{}, fmt.Errorf("the fragment of %s isn't a JSON pointer", reference)
+	}
+	if path == "" {
+		if from == "" {
+			return location{}, fmt.Errorf("%s refers to part of a file, but isn't in one", reference)
+		}
+		return location{file: from, pointer: pointer}, nil
+	}
+
+	file := filepath.FromSlash(path)
+	if !filepath.IsAbs(file) {
+		directory := resolver.base
+		if from != "" {
+			directory = filepath.Dir(from)
+		}
+		file = filepath.Join(directory, file)
+	}
+	// symbolic links are followed, so that they can't lead out of the base directory, and so that the file is
+	// compared with the base, which has its links followed too. A file that can't be found is left to be
+	// reported when it's read, but its directory is followed.
+	if resolved, err := filepath.EvalSymlinks(file); err == nil {
+		file = resolved
+	} else if directory, err := filepath.EvalSymlinks(filepath.Dir(file)); err == nil {
+		file = filepath.Join(directory, filepath.Base(file))
+	}
+	if !within(resolver.base, file) {
+		return location{}, fmt.Errorf("%s is outside %s, so can't be referred to", path, resolver.base)
+	}
+	return location{file: file, pointer: pointer}, nil
+}
+
+// document returns the json or YAML in the file at path, decoded, reading it if it hasn't been already.
+func (resolver *Resolver) document(path string) (any, error) {
+	if document, ok := resolver.documents[path]; ok {
+		return document, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if IsYAML(path) {
+		if data, err = yaml.ToJSON(data); err != nil {
+			return nil, fmt.Errorf("could not read schema: %w", err)
+		}
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, fmt.Errorf("could not read schema: %w", err)
+	}
+	resolver.documents[path] = document
+	return document, nil
+}
+
+// point returns the part of document at pointer, a JSON pointer.
+func point(document any, pointer string) (any, error) {
+	if pointer == "" {
+		return document, nil
+	}
+	value := document
+	tokens := strings.Split(pointer[1:], "/")
+	for index, token := range tokens {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		var ok bool
+		switch typed := value.(type) {
+		case map[string]any:
+			value, ok = typed[token]
+		case []any:
+			position, err := strconv.Atoi(token)
+			if ok = err == nil && position >= 0 && position < len(typed) && strconv.Itoa(position) == token; ok {
+				value = typed[position]
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("#%s has nothing at %s", pointer, "/"+strings.Join(tokens[:index+1], "/"))
+		}
+	}
+	return value, nil
+}
+
+// enter adds what is being resolved to the stack, returning a function that takes it off again. It's an error
+// for it to be on the stack already, since it would then refer to itself.
+func (resolver *Resolver) enter(key string, name string) (leave func(), err error) {
+	for index, entered := range resolver.stack {
+		if entered.key == key {
+			var cycle []string
+			for _, entered := range resolver.stack[index:] {
+				cycle = append(cycle, entered.name)
+			}
+			return nil, cycleError{cycle: append(cycle, name)}
+		}
+	}
+	resolver.stack = append(resolver.stack, resolving{key: key, name: name})
+	return func() { resolver.stack = resolver.stack[:len(resolver.stack)-1] }, nil
+}
+
+// nested adds reference to an error in resolving it, so that it says where it is, apart from a cycle's, which
+// names everything in it.
+func nested(reference string, err error) error {
+	if err == nil || errors.As(err, &cycleError{}) {
+		return err
+	}
+	return fmt.Errorf("%s: %w", reference, err)
+}
+
+// resolve returns the value reference refers to from the file at from, or from the base directory if from is
+// "", with the $refs in it replaced by what they refer to, and the file it's in. If it's a schema, a string is
+// the path of its descriptor, which is resolved in its place.
+func (resolver *Resolver) resolve(reference string, from string, isSchema bool) (any, string, error) {
+	location, err := resolver.locate(reference, from)
+	if err != nil {
+		return nil, "", err
+	}
+	leave, err := resolver.enter(location.file+"#"+location.pointer, location.String())
+	if err != nil {
+		return nil, "", err
+	}
+	defer leave()
+
+	document, err := resolver.document(location.file)
+	if err != nil {
+		return nil, "", err
+	}
+	value, err := point(document, location.pointer)
+	if err != nil {
+		return nil, "", err
+	}
+	if path, ok := value.(string); ok && isSchema {
+		value, file, err := resolver.resolve(path, location.file, true)
+		return value, file, nested(path, err)
+	}
+	value, err = resolver.expand(value, location.file)
+	return value, location.file, err
+}
+
+// expand returns a copy of value, from the file at file, with each {"$ref": reference} in it replaced by what
+// reference refers to.
+func (resolver *Resolver) expand(value any, file string) (any, error) {
+	switch typed := value.(type) {
+	case map[string]any:
+		if reference, ok := typed["$ref"].(string); ok {
+			if len(typed) > 1 {
+				return nil, fmt.Errorf("the $ref %s has other properties, which would be ignored", reference)
+			}
+			referred, _, err := resolver.resolve(reference, file, false)
+			return referred, nested(reference, err)
+		}
+		// in order, so that the first error is always the same one
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		expanded := make(map[string]any, len(typed))
+		for _, key := range keys {
+			var err error
+			if expanded[key], err = resolver.expand(typed[key], file); err != nil {
+				return nil, err
+			}
+		}
+		return expanded, nil
+	case []any:
+		expanded := make([]any, len(typed))
+		for index, item := range typed {
+			var err error
+			if expanded[index], err = resolver.expand(item, file); err != nil {
+				return nil, err
+			}
+		}
+		return expanded, nil
+	}
+	return value, nil
+}
+
+// checkResources checks that the foreign keys of tableSchema, whose descriptor is at reference, are to fields
+// of the resources they refer to, if the descriptor is part of a data package: a file with a list of resources.
+func (resolver *Resolver) checkResources(reference string, tableSchema Schema) error {
+	location, err := resolver.locate(reference, "")
+	if err != nil || location.pointer == "" {
+		return err
+	}
+	document, err := resolver.document(location.file)
+	if err != nil {
+		return err
+	}
+	dataPackage, _ := document.(map[string]any)
+	resources, ok := dataPackage["resources"].([]any)
+	if !ok {
+		return nil
+	}
+	packageName := filepath.Base(location.file)
+
+	for index, key := range tableSchema.ForeignKeys {
+		if key.Reference.Resource == "" {
+			continue
+		}
+		position := -1
+		for resourceIndex, resource := range resources {
+			if resource, ok := resource.(map[string]any); ok && resource["name"] == key.Reference.Resource {
+				position = resourceIndex
+			}
+		}
+		if position < 0 {
+			return fmt.Errorf("foreign key %d refers to the resource %s, which isn't in %s", index, key.Reference.Resource, packageName)
+		}
+		if _, ok := resources[position].(map[string]any)["schema"]; !ok {
+			return fmt.Errorf("foreign key %d refers to the resource %s, which has no schema", index, key.Reference.Resource)
+		}
+
+		schemaReference := fmt.Sprintf("#/resources/%d/schema", position)
+		descriptor, err := resolver.descriptor(schemaReference, location.file)
+		if err == nil {
+			var data []byte
+			if data, err = json.Marshal(descriptor); err == nil {
+				var referenced Schema
+				if referenced, err = ParseSchema(data); err == nil {
+					for _, name := range key.Reference.Fields {
+						if !slices.Contains(referenced.Fields.Names(), name) {
+							return fmt.Errorf("foreign key %d refers to %s, which isn't a field of the resource %s", index, name, key.Reference.Resource)
+						}
+					}
+				}
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("foreign key %d refers to the resource %s, whose schema can't be read: %w", index, key.Reference.Resource, err)
+		}
+	}
+	return nil
+}
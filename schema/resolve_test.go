@@ -0,0 +1,175 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResolverLoad(t *testing.T) {
+	directory := writeDescriptors(t, map[string]string{
+		"datapackage.json": `{
+  "name": "league",
+  "resources": [
+    {"name": "teams", "path": "teams.csv", "schema": "schemas/teams.json"},
+    {
+      "name": "players",
+      "path": "players.csv",
+      "schema": {
+        "fields": [
+          {"$ref": "schemas/fields.yaml#/id"},
+          {"name": "team_id", "type": "integer"},
+          {"$ref": "#/definitions/name"}
+        ],
+        "primaryKey": ["id"],
+        "foreignKeys": [{"fields": ["team_id"], "reference": {"resource": "teams", "fields": ["id"]}}]
+      }
+    }
+  ],
+  "definitions": {"name": {"name": "name", "type": "string", "title": "Name"}}
+}`,
+		"schemas/teams.json": `{
+  "fields": [{"$ref": "fields.yaml#/id"}, {"$ref": "../datapackage.json#/definitions/name"}],
+  "primaryKey": ["id"]
+}`,
+		"schemas/fields.yaml": `id: {name: id, type: integer, constraints: {required: true}}`,
+	})
+	resolver, err := NewResolver(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		reference string
+		want      *Builder
+	}{
+		{
+			// the schema is given as the path of its descriptor
+			reference: "datapackage.json#/resources/0/schema",
+			want:      New().Integer("id").Required().String("name").Title("Name").PrimaryKey("id"),
+		},
+		{
+			reference: "datapackage.json#/resources/1/schema",
+			want: New().Integer("id").Required().Integer("team_id").String("name").Title("Name").
+				PrimaryKey("id").ForeignKey([]string{"team_id"}, "teams", []string{"id"}),
+		},
+		{
+			reference: "schemas/teams.json",
+			want:      New().Integer("id").Required().String("name").Title("Name").PrimaryKey("id"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		got, err := resolver.Load(testCase.reference)
+		if err != nil {
+			t.Errorf("%s: %v", testCase.reference, err)
+			continue
+		}
+		want, err := testCase.want.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("%s: (-want +got):\n%s", testCase.reference, diff)
+		}
+	}
+
+	// each file is read once, however many references there are to it
+	if len(resolver.documents) != 3 {
+		t.Errorf("expected 3 files to have been read, got %d", len(resolver.documents))
+	}
+	if err := os.Remove(filepath.Join(directory, "schemas", "fields.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := resolver.Load("schemas/teams.json"); err != nil {
+		t.Errorf("expected the file to have been read already, got %v", err)
+	}
+}
+
+func TestResolveSchemaFragment(t *testing.T) {
+	directory := writeDescriptors(t, map[string]string{
+		"datapackage.json": `{"resources": [{"name": "teams", "schema": "teams.json"}]}`,
+		"teams.json":       `{"fields": [{"name": "id", "type": "integer"}]}`,
+	})
+
+	got, err := ResolveSchema(filepath.Join(directory, "datapackage.json#/resources/0/schema"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := New().Integer("id").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestResolverLoadErrors(t *testing.T) {
+	root := writeDescriptors(t, map[string]string{
+		"secret.json": `{"fields": [{"name": "secret", "type": "string"}]}`,
+	})
+	directory := filepath.Join(root, "schemas")
+	descriptors := map[string]string{
+		"escape.json":      `{"fields": [{"$ref": "../secret.json#/fields/0"}]}`,
+		"escape-path.json": `"../secret.json"`,
+		"absolute.json":    `{"extends": "` + filepath.ToSlash(filepath.Join(root, "secret.json")) + `"}`,
+		"pointer.json":     `{"fields": [{"$ref": "#/definitions/code"}], "definitions": {}}`,
+		"index.json":       `{"fields": [{"$ref": "#/fields/3"}]}`,
+		"missing.json":     `{"fields": [{"$ref": "nowhere.json#/id"}]}`,
+		"cycle.json":       `{"fields": [{"$ref": "#/definitions/a"}], "definitions": {"a": {"$ref": "#/definitions/b"}, "b": {"$ref": "#/definitions/a"}}}`,
+		"path-cycle.json":  `"path-cycle.json"`,
+		"siblings.json":    `{"fields": [{"$ref": "#/definitions/id", "title": "ID"}], "definitions": {"id": {"name": "id"}}}`,
+		"fragment.json":    `{"fields": [{"$ref": "#definitions"}]}`,
+		"datapackage.json": `{"resources": [
+  {"name": "teams", "schema": {"fields": [{"name": "id", "type": "integer"}]}},
+  {"name": "venues"},
+  {"name": "players", "schema": {"fields": [{"name": "team_id"}], "foreignKeys": [{"fields": ["team_id"], "reference": {"resource": "clubs", "fields": ["id"]}}]}},
+  {"name": "coaches", "schema": {"fields": [{"name": "team_id"}], "foreignKeys": [{"fields": ["team_id"], "reference": {"resource": "teams", "fields": ["code"]}}]}},
+  {"name": "games", "schema": {"fields": [{"name": "venue_id"}], "foreignKeys": [{"fields": ["venue_id"], "reference": {"resource": "venues", "fields": ["id"]}}]}}
+]}`,
+	}
+	if err := os.MkdirAll(directory, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for path, descriptor := range descriptors {
+		if err := os.WriteFile(filepath.Join(directory, path), []byte(descriptor), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resolver, err := NewResolver(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		reference string
+		wantErr   string
+	}{
+		{reference: "escape.json", wantErr: "../secret.json#/fields/0: ../secret.json is outside"},
+		{reference: "escape-path.json", wantErr: "../secret.json: ../secret.json is outside"},
+		{reference: "absolute.json", wantErr: "secret.json is outside"},
+		{reference: "../secret.json", wantErr: "../secret.json is outside"},
+		{reference: "pointer.json", wantErr: "#/definitions/code: #/definitions/code has nothing at /definitions/code"},
+		{reference: "index.json", wantErr: "#/fields/3 has nothing at /fields/3"},
+		{reference: "missing.json", wantErr: "nowhere.json#/id: open "},
+		{reference: "cycle.json", wantErr: "a descriptor refers to itself: cycle.json#/definitions/b -> cycle.json#/definitions/a -> cycle.json#/definitions/b"},
+		{reference: "path-cycle.json", wantErr: "path-cycle.json -> path-cycle.json"},
+		{reference: "siblings.json", wantErr: "the $ref #/definitions/id has other properties"},
+		{reference: "fragment.json", wantErr: "the fragment of #definitions isn't a JSON pointer"},
+		{reference: "datapackage.json#/resources/2/schema", wantErr: "foreign key 0 refers to the resource clubs, which isn't in datapackage.json"},
+		{reference: "datapackage.json#/resources/3/schema", wantErr: "foreign key 0 refers to code, which isn't a field of the resource teams"},
+		{reference: "datapackage.json#/resources/4/schema", wantErr: "foreign key 0 refers to the resource venues, which has no schema"},
+		{reference: "datapackage.json#/resources/5/schema", wantErr: "#/resources/5/schema has nothing at /resources/5"},
+	}
+
+	for _, testCase := range testCases {
+		_, err := resolver.Load(testCase.reference)
+		if err == nil || !strings.Contains(err.Error(), testCase.wantErr) {
+			t.Errorf("%s: expected an error containing %q, got %v", testCase.reference, testCase.wantErr, err)
+		}
+	}
+}
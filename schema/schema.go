@@ -2,6 +2,35 @@
 // A Schema object can then be used by the validate package to validate some source data.
 package schema
 
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// trueValues and falseValues are the values a boolean field accepts, which are the defaults of trueValues and
+// falseValues defined [here](https://datapackage.org/standard/table-schema/#boolean). Everything that reads or
+// writes booleans checks them with IsTrue, IsFalse and IsBoolean, so that it agrees with validation.
+var (
+	trueValues  = []string{"true", "True", "TRUE", "1"}
+	falseValues = []string{"false", "False", "FALSE", "0"}
+)
+
+// IsTrue reports whether a boolean field reads value as true.
+func IsTrue(value string) bool {
+	return slices.Contains(trueValues, value)
+}
+
+// IsFalse reports whether a boolean field reads value as false.
+func IsFalse(value string) bool {
+	return slices.Contains(falseValues, value)
+}
+
+// IsBoolean reports whether a boolean field accepts value, as either true or false.
+func IsBoolean(value string) bool {
+	return IsTrue(value) || IsFalse(value)
+}
+
 type Schema struct {
 	SchemaSchema string `json:"$schema"`
 	SchemaOptions
@@ -24,6 +53,92 @@ func (fields *Fields) insertFieldTypes() {
 	for i := range fields.ListFields {
 		fields.ListFields[i].FieldType = "list"
 	}
+
+	for i := range fields.IntegerFields {
+		fields.IntegerFields[i].FieldType = "integer"
+	}
+
+	for i := range fields.DateFields {
+		fields.DateFields[i].FieldType = "date"
+	}
+
+	for i := range fields.DateTimeFields {
+		fields.DateTimeFields[i].FieldType = "datetime"
+	}
+}
+
+// values returns each field, whatever its type, in the order of Order.
+func (fields Fields) values() []reflect.Value {
+	var values []reflect.Value
+
+	val := reflect.ValueOf(fields)
+
+	for i := 0; i < val.NumField(); i++ {
+		if val.Type().Field(i).Name == "Order" {
+			continue
+		}
+		typedFields := val.Field(i)
+		for j := 0; j < typedFields.Len(); j++ {
+			values = append(values, typedFields.Index(j))
+		}
+	}
+
+	position := func(field reflect.Value) int {
+		index := slices.Index(fields.Order, field.FieldByName("Name").String())
+		if index < 0 {
+			return len(fields.Order)
+		}
+		return index
+	}
+	slices.SortStableFunc(values, func(a reflect.Value, b reflect.Value) int { return position(a) - position(b) })
+
+	return values
+}
+
+// Names returns the names of the fields in the order they appear in the marshalled schema, which is
+// the order columns are expected in when a source has no header to match them by.
+func (fields Fields) Names() []string {
+	var names []string
+	for _, field := range fields.values() {
+		names = append(names, field.FieldByName("Name").String())
+	}
+	return names
+}
+
+// A FieldDescriptor describes a field whatever its type, for code that treats fields of every type alike.
+type FieldDescriptor struct {
+	FieldBase
+	// Constraints holds the value of each selected constraint, keyed by its name in a descriptor, e.g. minLength.
+	Constraints map[string]any
+}
+
+// List returns a FieldDescriptor for each field, in the same order as Names.
+func (fields Fields) List() []FieldDescriptor {
+	var descriptors []FieldDescriptor
+
+	for _, field := range fields.values() {
+		descriptor := FieldDescriptor{
+			FieldBase:   field.FieldByName("FieldBase").Interface().(FieldBase),
+			Constraints: make(map[string]any),
+		}
+		// each type of field is named for its type, e.g. a DateTimeField is a datetime field
+		descriptor.FieldType = strings.ToLower(strings.TrimSuffix(field.Type().Name(), "Field"))
+
+		constraints := field.FieldByName("Constraints")
+		for k := 0; k < constraints.NumField(); k++ {
+			constraint := constraints.Field(k)
+			if !constraint.FieldByName("Selected").Bool() {
+				continue
+			}
+			structKey := constraints.Type().Field(k).Name
+			jsonKey := strings.ToLower(structKey[0:1]) + structKey[1:]
+			descriptor.Constraints[jsonKey] = constraint.FieldByName("Value").Interface()
+		}
+
+		descriptors = append(descriptors, descriptor)
+	}
+
+	return descriptors
 }
 
 // Takes a set of SchemaOptions and converts them into a valid Schema
@@ -54,6 +54,23 @@ type BooleanConstraints struct {
 	Enum     EnumConstraint     `json:"enum"`
 }
 
+type IntegerConstraints struct {
+	Required RequiredConstraint `json:"required"`
+	Unique   UniqueContraint    `json:"unique"`
+	Min      MinConstraint      `json:"min"`
+	Max      MaxConstraint      `json:"max"`
+}
+
+type DateConstraints struct {
+	Required RequiredConstraint `json:"required"`
+	Unique   UniqueContraint    `json:"unique"`
+}
+
+type DateTimeConstraints struct {
+	Required RequiredConstraint `json:"required"`
+	Unique   UniqueContraint    `json:"unique"`
+}
+
 type ListConstraints struct {
 	Required  RequiredConstraint  `json:"required"`
 	MinLength MinLengthConstraint `json:"minLength"`
@@ -66,6 +83,9 @@ type FieldBase struct {
 	Title       string `json:"title,omitempty"`
 	Description string `json:"description,omitempty"`
 	Example     string `json:"example,omitempty"`
+	// IgnoredConstraints are the keys of the constraints in the field's descriptor that its type doesn't have,
+	// e.g. unique for a boolean field, which are read without error but have no effect.
+	IgnoredConstraints []string `json:"-"`
 }
 
 type StringField struct {
@@ -88,18 +108,63 @@ type ListField struct {
 	Constraints ListConstraints `json:"constraints"`
 }
 
+type IntegerField struct {
+	FieldBase
+	Constraints IntegerConstraints `json:"constraints"`
+}
+
+// A DateField's values are dates in the form YYYY-MM-DD.
+type DateField struct {
+	FieldBase
+	Constraints DateConstraints `json:"constraints"`
+}
+
+// A DateTimeField's values are ISO 8601 datetimes, e.g. 2024-01-31T09:30:00Z. The timezone may be left out.
+type DateTimeField struct {
+	FieldBase
+	Constraints DateTimeConstraints `json:"constraints"`
+}
+
 // The fields, or columns, of the source data, that are to be included in the schema.
 // Fields are split into their types rather than being a list like in the output json
 // version of the schema or the csv header row because of the difficulty of modelling 
-// sum types in Golang. An outcome of this is that as a user, you cannot control the 
-// order of your field definitions.
+// sum types in Golang. Order puts the fields back into the order of a descriptor.
 type Fields struct {
-	StringFields  []StringField
-	NumberFields  []NumberField
-	BooleanFields []BooleanField
-	ListFields    []ListField
+	StringFields   []StringField
+	NumberFields   []NumberField
+	BooleanFields  []BooleanField
+	ListFields     []ListField
+	IntegerFields  []IntegerField
+	DateFields     []DateField
+	DateTimeFields []DateTimeField
+	// Order is the names of the fields in the order they are listed in a descriptor. Fields that Order
+	// doesn't name come after those it does, grouped by type in the order of the slices above.
+	Order []string
+}
+
+// FieldNames names one or more fields, e.g. those of a primary key. A descriptor can give a single name as a
+// string rather than a list of one.
+type FieldNames []string
+
+// A ForeignKey says that the values of Fields are values of the fields of Reference, e.g. that a customer_id
+// column holds the id of a row of a customers table.
+type ForeignKey struct {
+	Fields    FieldNames          `json:"fields"`
+	Reference ForeignKeyReference `json:"reference"`
+}
+
+// A ForeignKeyReference is the fields a ForeignKey refers to. Resource is the name of the data resource, e.g.
+// the table, they're in. An empty Resource refers to the schema's own table.
+type ForeignKeyReference struct {
+	Resource string     `json:"resource,omitempty"`
+	Fields   FieldNames `json:"fields"`
 }
 
 type SchemaOptions struct {
 	Fields Fields `json:"fields"`
+	// PrimaryKey is the fields whose values together identify each row.
+	PrimaryKey FieldNames `json:"primaryKey,omitempty"`
+	// UniqueKeys are sets of fields whose values together are different in each row.
+	UniqueKeys  []FieldNames `json:"uniqueKeys,omitempty"`
+	ForeignKeys []ForeignKey `json:"foreignKeys,omitempty"`
 }
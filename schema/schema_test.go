@@ -135,7 +135,7 @@ func TestMarshallSchemaToJSON(t *testing.T) {
 		t.Errorf("\nWanted %s got %s", expected, got)
 	}
 
-	schemaLoader := gojsonschema.NewReferenceLoader("file://../test-data/tableschema-json-schema.json")
+	schemaLoader := gojsonschema.NewBytesLoader(profile)
 	documentLoader := gojsonschema.NewStringLoader(got)
 
 	result, err := gojsonschema.Validate(schemaLoader, documentLoader)
@@ -154,3 +154,152 @@ func TestMarshallSchemaToJSON(t *testing.T) {
 	}
 
 }
+
+func TestParseSchema(t *testing.T) {
+	descriptor := `{
+  "fields": [
+    {"name": "id", "constraints": {"required": true, "unique": true}},
+    {"type": "number", "name": "score", "title": "Score", "constraints": {"minimum": 0, "max": 10}},
+    {"type": "boolean", "name": "active"},
+    {"type": "string", "name": "code", "constraints": {"pattern": "[A-Z]+", "minLength": 0, "exclusiveMinimum": 1}},
+    {"type": "integer", "name": "count", "constraints": {"required": true, "minimum": 1}},
+    {"type": "date", "name": "joined", "constraints": {"unique": true}},
+    {"type": "datetime", "name": "updated"}
+  ]
+}`
+
+	got, err := ParseSchema([]byte(descriptor))
+	if err != nil {
+		t.Fatalf("Failed to parse schema with error %s", err.Error())
+	}
+
+	expected := MakeSchema(SchemaOptions{
+		Fields: Fields{
+			StringFields: []StringField{
+				{
+					FieldBase: FieldBase{Name: "id"},
+					Constraints: StringConstraints{
+						Required: RequiredConstraint{Selected: true, Value: true},
+						Unique:   UniqueContraint{Selected: true, Value: true},
+					},
+				},
+				{
+					// a constraint the type doesn't have is recorded, so that it can be linted
+					FieldBase: FieldBase{Name: "code", IgnoredConstraints: []string{"exclusiveMinimum"}},
+					Constraints: StringConstraints{
+						Pattern:   PatternConstraint{Selected: true, Value: "[A-Z]+"},
+						MinLength: MinLengthConstraint{Selected: true, Value: 0},
+					},
+				},
+			},
+			NumberFields: []NumberField{
+				{
+					FieldBase: FieldBase{Name: "score", Title: "Score"},
+					Constraints: NumberConstraints{
+						Min: MinConstraint{Selected: true, Value: 0},
+						Max: MaxConstraint{Selected: true, Value: 10},
+					},
+				},
+			},
+			BooleanFields: []BooleanField{
+				{FieldBase: FieldBase{Name: "active"}},
+			},
+			IntegerFields: []IntegerField{
+				{
+					FieldBase: FieldBase{Name: "count"},
+					Constraints: IntegerConstraints{
+						Required: RequiredConstraint{Selected: true, Value: true},
+						Min:      MinConstraint{Selected: true, Value: 1},
+					},
+				},
+			},
+			DateFields: []DateField{
+				{FieldBase: FieldBase{Name: "joined"}, Constraints: DateConstraints{Unique: UniqueContraint{Selected: true, Value: true}}},
+			},
+			DateTimeFields: []DateTimeField{
+				{FieldBase: FieldBase{Name: "updated"}},
+			},
+			// the fields are listed in the order of the descriptor, rather than by type
+			Order: []string{"id", "score", "active", "code", "count", "joined", "updated"},
+		},
+	})
+
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	// a marshalled schema can be parsed back into the schema it came from, which leaves out the constraints it
+	// ignored
+	expected.Fields.StringFields[1].IgnoredConstraints = nil
+	marshalled, err := json.Marshal(expected)
+	if err != nil {
+		t.Fatalf("Failed to marshall schema to JSON with error %s", err.Error())
+	}
+	roundTripped, err := ParseSchema(marshalled)
+	if err != nil {
+		t.Fatalf("Failed to parse marshalled schema with error %s", err.Error())
+	}
+	if diff := cmp.Diff(expected, roundTripped); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	for _, invalid := range []string{
+		`{"fields": [{"type": "string"}]}`,
+		`{"fields": [{"name": "when", "type": "geopoint"}]}`,
+		`{"fields": [{"name": "score", "type": "number", "constraints": {"minimum": "low"}}]}`,
+	} {
+		if _, err := ParseSchema([]byte(invalid)); err == nil {
+			t.Errorf("Expected an error parsing %s", invalid)
+		}
+	}
+}
+
+func TestParseSchemaKeys(t *testing.T) {
+	descriptor := `{
+  "fields": [{"name": "id"}, {"name": "email"}, {"name": "team"}, {"name": "parent"}],
+  "primaryKey": "id",
+  "uniqueKeys": [["email"], ["team", "email"]],
+  "foreignKeys": [
+    {"fields": "team", "reference": {"resource": "teams", "fields": "id"}},
+    {"fields": ["parent"], "reference": {"fields": ["id"]}}
+  ]
+}`
+
+	got, err := ParseSchema([]byte(descriptor))
+	if err != nil {
+		t.Fatalf("Failed to parse schema with error %s", err.Error())
+	}
+
+	// a key given as a single name is read as a list of one
+	want := SchemaOptions{
+		PrimaryKey: FieldNames{"id"},
+		UniqueKeys: []FieldNames{{"email"}, {"team", "email"}},
+		ForeignKeys: []ForeignKey{
+			{Fields: FieldNames{"team"}, Reference: ForeignKeyReference{Resource: "teams", Fields: FieldNames{"id"}}},
+			{Fields: FieldNames{"parent"}, Reference: ForeignKeyReference{Fields: FieldNames{"id"}}},
+		},
+	}
+	want.Fields = got.Fields
+	if diff := cmp.Diff(want, got.SchemaOptions); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	marshalled, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("Failed to marshall schema to JSON with error %s", err.Error())
+	}
+	roundTripped, err := ParseSchema(marshalled)
+	if err != nil {
+		t.Fatalf("Failed to parse marshalled schema with error %s", err.Error())
+	}
+	if diff := cmp.Diff(got, roundTripped); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+	if err := Validate(roundTripped); err != nil {
+		t.Errorf("Expected the marshalled keys to match the profile, got %s", err.Error())
+	}
+
+	if _, err := ParseSchema([]byte(`{"fields": [{"name": "id"}], "primaryKey": 1}`)); err == nil {
+		t.Error("Expected an error parsing a primary key that isn't a name")
+	}
+}
@@ -0,0 +1,242 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// splitTag splits a tableschema struct tag into its comma-separated options. A comma that is part of an
+// option's value, e.g. in the pattern [a-z]{1,3}, is escaped with a backslash.
+func splitTag(tag string) []string {
+	var options []string
+	var option strings.Builder
+	escaped := false
+	for _, character := range tag {
+		switch {
+		case escaped:
+			if character != ',' {
+				option.WriteRune('\\')
+			}
+			option.WriteRune(character)
+			escaped = false
+		case character == '\\':
+			escaped = true
+		case character == ',':
+			options = append(options, option.String())
+			option.Reset()
+		default:
+			option.WriteRune(character)
+		}
+	}
+	if escaped {
+		option.WriteRune('\\')
+	}
+	return append(options, option.String())
+}
+
+// structFieldType returns the tableschema type of a struct field of Go type goType, and whether the field
+// is optional, which it is if it's a pointer.
+func structFieldType(goType reflect.Type) (string, bool, error) {
+	optional := false
+	if goType.Kind() == reflect.Pointer {
+		optional = true
+		goType = goType.Elem()
+	}
+
+	switch {
+	case goType == reflect.TypeFor[time.Time]():
+		return "datetime", optional, nil
+	case goType.Kind() == reflect.String:
+		return "string", optional, nil
+	case goType.Kind() == reflect.Bool:
+		return "boolean", optional, nil
+	case goType.Kind() >= reflect.Int && goType.Kind() <= reflect.Uint64:
+		return "integer", optional, nil
+	case goType.Kind() == reflect.Float32 || goType.Kind() == reflect.Float64:
+		return "number", optional, nil
+	case (goType.Kind() == reflect.Slice || goType.Kind() == reflect.Array) && goType.Elem().Kind() != reflect.Uint8:
+		return "list", optional, nil
+	}
+	return "", false, fmt.Errorf("has Go type %s, which has no tableschema type", goType)
+}
+
+// startField starts a field of type fieldType called name.
+func startField(builder *Builder, fieldType string, name string) error {
+	switch fieldType {
+	case "string":
+		builder.String(name)
+	case "number":
+		builder.Number(name)
+	case "boolean":
+		builder.Boolean(name)
+	case "list":
+		builder.List(name)
+	case "integer":
+		builder.Integer(name)
+	case "date":
+		builder.Date(name)
+	case "datetime":
+		builder.DateTime(name)
+	default:
+		return fmt.Errorf("has type %q, which isn't supported", fieldType)
+	}
+	return nil
+}
+
+// applyOption applies one option of a tableschema struct tag, other than name, type and optional, to the
+// field being built.
+func applyOption(builder *Builder, key string, value string) error {
+	parseInt := func() (int64, error) {
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("has a %s of %q, which isn't an integer", key, value)
+		}
+		return number, nil
+	}
+
+	switch key {
+	case "title":
+		builder.Title(value)
+	case "description":
+		builder.Description(value)
+	case "example":
+		builder.Example(value)
+	case "required":
+		builder.Required()
+	case "unique":
+		builder.Unique()
+	case "pattern":
+		builder.Pattern(value)
+	case "enum":
+		builder.Enum(strings.Split(value, "|")...)
+	case "minLength", "maxLength", "min", "max":
+		number, err := parseInt()
+		if err != nil {
+			return err
+		}
+		map[string]func(int64) *Builder{
+			"minLength": builder.MinLength,
+			"maxLength": builder.MaxLength,
+			"min":       builder.Min,
+			"max":       builder.Max,
+		}[key](number)
+	default:
+		return fmt.Errorf("has the tableschema option %q, which isn't supported", key)
+	}
+	return nil
+}
+
+// addStructFields adds a field to builder for each exported field of structType, including those of the
+// structs it embeds.
+func addStructFields(builder *Builder, structType reflect.Type) error {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag, tagged := field.Tag.Lookup("tableschema")
+		if tag == "-" {
+			continue
+		}
+		if field.Anonymous && !tagged && field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeFor[time.Time]() {
+			if err := addStructFields(builder, field.Type); err != nil {
+				return err
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if err := addStructField(builder, field, tag); err != nil {
+			return fmt.Errorf("field %s %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+// addStructField adds a field to builder for the struct field field, whose tableschema struct tag is tag.
+func addStructField(builder *Builder, field reflect.StructField, tag string) error {
+	name := field.Name
+	if jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ","); jsonName != "" && jsonName != "-" {
+		name = jsonName
+	}
+
+	fieldType, optional, err := structFieldType(field.Type)
+
+	var options [][2]string
+	if tag != "" {
+		for _, option := range splitTag(tag) {
+			key, value, _ := strings.Cut(option, "=")
+			switch key {
+			case "name":
+				name = value
+			case "type":
+				// the type can be given for a field whose Go type has none, e.g. a string holding dates
+				fieldType, err = value, nil
+			case "optional":
+				optional = true
+			default:
+				options = append(options, [2]string{key, value})
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := startField(builder, fieldType, name); err != nil {
+		return err
+	}
+	if !optional {
+		builder.Required()
+	}
+	for _, option := range options {
+		if option[0] == "required" && !optional {
+			continue
+		}
+		if err := applyOption(builder, option[0], option[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FromType returns a schema with a field for each exported field of structType, which has to be a struct or
+// a pointer to one, in the order they are declared. The fields of embedded structs are included as if they
+// were declared in their place.
+//
+// A field's type is taken from its Go type: strings are strings, bools are booleans, integers are integers,
+// floats are numbers, time.Times are datetimes, and slices and arrays are lists. A field is required unless
+// it is a pointer. Each field is named after its json name if it has one, or its Go name otherwise.
+//
+// The tableschema struct tag sets anything else, as a comma-separated list of options:
+//
+//	ID      int       `tableschema:"name=id,unique,min=1"`
+//	Joined  time.Time `tableschema:"type=date,title=Date joined"`
+//	Size    *string   `tableschema:"enum=S|M|L"`
+//	Code    string    `tableschema:"pattern=[A-Z]{2\,3},optional"`
+//	Secret  string    `tableschema:"-"`
+//
+// The options are name, title, description and example; type, for a type other than the Go type's; optional
+// or required; and the constraints unique, pattern, enum (with values separated by |), minLength, maxLength,
+// min and max. A comma in an option's value is escaped with a backslash. A field tagged - is left out.
+func FromType(structType reflect.Type) (Schema, error) {
+	for structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return Schema{}, fmt.Errorf("can only make a schema from a struct, not a %s", structType)
+	}
+
+	builder := New()
+	if err := addStructFields(builder, structType); err != nil {
+		return Schema{}, err
+	}
+	return builder.Build()
+}
+
+// FromStruct returns a schema with a field for each exported field of the struct T, as FromType does.
+func FromStruct[T any]() (Schema, error) {
+	return FromType(reflect.TypeFor[T]())
+}
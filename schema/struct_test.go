@@ -0,0 +1,103 @@
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type auditFields struct {
+	Updated time.Time `json:"updated"`
+}
+
+type account struct {
+	ID      int       `tableschema:"name=id,unique,min=1"`
+	Name    string    `json:"name" tableschema:"title=Full name,maxLength=50"`
+	Code    string    `tableschema:"pattern=[A-Z]{2\\,3},optional"`
+	Size    *string   `tableschema:"enum=S|M|L"`
+	Score   *float64  `tableschema:"required,max=100"`
+	Active  bool
+	Tags    []string
+	Joined  string `tableschema:"type=date"`
+	Secret  string `tableschema:"-"`
+	private string
+	auditFields
+}
+
+func TestFromStruct(t *testing.T) {
+	got, err := FromStruct[account]()
+	if err != nil {
+		t.Fatalf("Failed to make schema with error %s", err.Error())
+	}
+
+	required := RequiredConstraint{Selected: true, Value: true}
+	expected := MakeSchema(SchemaOptions{Fields: Fields{
+		StringFields: []StringField{
+			{FieldBase: FieldBase{Name: "name", Title: "Full name"}, Constraints: StringConstraints{Required: required, MaxLength: MaxLengthConstraint{Selected: true, Value: 50}}},
+			{FieldBase: FieldBase{Name: "Code"}, Constraints: StringConstraints{Pattern: PatternConstraint{Selected: true, Value: "[A-Z]{2,3}"}}},
+			{FieldBase: FieldBase{Name: "Size"}, Constraints: StringConstraints{Enum: EnumConstraint{Selected: true, Value: []string{"S", "M", "L"}}}},
+		},
+		NumberFields: []NumberField{
+			{FieldBase: FieldBase{Name: "Score"}, Constraints: NumberConstraints{Required: required, Max: MaxConstraint{Selected: true, Value: 100}}},
+		},
+		BooleanFields: []BooleanField{
+			{FieldBase: FieldBase{Name: "Active"}, Constraints: BooleanConstraints{Required: required}},
+		},
+		ListFields: []ListField{
+			{FieldBase: FieldBase{Name: "Tags"}, Constraints: ListConstraints{Required: required}},
+		},
+		IntegerFields: []IntegerField{
+			{FieldBase: FieldBase{Name: "id"}, Constraints: IntegerConstraints{Required: required, Unique: UniqueContraint{Selected: true, Value: true}, Min: MinConstraint{Selected: true, Value: 1}}},
+		},
+		DateFields: []DateField{
+			{FieldBase: FieldBase{Name: "Joined"}, Constraints: DateConstraints{Required: required}},
+		},
+		DateTimeFields: []DateTimeField{
+			{FieldBase: FieldBase{Name: "updated"}, Constraints: DateTimeConstraints{Required: required}},
+		},
+		Order: []string{"id", "name", "Code", "Size", "Score", "Active", "Tags", "Joined", "updated"},
+	}})
+
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestFromStructErrors(t *testing.T) {
+	type unsupportedType struct {
+		Lookup map[string]string
+	}
+	type unknownOption struct {
+		ID int `tableschema:"primary"`
+	}
+	type invalidNumber struct {
+		ID int `tableschema:"min=one"`
+	}
+	type constraintForAnotherType struct {
+		Active bool `tableschema:"min=1"`
+	}
+
+	testCases := []struct {
+		name string
+		make func() (Schema, error)
+		want string
+	}{
+		{name: "unsupported type", make: FromStruct[unsupportedType], want: "field Lookup has Go type map[string]string, which has no tableschema type"},
+		{name: "unknown option", make: FromStruct[unknownOption], want: `field ID has the tableschema option "primary", which isn't supported`},
+		{name: "invalid number", make: FromStruct[invalidNumber], want: `field ID has a min of "one", which isn't an integer`},
+		{name: "constraint for another type", make: FromStruct[constraintForAnotherType], want: "Active is of type boolean, which has no min constraint"},
+		{name: "not a struct", make: FromStruct[string], want: "can only make a schema from a struct, not a string"},
+	}
+
+	for _, testCase := range testCases {
+		_, err := testCase.make()
+		if err == nil {
+			t.Errorf("%s: expected an error", testCase.name)
+			continue
+		}
+		if diff := cmp.Diff(testCase.want, err.Error()); diff != "" {
+			t.Errorf("%s (-want +got):\n%s", testCase.name, diff)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// constraintAliases maps the keys the tableschema spec uses for some constraints to the keys this
+// package marshals them with, so that descriptors written to the spec can be read.
+var constraintAliases = map[string]string{
+	"minimum": "min",
+	"maximum": "max",
+}
+
+// Constraint.UnmarshalJSON reads a constraint's value from a tableschema json string. A constraint that
+// is present is Selected, even if its value is a 0 value; null is taken to mean it isn't present.
+func (constraint *Constraint[selection]) UnmarshalJSON(data []byte) error {
+	if string(data) == `null` {
+		*constraint = Constraint[selection]{}
+		return nil
+	}
+	var value selection
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*constraint = Constraint[selection]{Selected: true, Value: value}
+	return nil
+}
+
+// constraintIndex returns the index of the field of the constraints struct type constraints that a json
+// key is marshalled from, or -1 if the type doesn't have that constraint.
+func constraintIndex(constraints reflect.Type, jsonKey string) int {
+	if alias, ok := constraintAliases[jsonKey]; ok {
+		jsonKey = alias
+	}
+	for i := 0; i < constraints.NumField(); i++ {
+		structKey := constraints.Field(i).Name
+		if strings.ToLower(structKey[0:1])+structKey[1:] == jsonKey {
+			return i
+		}
+	}
+	return -1
+}
+
+// constraintsUnmarshaller is the reverse of constraintsMarshaller: each key of the json object is matched
+// to the struct field it would have been marshalled from. Keys for constraints that the type of field
+// doesn't have are skipped here; Fields.UnmarshalJSON records them in IgnoredConstraints.
+func constraintsUnmarshaller(data []byte, constraints any) error {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	val := reflect.ValueOf(constraints).Elem()
+
+	for jsonKey, value := range values {
+		if alias, ok := constraintAliases[jsonKey]; ok {
+			jsonKey = alias
+		}
+		i := constraintIndex(val.Type(), jsonKey)
+		if i < 0 {
+			continue
+		}
+		if err := json.Unmarshal(value, val.Field(i).Addr().Interface()); err != nil {
+			return fmt.Errorf("invalid %s constraint: %w", jsonKey, err)
+		}
+	}
+
+	return nil
+}
+
+// ignoredConstraints returns the keys of the constraints in a field's descriptor that the constraints struct
+// type constraints doesn't have, in order, e.g. unique for a boolean field.
+func ignoredConstraints(descriptor json.RawMessage, constraints reflect.Type) []string {
+	var field struct {
+		Constraints map[string]json.RawMessage `json:"constraints"`
+	}
+	// the descriptor has already been read as a field, so it can't be invalid
+	_ = json.Unmarshal(descriptor, &field)
+
+	var ignored []string
+	for jsonKey := range field.Constraints {
+		if constraintIndex(constraints, jsonKey) < 0 {
+			ignored = append(ignored, jsonKey)
+		}
+	}
+	slices.Sort(ignored)
+	return ignored
+}
+
+func (constraints *StringConstraints) UnmarshalJSON(data []byte) error {
+	return constraintsUnmarshaller(data, constraints)
+}
+
+func (constraints *NumberConstraints) UnmarshalJSON(data []byte) error {
+	return constraintsUnmarshaller(data, constraints)
+}
+
+func (constraints *BooleanConstraints) UnmarshalJSON(data []byte) error {
+	return constraintsUnmarshaller(data, constraints)
+}
+
+func (constraints *ListConstraints) UnmarshalJSON(data []byte) error {
+	return constraintsUnmarshaller(data, constraints)
+}
+
+func (constraints *IntegerConstraints) UnmarshalJSON(data []byte) error {
+	return constraintsUnmarshaller(data, constraints)
+}
+
+func (constraints *DateConstraints) UnmarshalJSON(data []byte) error {
+	return constraintsUnmarshaller(data, constraints)
+}
+
+func (constraints *DateTimeConstraints) UnmarshalJSON(data []byte) error {
+	return constraintsUnmarshaller(data, constraints)
+}
+
+// FieldNames.UnmarshalJSON reads a list of field names, or a single name given as a string.
+func (names *FieldNames) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*names = FieldNames{name}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("expected a field name or a list of them: %w", err)
+	}
+	*names = list
+	return nil
+}
+
+// Fields.UnmarshalJSON reads the mixed-type json list of a tableschema's fields, sorting each field into
+// the slice for its type and recording the list's order in Order. A field without a type is a string
+// field, as the spec says.
+func (fields *Fields) UnmarshalJSON(data []byte) error {
+	var descriptors []json.RawMessage
+	if err := json.Unmarshal(data, &descriptors); err != nil {
+		return err
+	}
+
+	*fields = Fields{}
+
+	for index, descriptor := range descriptors {
+		var base FieldBase
+		if err := json.Unmarshal(descriptor, &base); err != nil {
+			return fmt.Errorf("field %d is invalid: %w", index, err)
+		}
+		if base.Name == "" {
+			return fmt.Errorf("field %d has no name", index)
+		}
+		fields.Order = append(fields.Order, base.Name)
+
+		var err error
+		switch base.FieldType {
+		case "", "string":
+			var field StringField
+			err = json.Unmarshal(descriptor, &field)
+			field.IgnoredConstraints = ignoredConstraints(descriptor, reflect.TypeOf(field.Constraints))
+			fields.StringFields = append(fields.StringFields, field)
+		case "number":
+			var field NumberField
+			err = json.Unmarshal(descriptor, &field)
+			field.IgnoredConstraints = ignoredConstraints(descriptor, reflect.TypeOf(field.Constraints))
+			fields.NumberFields = append(fields.NumberFields, field)
+		case "boolean":
+			var field BooleanField
+			err = json.Unmarshal(descriptor, &field)
+			field.IgnoredConstraints = ignoredConstraints(descriptor, reflect.TypeOf(field.Constraints))
+			fields.BooleanFields = append(fields.BooleanFields, field)
+		case "list":
+			var field ListField
+			err = json.Unmarshal(descriptor, &field)
+			field.IgnoredConstraints = ignoredConstraints(descriptor, reflect.TypeOf(field.Constraints))
+			fields.ListFields = append(fields.ListFields, field)
+		case "integer":
+			var field IntegerField
+			err = json.Unmarshal(descriptor, &field)
+			field.IgnoredConstraints = ignoredConstraints(descriptor, reflect.TypeOf(field.Constraints))
+			fields.IntegerFields = append(fields.IntegerFields, field)
+		case "date":
+			var field DateField
+			err = json.Unmarshal(descriptor, &field)
+			field.IgnoredConstraints = ignoredConstraints(descriptor, reflect.TypeOf(field.Constraints))
+			fields.DateFields = append(fields.DateFields, field)
+		case "datetime":
+			var field DateTimeField
+			err = json.Unmarshal(descriptor, &field)
+			field.IgnoredConstraints = ignoredConstraints(descriptor, reflect.TypeOf(field.Constraints))
+			fields.DateTimeFields = append(fields.DateTimeFields, field)
+		default:
+			return fmt.Errorf("field %s has type %q, which isn't supported", base.Name, base.FieldType)
+		}
+		if err != nil {
+			return fmt.Errorf("field %s is invalid: %w", base.Name, err)
+		}
+	}
+
+	fields.insertFieldTypes()
+	return nil
+}
+
+// ParseSchema reads a Schema from a tableschema json descriptor, the reverse of marshalling one.
+func ParseSchema(data []byte) (Schema, error) {
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return Schema{}, fmt.Errorf("could not read schema: %w", err)
+	}
+	if schema.SchemaSchema == "" {
+		schema.SchemaSchema = MakeSchema(SchemaOptions{}).SchemaSchema
+	}
+	return schema, nil
+}
+
+// LoadSchema reads a Schema from the tableschema descriptor in the file at path, which is written in YAML
+// if IsYAML(path) and in json otherwise. A descriptor composed from others is flattened, as by ResolveSchema.
+// Its references can only be to files in its directory or below it; LoadSchemaWithOptions can widen that.
+func LoadSchema(path string) (Schema, error) {
+	return ResolveSchema(path)
+}
@@ -0,0 +1,54 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"tableschema-validator/yaml"
+)
+
+// IsYAML reports whether the descriptor in the file at path is written in YAML rather than json, which is
+// told from its extension: .yaml or .yml.
+func IsYAML(path string) bool {
+	extension := strings.ToLower(filepath.Ext(path))
+	return extension == ".yaml" || extension == ".yml"
+}
+
+// ParseSchemaYAML reads a Schema from a tableschema descriptor written in YAML, which has the same structure
+// as a json one. Its fields are in the order they're listed in.
+func ParseSchemaYAML(data []byte) (Schema, error) {
+	converted, err := yaml.ToJSON(data)
+	if err != nil {
+		return Schema{}, fmt.Errorf("could not read schema: %w", err)
+	}
+	return ParseSchema(converted)
+}
+
+// MarshalYAML returns the tableschema descriptor of schema written in YAML, with the same structure as its
+// json one.
+func MarshalYAML(schema Schema) ([]byte, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.FromJSON(data)
+}
+
+// SaveSchema writes the descriptor of schema to the file at path, in YAML if IsYAML(path) and as indented
+// json otherwise.
+func SaveSchema(path string, schema Schema) error {
+	var data []byte
+	var err error
+	if IsYAML(path) {
+		data, err = MarshalYAML(schema)
+	} else {
+		data, err = json.MarshalIndent(schema, "", "  ")
+		data = append(data, '\n')
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
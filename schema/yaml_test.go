@@ -0,0 +1,100 @@
+package schema
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseSchemaYAML(t *testing.T) {
+	descriptor := `# accounts, one row per customer
+fields:
+  - name: id
+    type: integer
+    constraints:
+      required: true
+      minimum: 1   # ids start at 1
+  - name: size
+    type: string
+    title: "Size: S or M"
+    example: M
+    constraints: {enum: [S, M]}
+  - name: notes
+    description: |
+      Anything else.
+primaryKey: id
+`
+
+	got, err := ParseSchemaYAML([]byte(descriptor))
+	if err != nil {
+		t.Fatalf("Failed to parse schema with error %s", err.Error())
+	}
+
+	want, err := New().
+		Integer("id").Required().Min(1).
+		String("size").Title("Size: S or M").Example("M").Enum("S", "M").
+		String("notes").Description("Anything else.\n").
+		PrimaryKey("id").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	for _, invalid := range []string{
+		"fields:\n  - name: id\n   type: integer\n",
+		"fields:\n  - name: count\n    type: integer\n    example: 1\n",
+		"- name: id\n",
+	} {
+		if _, err := ParseSchemaYAML([]byte(invalid)); err == nil {
+			t.Errorf("Expected an error parsing %q", invalid)
+		}
+	}
+}
+
+func TestMarshalYAML(t *testing.T) {
+	tableSchema, err := New().
+		Integer("id").Required().
+		String("code").Enum("A", "1").
+		PrimaryKey("id").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := MarshalYAML(tableSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `$schema: https://datapackage.org/profiles/2.0/tableschema.json
+fields:
+  - type: integer
+    name: id
+    constraints:
+      required: true
+  - type: string
+    name: code
+    constraints:
+      enum: [A, "1"]
+primaryKey: [id]
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	// a schema saved as YAML is loaded back as the same schema
+	path := filepath.Join(t.TempDir(), "schema.yml")
+	if err := SaveSchema(path, tableSchema); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadSchema(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(tableSchema, loaded); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"flag"
+	"tableschema-validator/schema"
+)
+
+// schemaFlags are the flags of the commands that read schema descriptors, describing how to follow the
+// references in them.
+type schemaFlags struct {
+	base *string
+}
+
+func addSchemaFlags(flags *flag.FlagSet) schemaFlags {
+	return schemaFlags{
+		base: flags.String("base", "", "directory the references in a schema can't leave (default the schema's own directory)"),
+	}
+}
+
+// load reads the schema at path, which can end in a JSON pointer fragment, e.g.
+// datapackage.json#/resources/0/schema.
+func (flags schemaFlags) load(path string) (schema.Schema, error) {
+	return schema.LoadSchemaWithOptions(path, schema.LoadOptions{Base: *flags.base})
+}
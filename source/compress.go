@@ -0,0 +1,165 @@
+package source
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// DefaultMaxDecompressedSize is the limit on the size of a decompressed source used when Options doesn't set one.
+const DefaultMaxDecompressedSize = 1 << 30
+
+// ErrDecompressedSizeLimit is returned when a compressed source is bigger than allowed once decompressed,
+// as it might be if it is a zip bomb.
+var ErrDecompressedSizeLimit = errors.New("source is too large once decompressed")
+
+const (
+	noCompression    = ""
+	gzipCompression  = "gzip"
+	bzip2Compression = "bzip2"
+	zipArchive       = "zip"
+)
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+	zipMagic   = []byte("PK\x03\x04")
+)
+
+// detectCompression decides how a source is compressed from its name's extension, or failing that, from
+// the magic bytes it starts with. It returns the name without the compression's extension.
+func detectCompression(name string, magic []byte) (string, string) {
+	extension := strings.ToLower(path.Ext(name))
+	switch extension {
+	case ".gz", ".gzip":
+		return gzipCompression, strings.TrimSuffix(name, path.Ext(name))
+	case ".bz2":
+		return bzip2Compression, strings.TrimSuffix(name, path.Ext(name))
+	case ".zip":
+		return zipArchive, strings.TrimSuffix(name, path.Ext(name))
+	case ".xlsx", ".ods":
+		// spreadsheets are zip archives, but aren't opened as such
+		return noCompression, name
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		return gzipCompression, name
+	case bytes.HasPrefix(magic, bzip2Magic):
+		return bzip2Compression, name
+	case bytes.HasPrefix(magic, zipMagic):
+		return zipArchive, name
+	}
+	return noCompression, name
+}
+
+// sizeLimitedReader reads from an underlying reader until more than limit bytes have been read, at which
+// point it returns ErrDecompressedSizeLimit rather than carrying on.
+type sizeLimitedReader struct {
+	reader io.Reader
+	name   string
+	limit  int64
+	read   int64
+}
+
+func limitSize(reader io.Reader, name string, limit int64) io.Reader {
+	if limit < 0 {
+		return reader
+	}
+	return &sizeLimitedReader{reader: reader, name: name, limit: limit}
+}
+
+func (limited *sizeLimitedReader) Read(buffer []byte) (int, error) {
+	if limited.read > limited.limit {
+		return 0, fmt.Errorf("%s is larger than %d bytes: %w", limited.name, limited.limit, ErrDecompressedSizeLimit)
+	}
+	// read one byte more than the limit, so that a source of exactly the limit isn't mistaken for a larger one
+	if remaining := limited.limit - limited.read + 1; int64(len(buffer)) > remaining {
+		buffer = buffer[:remaining]
+	}
+	count, err := limited.reader.Read(buffer)
+	limited.read += int64(count)
+	if limited.read > limited.limit {
+		return 0, fmt.Errorf("%s is larger than %d bytes: %w", limited.name, limited.limit, ErrDecompressedSizeLimit)
+	}
+	return count, err
+}
+
+// decompress returns a reader of the decompressed contents of a gzip or bzip2 stream.
+func decompress(reader io.Reader, compression string) (io.Reader, error) {
+	switch compression {
+	case gzipCompression:
+		decompressed, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, fmt.Errorf("could not decompress gzip source: %w", err)
+		}
+		return decompressed, nil
+	case bzip2Compression:
+		return bzip2.NewReader(reader), nil
+	}
+	return reader, nil
+}
+
+// isTableMember reports whether a member of a zip archive could be a table, rather than a directory or the
+// kind of metadata left by some operating systems, e.g. __MACOSX/ or .DS_Store.
+func isTableMember(file *zip.File) bool {
+	if file.FileInfo().IsDir() || strings.HasPrefix(file.Name, "__MACOSX/") {
+		return false
+	}
+	return !strings.HasPrefix(path.Base(file.Name), ".")
+}
+
+// selectMember picks the member of archive named member, or if member is empty, the only table in the archive.
+func selectMember(archive *zip.Reader, member string) (*zip.File, error) {
+	var tables []string
+	var only *zip.File
+
+	for _, file := range archive.File {
+		if member != "" && file.Name == member {
+			return file, nil
+		}
+		if isTableMember(file) {
+			tables = append(tables, file.Name)
+			only = file
+		}
+	}
+
+	switch {
+	case member != "":
+		return nil, fmt.Errorf("there is no member named %q in the archive, which has members %s", member, strings.Join(tables, ", "))
+	case len(tables) == 0:
+		return nil, errors.New("the archive has no members")
+	case len(tables) > 1:
+		return nil, fmt.Errorf("the archive has more than one member, so one of %s must be chosen", strings.Join(tables, ", "))
+	}
+	return only, nil
+}
+
+// spreadsheetFormat reports whether a zip archive is really an Excel or OpenDocument spreadsheet, for when
+// the archive's name doesn't say.
+func spreadsheetFormat(archive *zip.Reader) string {
+	for _, file := range archive.File {
+		switch file.Name {
+		case "xl/workbook.xml":
+			// every Office Open XML document has a [Content_Types].xml, but only a workbook has this
+			return "xlsx"
+		case "mimetype":
+			reader, err := file.Open()
+			if err != nil {
+				return ""
+			}
+			mimetype, _ := io.ReadAll(io.LimitReader(reader, 128))
+			reader.Close()
+			if string(mimetype) == "application/vnd.oasis.opendocument.spreadsheet" {
+				return "ods"
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,271 @@
+package source
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// csvParser splits delimited text into records. It is used instead of Go's `encoding/csv` because that
+// library can't be configured with a different quote character, an escape character, or quotes that
+// aren't escaped by doubling them, all of which a Dialect can describe.
+type csvParser struct {
+	reader *bufio.Reader
+	err    error
+
+	delimiter        rune
+	quote            rune
+	hasQuote         bool
+	escape           rune
+	hasEscape        bool
+	doubleQuote      bool
+	skipInitialSpace bool
+	commentChar      string
+
+	line   int   // physical line of the last rune read, 1-based
+	column int   // byte column of the last rune read, 1-based
+	offset int64 // byte offset of the next rune to be read
+}
+
+func newCSVParser(r io.Reader, dialect Dialect) *csvParser {
+	parser := &csvParser{
+		reader:           bufio.NewReader(r),
+		doubleQuote:      dialect.DoubleQuote,
+		skipInitialSpace: dialect.SkipInitialSpace,
+		commentChar:      dialect.CommentChar,
+		line:             1,
+	}
+
+	if err := dialect.check(); err != nil {
+		parser.err = err
+		return parser
+	}
+
+	parser.delimiter, _ = utf8.DecodeRuneInString(dialect.Delimiter)
+	if dialect.QuoteChar != "" {
+		parser.quote, _ = utf8.DecodeRuneInString(dialect.QuoteChar)
+		parser.hasQuote = true
+	}
+	if dialect.EscapeChar != "" {
+		parser.escape, _ = utf8.DecodeRuneInString(dialect.EscapeChar)
+		parser.hasEscape = true
+	}
+	return parser
+}
+
+// NewCSVReader returns a Reader for delimited text, such as CSV or TSV, laid out as described by dialect.
+// Blank lines are skipped, as are lines starting with dialect.CommentChar. A malformed record is reported
+// as a `*csv.ParseError`, the same as Go's `encoding/csv` library would. Records have the line and byte
+// offset they start at.
+func NewCSVReader(r io.Reader, dialect Dialect) *Reader {
+	parser := newCSVParser(r, dialect)
+	return newReader(dialect, parser.readRecord)
+}
+
+func isNewline(r rune) bool {
+	return r == '\n' || r == '\r'
+}
+
+// readRune reads the next rune, treating "\r\n" as a single "\n".
+func (parser *csvParser) readRune() (rune, error) {
+	r, size, err := parser.reader.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+
+	if r == '\r' {
+		next, _, err := parser.reader.ReadRune()
+		if err == nil && next == '\n' {
+			size++
+		} else if err == nil {
+			parser.reader.UnreadRune()
+		}
+		r = '\n'
+	}
+
+	parser.column += size
+	parser.offset += int64(size)
+	return r, nil
+}
+
+// startLine moves the parser's position to the start of the next line.
+func (parser *csvParser) startLine() {
+	parser.line++
+	parser.column = 0
+}
+
+func (parser *csvParser) peekRune() (rune, error) {
+	r, _, err := parser.reader.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	parser.reader.UnreadRune()
+	return r, nil
+}
+
+// skipLine discards the rest of the current line.
+func (parser *csvParser) skipLine() error {
+	for {
+		r, err := parser.readRune()
+		if err != nil {
+			return err
+		}
+		if r == '\n' {
+			parser.startLine()
+			return nil
+		}
+	}
+}
+
+func (parser *csvParser) isCommentLine() bool {
+	if parser.commentChar == "" {
+		return false
+	}
+	prefix, _ := parser.reader.Peek(len(parser.commentChar))
+	return string(prefix) == parser.commentChar
+}
+
+func (parser *csvParser) parseError(startLine int, err error) error {
+	return &csv.ParseError{StartLine: startLine, Line: parser.line, Column: parser.column, Err: err}
+}
+
+const (
+	fieldStart = iota
+	unquotedField
+	quotedField
+	afterQuotedField
+)
+
+// readRecord reads the next record. After a parse error the rest of the line is skipped, so that
+// reading can carry on from the next line.
+func (parser *csvParser) readRecord() (Record, error) {
+	if parser.err != nil {
+		return Record{}, parser.err
+	}
+
+	// skip blank lines and comments
+	for {
+		if parser.isCommentLine() {
+			if err := parser.skipLine(); err != nil {
+				return Record{}, err
+			}
+			continue
+		}
+		r, err := parser.peekRune()
+		if err != nil {
+			return Record{}, err
+		}
+		if !isNewline(r) {
+			break
+		}
+		parser.readRune()
+		parser.startLine()
+	}
+
+	startLine := parser.line
+	position := Record{Line: startLine, Offset: parser.offset}
+	var cells []string
+	var cell strings.Builder
+	state := fieldStart
+
+	record := func() Record {
+		position.Cells = cells
+		return position
+	}
+
+	endCell := func() {
+		cells = append(cells, cell.String())
+		cell.Reset()
+		state = fieldStart
+	}
+
+	for {
+		r, err := parser.readRune()
+		if errors.Is(err, io.EOF) {
+			if state == quotedField {
+				return position, parser.parseError(startLine, csv.ErrQuote)
+			}
+			endCell()
+			return record(), nil
+		}
+		if err != nil {
+			return Record{}, err
+		}
+
+		switch state {
+		case fieldStart:
+			if parser.skipInitialSpace && r == ' ' {
+				continue
+			}
+			if parser.hasQuote && r == parser.quote {
+				state = quotedField
+				continue
+			}
+			state = unquotedField
+			fallthrough
+
+		case unquotedField:
+			switch {
+			case r == parser.delimiter:
+				endCell()
+			case r == '\n':
+				endCell()
+				parser.startLine()
+				return record(), nil
+			case parser.hasEscape && r == parser.escape:
+				if escaped, err := parser.readRune(); err == nil {
+					cell.WriteRune(escaped)
+				}
+			case parser.hasQuote && r == parser.quote:
+				err := parser.parseError(startLine, csv.ErrBareQuote)
+				parser.skipLine()
+				return position, err
+			default:
+				cell.WriteRune(r)
+			}
+
+		case quotedField:
+			switch {
+			case r == '\n':
+				cell.WriteRune(r)
+				parser.startLine()
+			case parser.hasEscape && r == parser.escape && parser.escape != parser.quote:
+				escaped, err := parser.readRune()
+				if err != nil {
+					return position, parser.parseError(startLine, csv.ErrQuote)
+				}
+				if escaped == '\n' {
+					parser.startLine()
+				}
+				cell.WriteRune(escaped)
+			case r == parser.quote:
+				next, err := parser.peekRune()
+				if err == nil && next == parser.quote && (parser.doubleQuote || parser.hasEscape && parser.escape == parser.quote) {
+					parser.readRune()
+					cell.WriteRune(r)
+				} else {
+					state = afterQuotedField
+				}
+			default:
+				cell.WriteRune(r)
+			}
+
+		case afterQuotedField:
+			switch {
+			case r == parser.delimiter:
+				endCell()
+			case r == '\n':
+				endCell()
+				parser.startLine()
+				return record(), nil
+			default:
+				err := parser.parseError(startLine, csv.ErrQuote)
+				parser.skipLine()
+				return position, err
+			}
+		}
+	}
+}
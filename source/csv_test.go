@@ -0,0 +1,138 @@
+package source
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCSVReaderDialects(t *testing.T) {
+	semicolons := DefaultDialect()
+	semicolons.Delimiter = ";"
+
+	singleQuotes := DefaultDialect()
+	singleQuotes.QuoteChar = "'"
+
+	backslashEscapes := DefaultDialect()
+	backslashEscapes.DoubleQuote = false
+	backslashEscapes.EscapeChar = `\`
+
+	initialSpaces := DefaultDialect()
+	initialSpaces.SkipInitialSpace = true
+
+	comments := DefaultDialect()
+	comments.CommentChar = "#"
+
+	testCases := []struct {
+		name     string
+		dialect  Dialect
+		input    string
+		expected [][]string
+	}{
+		{name: "default", dialect: DefaultDialect(), input: "a,b\n1,\"x,\"\"y\"\"\"\r\n\n2,z", expected: [][]string{{"a", "b"}, {"1", `x,"y"`}, {"2", "z"}}},
+		{name: "multiline cell", dialect: DefaultDialect(), input: "a,b\n1,\"x\r\ny\"\n", expected: [][]string{{"a", "b"}, {"1", "x\ny"}}},
+		{name: "delimiter", dialect: semicolons, input: "a;b\n1,5;2\n", expected: [][]string{{"a", "b"}, {"1,5", "2"}}},
+		{name: "quote character", dialect: singleQuotes, input: "a,b\n'1,2',\"3\"\n", expected: [][]string{{"a", "b"}, {"1,2", `"3"`}}},
+		{name: "escape character", dialect: backslashEscapes, input: "a,b\n\"1\\\"2\",3\\,4\n", expected: [][]string{{"a", "b"}, {`1"2`, "3,4"}}},
+		{name: "skip initial space", dialect: initialSpaces, input: "a, b\n1,  \"2\"\n", expected: [][]string{{"a", "b"}, {"1", "2"}}},
+		{name: "comment character", dialect: comments, input: "# exported today\na,b\n#1,2\n3,4\n", expected: [][]string{{"a", "b"}, {"3", "4"}}},
+	}
+
+	for _, testCase := range testCases {
+		got, err := NewCSVReader(strings.NewReader(testCase.input), testCase.dialect).ReadAll()
+		if err != nil {
+			t.Errorf("%s: failed to read with error %s", testCase.name, err.Error())
+			continue
+		}
+		if diff := cmp.Diff(testCase.expected, got); diff != "" {
+			t.Errorf("%s: (-want +got):\n%s", testCase.name, diff)
+		}
+	}
+}
+
+func TestCSVReaderHeaderRows(t *testing.T) {
+	input := "exported 2024-01-01\nsales,sales,region\nnet,gross,\n1,2,north\n3,4,south\n"
+
+	dialect := DefaultDialect()
+	dialect.HeaderRows = []int{2, 3}
+	dialect.HeaderJoin = "_"
+
+	reader := NewCSVReader(strings.NewReader(input), dialect)
+
+	header, err := reader.Header()
+	if err != nil {
+		t.Fatalf("Failed to read header with error %s", err.Error())
+	}
+	if diff := cmp.Diff([]string{"sales_net", "sales_gross", "region"}, header); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	record, err := reader.Read()
+	if err != nil {
+		t.Fatalf("Failed to read record with error %s", err.Error())
+	}
+	if diff := cmp.Diff([]string{"1", "2", "north"}, record.Cells); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	headerless := DefaultDialect()
+	headerless.Header = false
+	headerless.CommentRows = []int{1}
+
+	got, err := NewCSVReader(strings.NewReader("skip me\n1,2\n"), headerless).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read headerless source with error %s", err.Error())
+	}
+	if diff := cmp.Diff([][]string{{"1", "2"}}, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestCSVReaderErrors(t *testing.T) {
+	_, err := NewCSVReader(strings.NewReader("a,b\n1,\"2\"x\n"), DefaultDialect()).ReadAll()
+
+	var parseError *csv.ParseError
+	if !errors.As(err, &parseError) || !errors.Is(err, csv.ErrQuote) || parseError.Line != 2 {
+		t.Errorf("Expected a quote error on line 2, got %v", err)
+	}
+
+	dialect := DefaultDialect()
+	dialect.Delimiter = ";;"
+	_, err = NewCSVReader(strings.NewReader("a;;b\n"), dialect).ReadAll()
+	if !errors.Is(err, ErrInvalidDelimiter) {
+		t.Errorf("Expected an invalid delimiter error, got %v", err)
+	}
+}
+
+func TestCSVReaderPositions(t *testing.T) {
+	input := "id,note\r\n\r\n1,\"two\nlines\"\n2,x\"y\n3,z\n"
+	reader := NewCSVReader(strings.NewReader(input), DefaultDialect())
+
+	type position struct {
+		Row    int
+		Line   int
+		Offset int64
+	}
+	var got []position
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if err != nil && !errors.As(err, &parseErr) {
+			t.Fatalf("Failed to read with error %s", err.Error())
+		}
+		got = append(got, position{Row: record.Row, Line: record.Line, Offset: record.Offset})
+	}
+
+	// the blank line is skipped without taking up a row, and the malformed record on line 5 still does
+	expected := []position{{Row: 2, Line: 3, Offset: 11}, {Row: 3, Line: 5, Offset: 25}, {Row: 4, Line: 6, Offset: 31}}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,131 @@
+// Package source reads tabular data into records that the validate package can check against a schema.
+// How a source is laid out - its delimiter, quoting, which rows are headers and so on - is described by
+// a Dialect, as defined [here](https://datapackage.org/standard/table-dialect/).
+package source
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+// A Dialect describes how the rows and cells of a tabular source are laid out. The zero value is not a
+// sensible dialect (it has no header and no delimiter), so start from DefaultDialect and change the
+// properties that differ, in the same way as you would in a JSON dialect descriptor.
+type Dialect struct {
+	// Header is true if the source has header rows naming its columns. If it is false, columns are
+	// matched to schema fields by position.
+	Header bool `json:"header"`
+	// HeaderRows are the 1-based row numbers of the header rows. Rows before the last header row that
+	// aren't header rows are skipped.
+	HeaderRows []int `json:"headerRows,omitempty"`
+	// HeaderJoin joins the values of a column's header rows when there is more than one.
+	HeaderJoin string `json:"headerJoin,omitempty"`
+	// CommentRows are the 1-based row numbers of rows which are skipped.
+	CommentRows []int `json:"commentRows,omitempty"`
+	// CommentChar marks a line as a comment, which is skipped, when the line starts with it.
+	CommentChar string `json:"commentChar,omitempty"`
+
+	Delimiter        string `json:"delimiter,omitempty"`
+	QuoteChar        string `json:"quoteChar,omitempty"`
+	DoubleQuote      bool   `json:"doubleQuote"`
+	EscapeChar       string `json:"escapeChar,omitempty"`
+	SkipInitialSpace bool   `json:"skipInitialSpace,omitempty"`
+
+	// Property is the dot-separated path to the array of rows in a JSON document, if the rows aren't
+	// the document itself.
+	Property string `json:"property,omitempty"`
+	// ItemType is "array" if the rows of a JSON source are arrays of cells, or "object" if they are
+	// objects keyed by field name. If it is empty, it is decided by the first row.
+	ItemType string `json:"itemType,omitempty"`
+	// ItemKeys are the keys read, in order, from the rows of a JSON source whose rows are objects. If
+	// they aren't given, every key of every row is read.
+	ItemKeys []string `json:"itemKeys,omitempty"`
+
+	// SheetName is the name of the sheet to read from a spreadsheet. It takes precedence over SheetNumber.
+	SheetName string `json:"sheetName,omitempty"`
+	// SheetNumber is the 1-based position of the sheet to read from a spreadsheet. 0 means the first sheet.
+	SheetNumber int `json:"sheetNumber,omitempty"`
+}
+
+// DefaultDialect returns the dialect the Table Dialect spec assumes when a property isn't given: a single
+// header row, comma delimiters and double-quoted cells in which quotes are escaped by doubling them.
+func DefaultDialect() Dialect {
+	return Dialect{
+		Header:      true,
+		HeaderRows:  []int{1},
+		HeaderJoin:  " ",
+		Delimiter:   ",",
+		QuoteChar:   `"`,
+		DoubleQuote: true,
+	}
+}
+
+var (
+	ErrInvalidDelimiter  = errors.New("dialect delimiter must be a single character")
+	ErrInvalidQuoteChar  = errors.New("dialect quoteChar must be a single character or empty")
+	ErrInvalidEscapeChar = errors.New("dialect escapeChar must be a single character or empty")
+	ErrInvalidHeaderRows = errors.New("dialect headerRows must be positive row numbers")
+	ErrInvalidItemType   = errors.New(`dialect itemType must be "array", "object" or empty`)
+)
+
+func isSingleCharacter(value string) bool {
+	return utf8.RuneCountInString(value) == 1
+}
+
+// check reports whether the dialect can be used to read a source.
+func (dialect Dialect) check() error {
+	if !isSingleCharacter(dialect.Delimiter) || dialect.Delimiter == "\n" || dialect.Delimiter == "\r" {
+		return ErrInvalidDelimiter
+	}
+	if dialect.QuoteChar != "" && !isSingleCharacter(dialect.QuoteChar) {
+		return ErrInvalidQuoteChar
+	}
+	if dialect.EscapeChar != "" && !isSingleCharacter(dialect.EscapeChar) {
+		return ErrInvalidEscapeChar
+	}
+	for _, row := range dialect.HeaderRows {
+		if row < 1 {
+			return ErrInvalidHeaderRows
+		}
+	}
+	return nil
+}
+
+// lastHeaderRow is the row number after which data rows start.
+func (dialect Dialect) lastHeaderRow() int {
+	if !dialect.Header {
+		return 0
+	}
+	if len(dialect.HeaderRows) == 0 {
+		return 1
+	}
+	last := 0
+	for _, row := range dialect.HeaderRows {
+		last = max(last, row)
+	}
+	return last
+}
+
+func (dialect Dialect) isHeaderRow(rowNumber int) bool {
+	if !dialect.Header {
+		return false
+	}
+	if len(dialect.HeaderRows) == 0 {
+		return rowNumber == 1
+	}
+	for _, row := range dialect.HeaderRows {
+		if row == rowNumber {
+			return true
+		}
+	}
+	return false
+}
+
+func (dialect Dialect) isCommentRow(rowNumber int) bool {
+	for _, row := range dialect.CommentRows {
+		if row == rowNumber {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,248 @@
+package source
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// The encodings a source can be decoded from. AutoEncoding detects which of the others a source is in.
+const (
+	UTF8Encoding        = "utf-8"
+	UTF16LEEncoding     = "utf-16le"
+	UTF16BEEncoding     = "utf-16be"
+	Latin1Encoding      = "latin-1"
+	Windows1252Encoding = "windows-1252"
+	AutoEncoding        = "auto"
+)
+
+// An EncodingError reports a sequence of bytes that isn't valid in the encoding a source was decoded from.
+type EncodingError struct {
+	Encoding string
+	// Offset is the position of the invalid sequence in the source, in bytes from its start.
+	Offset int64
+	Bytes  []byte
+}
+
+func (err *EncodingError) Error() string {
+	return fmt.Sprintf("invalid %s byte sequence % x at byte offset %d", err.Encoding, err.Bytes, err.Offset)
+}
+
+// byteOrderMark is the byte order mark once it has been decoded into a string.
+const byteOrderMark = "\ufeff"
+
+var byteOrderMarks = map[string][]byte{
+	UTF8Encoding:    {0xef, 0xbb, 0xbf},
+	UTF16LEEncoding: {0xff, 0xfe},
+	UTF16BEEncoding: {0xfe, 0xff},
+}
+
+// windows1252 maps the bytes 0x80 to 0x9F, where windows-1252 differs from latin-1, to their characters.
+// The bytes it leaves undefined are 0.
+var windows1252 = [32]rune{
+	0x20ac, 0, 0x201a, 0x0192, 0x201e, 0x2026, 0x2020, 0x2021, 0x02c6, 0x2030, 0x0160, 0x2039, 0x0152, 0, 0x017d, 0,
+	0, 0x2018, 0x2019, 0x201c, 0x201d, 0x2022, 0x2013, 0x2014, 0x02dc, 0x2122, 0x0161, 0x203a, 0x0153, 0, 0x017e, 0x0178,
+}
+
+// normaliseEncoding returns the name of an encoding as one of the constants above, accepting common aliases.
+func normaliseEncoding(encoding string) (string, error) {
+	switch strings.ReplaceAll(strings.ToLower(encoding), "_", "-") {
+	case "", "utf-8", "utf8":
+		return UTF8Encoding, nil
+	case "utf-16le", "utf-16-le", "utf16le":
+		return UTF16LEEncoding, nil
+	case "utf-16be", "utf-16-be", "utf16be":
+		return UTF16BEEncoding, nil
+	case "utf-16", "utf16":
+		// without a byte order mark, UTF-16 is big-endian, but the mark is checked for when decoding
+		return "utf-16", nil
+	case "latin-1", "latin1", "iso-8859-1", "iso8859-1":
+		return Latin1Encoding, nil
+	case "windows-1252", "cp1252":
+		return Windows1252Encoding, nil
+	case "auto":
+		return AutoEncoding, nil
+	}
+	return "", fmt.Errorf("unknown encoding %q", encoding)
+}
+
+// DetectEncoding makes a best guess at the encoding of sample, which should be the start of a source.
+// A byte order mark is trusted if there is one. Otherwise, text with many zero bytes in alternate
+// positions is taken to be UTF-16, valid UTF-8 is UTF-8, and anything else is windows-1252, or latin-1
+// if it uses bytes that windows-1252 leaves undefined.
+func DetectEncoding(sample []byte) string {
+	for _, encoding := range []string{UTF8Encoding, UTF16LEEncoding, UTF16BEEncoding} {
+		if bytes.HasPrefix(sample, byteOrderMarks[encoding]) {
+			return encoding
+		}
+	}
+
+	evenZeros, oddZeros := 0, 0
+	for index, character := range sample {
+		if character != 0 {
+			continue
+		}
+		if index%2 == 0 {
+			evenZeros++
+		} else {
+			oddZeros++
+		}
+	}
+	pairs := len(sample) / 2
+	switch {
+	case pairs > 0 && oddZeros > pairs/4 && evenZeros <= oddZeros/10:
+		return UTF16LEEncoding
+	case pairs > 0 && evenZeros > pairs/4 && oddZeros <= evenZeros/10:
+		return UTF16BEEncoding
+	}
+
+	// the sample may end part way through a character, which doesn't make it invalid
+	valid := sample
+	for index := 0; index < utf8.UTFMax && len(valid) > 0 && !utf8.Valid(valid); index++ {
+		valid = valid[:len(valid)-1]
+	}
+	if utf8.Valid(valid) && len(sample)-len(valid) < utf8.UTFMax {
+		return UTF8Encoding
+	}
+
+	for _, character := range sample {
+		if character >= 0x80 && character <= 0x9f && windows1252[character-0x80] == 0 {
+			return Latin1Encoding
+		}
+	}
+	return Windows1252Encoding
+}
+
+// decodingReader converts a source from its encoding into UTF-8 as it is read.
+type decodingReader struct {
+	source   *bufio.Reader
+	encoding string
+	offset   int64
+	pending  []byte
+	err      error
+}
+
+// NewDecoder returns a reader of source, converted from encoding into UTF-8. A byte order mark at the
+// start of the source is removed. If encoding is AutoEncoding, it is detected with DetectEncoding from
+// the start of the source. Bytes which aren't valid in the encoding are reported as an *EncodingError,
+// rather than being replaced, so that they don't end up in values unnoticed.
+func NewDecoder(source io.Reader, encoding string) (io.Reader, string, error) {
+	encoding, err := normaliseEncoding(encoding)
+	if err != nil {
+		return nil, "", err
+	}
+
+	buffered := bufio.NewReaderSize(source, 64*1024)
+	sample, _ := buffered.Peek(64 * 1024)
+
+	if encoding == AutoEncoding {
+		encoding = DetectEncoding(sample)
+	}
+	if encoding == "utf-16" {
+		encoding = UTF16BEEncoding
+		if bytes.HasPrefix(sample, byteOrderMarks[UTF16LEEncoding]) {
+			encoding = UTF16LEEncoding
+		}
+	}
+
+	decoder := &decodingReader{source: buffered, encoding: encoding}
+	if mark := byteOrderMarks[encoding]; mark != nil && bytes.HasPrefix(sample, mark) {
+		buffered.Discard(len(mark))
+		decoder.offset = int64(len(mark))
+	}
+	return decoder, encoding, nil
+}
+
+func (decoder *decodingReader) invalid(sequence ...byte) error {
+	return &EncodingError{Encoding: decoder.encoding, Offset: decoder.offset, Bytes: sequence}
+}
+
+// decodeRune reads the next character of the source, returning it along with the number of bytes it took up.
+func (decoder *decodingReader) decodeRune() (rune, int, error) {
+	switch decoder.encoding {
+	case UTF16LEEncoding, UTF16BEEncoding:
+		unit := make([]byte, 2)
+		readUnit := func() (rune, error) {
+			count, err := io.ReadFull(decoder.source, unit)
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				return 0, decoder.invalid(unit[:count]...)
+			}
+			if err != nil {
+				return 0, err
+			}
+			if decoder.encoding == UTF16LEEncoding {
+				return rune(unit[0]) | rune(unit[1])<<8, nil
+			}
+			return rune(unit[0])<<8 | rune(unit[1]), nil
+		}
+
+		first, err := readUnit()
+		if err != nil {
+			return 0, 0, err
+		}
+		if !utf16.IsSurrogate(first) {
+			return first, 2, nil
+		}
+		firstUnit := append([]byte{}, unit...)
+		second, err := readUnit()
+		if errors.Is(err, io.EOF) {
+			return 0, 0, decoder.invalid(firstUnit...)
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		character := utf16.DecodeRune(first, second)
+		if character == utf8.RuneError {
+			return 0, 0, decoder.invalid(append(firstUnit, unit...)...)
+		}
+		return character, 4, nil
+
+	case Latin1Encoding, Windows1252Encoding:
+		character, err := decoder.source.ReadByte()
+		if err != nil {
+			return 0, 0, err
+		}
+		if decoder.encoding == Windows1252Encoding && character >= 0x80 && character <= 0x9f {
+			if windows1252[character-0x80] == 0 {
+				return 0, 0, decoder.invalid(character)
+			}
+			return windows1252[character-0x80], 1, nil
+		}
+		return rune(character), 1, nil
+	}
+
+	character, size, err := decoder.source.ReadRune()
+	if err != nil {
+		return 0, 0, err
+	}
+	if character == utf8.RuneError && size == 1 {
+		decoder.source.UnreadRune()
+		invalid, _ := decoder.source.ReadByte()
+		return 0, 0, decoder.invalid(invalid)
+	}
+	return character, size, nil
+}
+
+func (decoder *decodingReader) Read(buffer []byte) (int, error) {
+	for len(decoder.pending) < len(buffer) && decoder.err == nil {
+		character, size, err := decoder.decodeRune()
+		if err != nil {
+			decoder.err = err
+			break
+		}
+		decoder.offset += int64(size)
+		decoder.pending = utf8.AppendRune(decoder.pending, character)
+	}
+
+	count := copy(buffer, decoder.pending)
+	decoder.pending = decoder.pending[count:]
+	if count == 0 && decoder.err != nil {
+		return 0, decoder.err
+	}
+	return count, nil
+}
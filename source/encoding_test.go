@@ -0,0 +1,116 @@
+package source
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecoder(t *testing.T) {
+	testCases := []struct {
+		name     string
+		encoding string
+		input    []byte
+		expected string
+		detected string
+	}{
+		{name: "utf-8 with byte order mark", encoding: "", input: []byte("\xef\xbb\xbfnamé,b\n"), expected: "namé,b\n", detected: UTF8Encoding},
+		{name: "utf-16le with byte order mark", encoding: "utf-16", input: []byte("\xff\xfea\x00,\x00\xe9\x00\n\x00"), expected: "a,é\n", detected: UTF16LEEncoding},
+		{name: "utf-16be surrogate pair", encoding: "utf-16be", input: []byte("\x00a\xd8\x3d\xde\x00"), expected: "a😀", detected: UTF16BEEncoding},
+		{name: "latin-1", encoding: "ISO-8859-1", input: []byte("caf\xe9\x80"), expected: "café\u0080", detected: Latin1Encoding},
+		{name: "windows-1252", encoding: "cp1252", input: []byte("\x93caf\xe9\x94 \x80"), expected: "“café” €", detected: Windows1252Encoding},
+		{name: "detected utf-8", encoding: "auto", input: []byte("naïve,b\n"), expected: "naïve,b\n", detected: UTF8Encoding},
+		{name: "detected utf-16le", encoding: "auto", input: []byte("a\x00,\x00b\x00\n\x00"), expected: "a,b\n", detected: UTF16LEEncoding},
+		{name: "detected windows-1252", encoding: "auto", input: []byte("caf\xe9 \x80\n"), expected: "café €\n", detected: Windows1252Encoding},
+		{name: "detected latin-1", encoding: "auto", input: []byte("caf\xe9 \x81\n"), expected: "café \u0081\n", detected: Latin1Encoding},
+	}
+
+	for _, testCase := range testCases {
+		decoder, detected, err := NewDecoder(bytes.NewReader(testCase.input), testCase.encoding)
+		if err != nil {
+			t.Errorf("%s: failed to create decoder with error %s", testCase.name, err.Error())
+			continue
+		}
+		got, err := io.ReadAll(decoder)
+		if err != nil {
+			t.Errorf("%s: failed to decode with error %s", testCase.name, err.Error())
+			continue
+		}
+		if diff := cmp.Diff(testCase.expected, string(got)); diff != "" {
+			t.Errorf("%s (-want +got):\n%s", testCase.name, diff)
+		}
+		if detected != testCase.detected {
+			t.Errorf("%s: expected the encoding to be %s, got %s", testCase.name, testCase.detected, detected)
+		}
+	}
+}
+
+func TestDecoderInvalidBytes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		encoding string
+		input    []byte
+		expected EncodingError
+	}{
+		{name: "utf-8", encoding: "utf-8", input: []byte("\xef\xbb\xbfab,c\xff\n"), expected: EncodingError{Encoding: UTF8Encoding, Offset: 7, Bytes: []byte{0xff}}},
+		{name: "unpaired surrogate", encoding: "utf-16le", input: []byte("a\x00\x3d\xd8b\x00"), expected: EncodingError{Encoding: UTF16LEEncoding, Offset: 2, Bytes: []byte{0x3d, 0xd8, 'b', 0x00}}},
+		{name: "odd length utf-16", encoding: "utf-16be", input: []byte("\x00a\x00"), expected: EncodingError{Encoding: UTF16BEEncoding, Offset: 2, Bytes: []byte{0x00}}},
+		{name: "undefined windows-1252", encoding: "windows-1252", input: []byte("ab\x8d"), expected: EncodingError{Encoding: Windows1252Encoding, Offset: 2, Bytes: []byte{0x8d}}},
+	}
+
+	for _, testCase := range testCases {
+		decoder, _, err := NewDecoder(bytes.NewReader(testCase.input), testCase.encoding)
+		if err == nil {
+			_, err = io.ReadAll(decoder)
+		}
+		var encodingErr *EncodingError
+		if !errors.As(err, &encodingErr) {
+			t.Errorf("%s: expected an encoding error, got %v", testCase.name, err)
+			continue
+		}
+		if diff := cmp.Diff(testCase.expected, *encodingErr); diff != "" {
+			t.Errorf("%s (-want +got):\n%s", testCase.name, diff)
+		}
+	}
+
+	if _, _, err := NewDecoder(bytes.NewReader(nil), "ebcdic"); err == nil {
+		t.Error("Expected an error for an unknown encoding")
+	}
+}
+
+func TestOpenReaderEncoding(t *testing.T) {
+	options := DefaultOptions()
+	options.Encoding = "auto"
+
+	file, err := OpenReader(bytes.NewReader([]byte("\xff\xfei\x00d\x00,\x00n\x00\n\x001\x00,\x00\xe9\x00\n\x00")), "data.csv", options)
+	got := readAllFrom(t, file, err)
+	if diff := cmp.Diff([][]string{{"id", "n"}, {"1", "é"}}, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+	if file.Encoding() != UTF16LEEncoding {
+		t.Errorf("Expected the encoding to be detected as %s, got %s", UTF16LEEncoding, file.Encoding())
+	}
+
+	file, err = OpenReader(bytes.NewReader([]byte("id,n\n1,caf\xe9\n")), "data.csv", DefaultOptions())
+	if err == nil {
+		_, err = file.ReadAll()
+	}
+	var encodingErr *EncodingError
+	if !errors.As(err, &encodingErr) || encodingErr.Offset != 10 {
+		t.Errorf("Expected an encoding error at byte offset 10, got %v", err)
+	}
+}
+
+func TestReaderRemovesByteOrderMark(t *testing.T) {
+	reader := NewRecordsReader([][]string{{"\ufeffid", "name"}, {"1", "a"}}, DefaultDialect())
+	header, err := reader.Header()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"id", "name"}, header); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
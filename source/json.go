@@ -0,0 +1,357 @@
+package source
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// jsonObject is a decoded JSON object which remembers the order of its keys, so that the columns of a
+// JSON source come out in the order they were written.
+type jsonObject struct {
+	keys   []string
+	values map[string]any
+}
+
+// decodeOrdered decodes the next JSON value from decoder. Objects are decoded as jsonObjects and
+// numbers as json.Numbers, so that they aren't rounded by being turned into float64s.
+func decodeOrdered(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delimiter, isDelimiter := token.(json.Delim)
+	if !isDelimiter {
+		return token, nil
+	}
+
+	switch delimiter {
+	case '[':
+		array := []any{}
+		for decoder.More() {
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err := decoder.Token()
+		return array, err
+	case '{':
+		object := jsonObject{values: make(map[string]any)}
+		for decoder.More() {
+			keyToken, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := keyToken.(string)
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := object.values[key]; !exists {
+				object.keys = append(object.keys, key)
+			}
+			object.values[key] = value
+		}
+		_, err := decoder.Token()
+		return object, err
+	}
+	return nil, fmt.Errorf("unexpected JSON delimiter %s", delimiter)
+}
+
+// nativeValue converts a value from decodeOrdered into the plain Go value put in a Record's Values.
+func nativeValue(value any) any {
+	switch typed := value.(type) {
+	case jsonObject:
+		native := make(map[string]any, len(typed.keys))
+		for _, key := range typed.keys {
+			native[key] = nativeValue(typed.values[key])
+		}
+		return native
+	case []any:
+		native := make([]any, len(typed))
+		for index, item := range typed {
+			native[index] = nativeValue(item)
+		}
+		return native
+	}
+	return value
+}
+
+// writeJSON writes value as compact JSON, keeping the key order of jsonObjects.
+func writeJSON(buffer *bytes.Buffer, value any) {
+	switch typed := value.(type) {
+	case jsonObject:
+		buffer.WriteByte('{')
+		for index, key := range typed.keys {
+			if index > 0 {
+				buffer.WriteByte(',')
+			}
+			encodedKey, _ := json.Marshal(key)
+			buffer.Write(encodedKey)
+			buffer.WriteByte(':')
+			writeJSON(buffer, typed.values[key])
+		}
+		buffer.WriteByte('}')
+	case []any:
+		buffer.WriteByte('[')
+		for index, item := range typed {
+			if index > 0 {
+				buffer.WriteByte(',')
+			}
+			writeJSON(buffer, item)
+		}
+		buffer.WriteByte(']')
+	default:
+		encoded, _ := json.Marshal(typed)
+		buffer.Write(encoded)
+	}
+}
+
+// cellText is the textual form of a JSON value, as it would be written in a CSV.
+func cellText(value any) string {
+	switch typed := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	case json.Number:
+		return typed.String()
+	case bool:
+		return strconv.FormatBool(typed)
+	}
+	var buffer bytes.Buffer
+	writeJSON(&buffer, value)
+	return buffer.String()
+}
+
+// jsonItem is a decoded row along with where it starts in the source, if that is known (see Record).
+type jsonItem struct {
+	value  any
+	line   int
+	offset int64
+}
+
+// jsonRows turns the decoded rows of a JSON source into Records, following the item properties of a Dialect.
+type jsonRows struct {
+	dialect Dialect
+	// next returns the next decoded row, or io.EOF
+	next     func() (jsonItem, error)
+	peeked   []jsonItem
+	rowIndex int
+	// keyed is true if the rows are objects rather than arrays, as decided by readKeys
+	keyed bool
+}
+
+func (rows *jsonRows) nextItem() (jsonItem, error) {
+	if len(rows.peeked) > 0 {
+		item := rows.peeked[0]
+		rows.peeked = rows.peeked[1:]
+		return item, nil
+	}
+	return rows.next()
+}
+
+func (rows *jsonRows) peekItem() (jsonItem, error) {
+	if len(rows.peeked) == 0 {
+		item, err := rows.next()
+		if err != nil {
+			return jsonItem{}, err
+		}
+		rows.peeked = append(rows.peeked, item)
+	}
+	return rows.peeked[0], nil
+}
+
+func describeJSON(value any) string {
+	switch value.(type) {
+	case jsonObject:
+		return "an object"
+	case []any:
+		return "an array"
+	case string:
+		return "a string"
+	case json.Number:
+		return "a number"
+	case bool:
+		return "a boolean"
+	}
+	return "null"
+}
+
+// readKeys decides whether the rows are keyed objects or positional arrays, returning the header for keyed rows.
+func (rows *jsonRows) readKeys() ([]string, bool, error) {
+	switch rows.dialect.ItemType {
+	case "", "array", "object":
+	default:
+		return nil, false, ErrInvalidItemType
+	}
+
+	first, err := rows.peekItem()
+	if errors.Is(err, io.EOF) {
+		rows.keyed = rows.dialect.ItemType == "object"
+		return rows.dialect.ItemKeys, rows.keyed, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	firstObject, isObject := first.value.(jsonObject)
+	rows.keyed = rows.dialect.ItemType == "object" || rows.dialect.ItemType == "" && isObject
+	if !rows.keyed {
+		return nil, false, nil
+	}
+	if rows.dialect.ItemKeys != nil {
+		return rows.dialect.ItemKeys, true, nil
+	}
+	return firstObject.keys, true, nil
+}
+
+func (rows *jsonRows) readRecord() (Record, error) {
+	item, err := rows.nextItem()
+	if err != nil {
+		return Record{}, err
+	}
+	rows.rowIndex++
+
+	switch typed := item.value.(type) {
+	case []any:
+		if rows.keyed {
+			break
+		}
+		record := Record{Cells: make([]string, len(typed)), Values: make([]any, len(typed)), Line: item.line, Offset: item.offset}
+		for index, value := range typed {
+			record.Cells[index] = cellText(value)
+			record.Values[index] = nativeValue(value)
+		}
+		return record, nil
+
+	case jsonObject:
+		if !rows.keyed {
+			break
+		}
+		keys := typed.keys
+		record := Record{Line: item.line, Offset: item.offset}
+		if rows.dialect.ItemKeys != nil {
+			keys = rows.dialect.ItemKeys
+		} else {
+			record.Keys = keys
+		}
+		record.Cells = make([]string, len(keys))
+		record.Values = make([]any, len(keys))
+		for index, key := range keys {
+			record.Cells[index] = cellText(typed.values[key])
+			record.Values[index] = nativeValue(typed.values[key])
+		}
+		return record, nil
+	}
+
+	expected := "an array"
+	if rows.keyed {
+		expected = "an object"
+	}
+	return Record{}, fmt.Errorf("row %d of the JSON source is %s, but should be %s", rows.rowIndex, describeJSON(item.value), expected)
+}
+
+func newJSONRowsReader(dialect Dialect, next func() (jsonItem, error)) *Reader {
+	rows := &jsonRows{dialect: dialect, next: next}
+	reader := newReader(dialect, rows.readRecord)
+	reader.readKeys = rows.readKeys
+	return reader
+}
+
+// findProperty follows a dot-separated path of keys from document to the array of rows.
+func findProperty(document any, property string) ([]any, error) {
+	value := document
+	if property != "" {
+		for _, key := range strings.Split(property, ".") {
+			object, ok := value.(jsonObject)
+			if !ok {
+				return nil, fmt.Errorf("could not find property %q in the JSON source: %s is not an object", property, describeJSON(value))
+			}
+			value, ok = object.values[key]
+			if !ok {
+				return nil, fmt.Errorf("could not find property %q in the JSON source", property)
+			}
+		}
+	}
+
+	rows, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("the rows of the JSON source should be an array, but are %s", describeJSON(value))
+	}
+	return rows, nil
+}
+
+// NewJSONReader returns a Reader for a JSON document whose rows are an array of arrays or an array of
+// objects, found at dialect.Property. When the rows are arrays, the first is the header unless the
+// dialect says otherwise. When they are objects, the keys name the columns and there are no header rows.
+// Records keep the native JSON values of their cells in Values.
+func NewJSONReader(r io.Reader, dialect Dialect) *Reader {
+	var rows []any
+	var decodeErr error
+	decoded := false
+
+	return newJSONRowsReader(dialect, func() (jsonItem, error) {
+		if !decoded {
+			decoded = true
+			decoder := json.NewDecoder(r)
+			decoder.UseNumber()
+			document, err := decodeOrdered(decoder)
+			if err == nil {
+				rows, err = findProperty(document, dialect.Property)
+			}
+			decodeErr = err
+		}
+		if decodeErr != nil {
+			return jsonItem{}, decodeErr
+		}
+		if len(rows) == 0 {
+			return jsonItem{}, io.EOF
+		}
+		row := rows[0]
+		rows = rows[1:]
+		// where the rows of a JSON document are isn't kept when it is decoded
+		return jsonItem{value: row, offset: -1}, nil
+	})
+}
+
+// NewNDJSONReader returns a Reader for newline-delimited JSON, in which each non-blank line is a row,
+// either an array or an object, read as it would be by NewJSONReader. Records have the line and byte
+// offset they start at.
+func NewNDJSONReader(r io.Reader, dialect Dialect) *Reader {
+	lines := bufio.NewReader(r)
+	lineNumber := 0
+	var offset int64
+
+	return newJSONRowsReader(dialect, func() (jsonItem, error) {
+		for {
+			line, err := lines.ReadBytes('\n')
+			if len(line) == 0 && err != nil {
+				return jsonItem{}, err
+			}
+			lineNumber++
+			lineOffset := offset
+			offset += int64(len(line))
+
+			if len(bytes.TrimSpace(line)) == 0 {
+				continue
+			}
+
+			decoder := json.NewDecoder(bytes.NewReader(line))
+			decoder.UseNumber()
+			row, decodeErr := decodeOrdered(decoder)
+			if decodeErr != nil {
+				return jsonItem{}, fmt.Errorf("line %d of the NDJSON source is not valid JSON: %w", lineNumber, decodeErr)
+			}
+			return jsonItem{value: row, line: lineNumber, offset: lineOffset}, nil
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package source
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func readTable(t *testing.T, reader *Reader) ([]string, []Record) {
+	t.Helper()
+
+	header, err := reader.Header()
+	if err != nil {
+		t.Fatalf("Failed to read header with error %s", err.Error())
+	}
+
+	var records []Record
+	for {
+		record, err := reader.Read()
+		if err != nil {
+			break
+		}
+		records = append(records, record)
+	}
+	return header, records
+}
+
+func TestJSONReaderArrays(t *testing.T) {
+	input := `{"meta": {}, "result": {"rows": [["id", "score", "ok"], [1, 1e5, true], [2, null, "no"]]}}`
+
+	dialect := DefaultDialect()
+	dialect.Property = "result.rows"
+
+	header, records := readTable(t, NewJSONReader(strings.NewReader(input), dialect))
+
+	if diff := cmp.Diff([]string{"id", "score", "ok"}, header); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	expected := []Record{
+		{Cells: []string{"1", "1e5", "true"}, Values: []any{json.Number("1"), json.Number("1e5"), true}, Row: 2, Offset: -1},
+		{Cells: []string{"2", "", "no"}, Values: []any{json.Number("2"), nil, "no"}, Row: 3, Offset: -1},
+	}
+	if diff := cmp.Diff(expected, records); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestJSONReaderObjects(t *testing.T) {
+	input := `[{"id": 1, "tags": ["a", "b"], "owner": {"name": "x", "age": 3}}, {"name": "y", "id": 2}]`
+
+	header, records := readTable(t, NewJSONReader(strings.NewReader(input), DefaultDialect()))
+
+	if diff := cmp.Diff([]string{"id", "tags", "owner"}, header); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	expected := []Record{
+		{
+			Cells:  []string{"1", `["a","b"]`, `{"name":"x","age":3}`},
+			Values: []any{json.Number("1"), []any{"a", "b"}, map[string]any{"name": "x", "age": json.Number("3")}},
+			Keys:   []string{"id", "tags", "owner"},
+			Row:    1,
+			Offset: -1,
+		},
+		{Cells: []string{"y", "2"}, Values: []any{"y", json.Number("2")}, Keys: []string{"name", "id"}, Row: 2, Offset: -1},
+	}
+	if diff := cmp.Diff(expected, records); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	dialect := DefaultDialect()
+	dialect.ItemKeys = []string{"name", "id"}
+
+	header, records = readTable(t, NewJSONReader(strings.NewReader(input), dialect))
+
+	if diff := cmp.Diff([]string{"name", "id"}, header); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+	expected = []Record{
+		{Cells: []string{"", "1"}, Values: []any{nil, json.Number("1")}, Row: 1, Offset: -1},
+		{Cells: []string{"y", "2"}, Values: []any{"y", json.Number("2")}, Row: 2, Offset: -1},
+	}
+	if diff := cmp.Diff(expected, records); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestNDJSONReader(t *testing.T) {
+	input := "{\"id\": 1, \"ok\": false}\n\n{\"id\": 2, \"ok\": true}\n"
+
+	header, records := readTable(t, NewNDJSONReader(strings.NewReader(input), DefaultDialect()))
+
+	if diff := cmp.Diff([]string{"id", "ok"}, header); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+	if len(records) != 2 || records[1].Values[1] != true {
+		t.Errorf("Expected two records with native booleans, got %v", records)
+	}
+	if records[1].Row != 2 || records[1].Line != 3 || records[1].Offset != 24 {
+		t.Errorf("Expected the second record to be row 2, starting on line 3 at byte 24, got row %d, line %d, byte %d", records[1].Row, records[1].Line, records[1].Offset)
+	}
+
+	_, err := NewNDJSONReader(strings.NewReader("[1]\n{\"id\": 2}\n"), DefaultDialect()).ReadAll()
+	if err == nil || err.Error() != "row 2 of the JSON source is an object, but should be an array" {
+		t.Errorf("Expected an error for mixed row types, got %v", err)
+	}
+}
@@ -0,0 +1,263 @@
+package source
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// odsSheet reads the rows of a table in an OpenDocument spreadsheet's content.xml.
+type odsSheet struct {
+	decoder   *xml.Decoder
+	closer    io.Closer
+	rowNumber int
+	// a row is repeated when table:number-rows-repeated is set, in which case it's kept here until it has been read enough times
+	repeatedRow spreadsheetRow
+	repeats     int
+}
+
+func attribute(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+func repeatCount(element xml.StartElement, name string) int {
+	count, err := strconv.Atoi(attribute(element, name))
+	if err != nil || count < 1 {
+		return 1
+	}
+	return count
+}
+
+var odsDurationPattern = regexp.MustCompile(`^-?PT(\d+)H(\d+)M(\d+)(?:\.\d+)?S$`)
+
+// odsTime turns an ISO 8601 duration, as used for time values, e.g. PT13H05M00S, into a time of day, e.g. 13:05:00.
+func odsTime(duration string) string {
+	match := odsDurationPattern.FindStringSubmatch(duration)
+	if match == nil {
+		return duration
+	}
+	parts := make([]string, 3)
+	for index, part := range match[1:] {
+		value, _ := strconv.Atoi(part)
+		parts[index] = fmt.Sprintf("%02d", value)
+	}
+	return strings.Join(parts, ":")
+}
+
+// readParagraphs reads the text of a cell's paragraphs, joining them with newlines.
+func readParagraphs(decoder *xml.Decoder) (string, error) {
+	var paragraphs []string
+	var text strings.Builder
+	depth := 1
+	inParagraph := false
+
+	for depth > 0 {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", err
+		}
+
+		switch typed := token.(type) {
+		case xml.StartElement:
+			depth++
+			switch typed.Name.Local {
+			case "p", "h":
+				if depth == 2 {
+					inParagraph = true
+					text.Reset()
+				}
+			case "s":
+				text.WriteString(strings.Repeat(" ", repeatCount(typed, "c")))
+			case "tab":
+				text.WriteString("\t")
+			case "line-break":
+				text.WriteString("\n")
+			case "annotation":
+				// comments aren't part of the cell's value
+				if err := decoder.Skip(); err != nil {
+					return "", err
+				}
+				depth--
+			}
+		case xml.EndElement:
+			depth--
+			if depth == 1 && inParagraph {
+				paragraphs = append(paragraphs, text.String())
+				inParagraph = false
+			}
+		case xml.CharData:
+			if inParagraph {
+				text.Write(typed)
+			}
+		}
+	}
+
+	return strings.Join(paragraphs, "\n"), nil
+}
+
+// cellValueText returns the text of a cell, preferring its typed value to the text it is displayed as.
+func cellValueText(decoder *xml.Decoder, cell xml.StartElement) (string, error) {
+	displayed, err := readParagraphs(decoder)
+	if err != nil {
+		return "", err
+	}
+
+	switch attribute(cell, "value-type") {
+	case "float", "percentage", "currency":
+		return attribute(cell, "value"), nil
+	case "date":
+		return attribute(cell, "date-value"), nil
+	case "time":
+		return odsTime(attribute(cell, "time-value")), nil
+	case "boolean":
+		return attribute(cell, "boolean-value"), nil
+	}
+	return displayed, nil
+}
+
+func (sheet *odsSheet) readRow(row xml.StartElement) (spreadsheetRow, error) {
+	cells := make(map[int]string)
+	column := 0
+
+	for {
+		token, err := sheet.decoder.Token()
+		if err != nil {
+			return spreadsheetRow{}, err
+		}
+
+		switch typed := token.(type) {
+		case xml.StartElement:
+			if typed.Name.Local != "table-cell" && typed.Name.Local != "covered-table-cell" {
+				if err := sheet.decoder.Skip(); err != nil {
+					return spreadsheetRow{}, err
+				}
+				continue
+			}
+			text, err := cellValueText(sheet.decoder, typed)
+			if err != nil {
+				return spreadsheetRow{}, err
+			}
+			repeats := repeatCount(typed, "number-columns-repeated")
+			// empty cells are often repeated to the edge of the sheet, so only cells with a value are kept
+			if text != "" {
+				for repeat := range repeats {
+					cells[column+repeat] = text
+				}
+			}
+			column += repeats
+		case xml.EndElement:
+			if typed.Name.Local == "table-row" {
+				return spreadsheetRow{cells: cells}, nil
+			}
+		}
+	}
+}
+
+func (sheet *odsSheet) nextRow() (spreadsheetRow, error) {
+	if sheet.repeats > 0 {
+		sheet.repeats--
+		sheet.rowNumber++
+		return spreadsheetRow{number: sheet.rowNumber, cells: sheet.repeatedRow.cells}, nil
+	}
+
+	for {
+		token, err := sheet.decoder.Token()
+		if err != nil {
+			return spreadsheetRow{}, err
+		}
+
+		switch typed := token.(type) {
+		case xml.StartElement:
+			if typed.Name.Local != "table-row" {
+				continue
+			}
+			row, err := sheet.readRow(typed)
+			if err != nil {
+				return spreadsheetRow{}, err
+			}
+			repeats := repeatCount(typed, "number-rows-repeated")
+			// empty rows are often repeated to the bottom of the sheet, and since empty rows are skipped, there's no need to repeat them
+			if row.isEmpty() {
+				sheet.rowNumber += repeats
+				continue
+			}
+			sheet.rowNumber++
+			row.number = sheet.rowNumber
+			sheet.repeatedRow = row
+			sheet.repeats = repeats - 1
+			return row, nil
+		case xml.EndElement:
+			if typed.Name.Local == "table" {
+				sheet.closer.Close()
+				return spreadsheetRow{}, io.EOF
+			}
+		}
+	}
+}
+
+// openODSSheet scans content.xml for the table selected by the dialect, leaving the decoder at its first row.
+func openODSSheet(archive *zip.Reader, dialect Dialect, limit int64) (func() (spreadsheetRow, error), error) {
+	member, err := openZipMember(archive, "content.xml", limit)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := xml.NewDecoder(member)
+	var sheetNames []string
+	sheetNumber := max(dialect.SheetNumber, 1)
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			member.Close()
+			return nil, err
+		}
+
+		table, ok := token.(xml.StartElement)
+		if !ok || table.Name.Local != "table" {
+			continue
+		}
+
+		name := attribute(table, "name")
+		sheetNames = append(sheetNames, name)
+		if dialect.SheetName != "" && dialect.SheetName == name || dialect.SheetName == "" && len(sheetNames) == sheetNumber {
+			sheet := &odsSheet{decoder: decoder, closer: member}
+			return sheet.nextRow, nil
+		}
+		if err := decoder.Skip(); err != nil {
+			member.Close()
+			return nil, err
+		}
+	}
+
+	member.Close()
+	return nil, missingSheetError(sheetNames, dialect)
+}
+
+// NewODSReader returns a Reader for a sheet of an OpenDocument spreadsheet, selected by dialect.SheetName or
+// dialect.SheetNumber. Cells are read as their typed values where they have one, so numbers aren't rounded
+// to how they are displayed, and dates and times are read in ISO 8601 format. Records have the A1 notation
+// of each cell in Refs. Since the spreadsheet is a zip archive, it has to be read from an io.ReaderAt of
+// known size, such as an *os.File.
+func NewODSReader(r io.ReaderAt, size int64, dialect Dialect) *Reader {
+	return newODSReader(r, size, dialect, DefaultMaxDecompressedSize)
+}
+
+func newODSReader(r io.ReaderAt, size int64, dialect Dialect, limit int64) *Reader {
+	return newSpreadsheetReader(r, size, dialect, func(archive *zip.Reader) (func() (spreadsheetRow, error), error) {
+		return openODSSheet(archive, dialect, limit)
+	})
+}
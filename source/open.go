@@ -0,0 +1,282 @@
+package source
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// Options control how Open and OpenReader find and read the table in a file.
+type Options struct {
+	Dialect Dialect
+	// Format is one of "csv", "tsv", "json", "ndjson", "xlsx" or "ods". If it is empty, it is decided by the
+	// file's extension, once any compression extensions (.gz, .bz2, .zip) have been removed, defaulting to csv.
+	Format string
+	// Member is the name of the member to read from a zip archive. It can be left empty if the archive has
+	// only one member.
+	Member string
+	// MaxDecompressedSize is the limit, in bytes, on the size of a compressed source (or archive member) once
+	// it has been decompressed. 0 means DefaultMaxDecompressedSize, and a negative number means no limit.
+	MaxDecompressedSize int64
+	// Encoding is the character encoding of a text source (csv, tsv, json or ndjson): one of "utf-8",
+	// "utf-16le", "utf-16be", "utf-16", "latin-1" or "windows-1252", or "auto" to detect it. Empty means
+	// utf-8. Spreadsheets declare their own encoding, so it doesn't apply to them.
+	Encoding string
+}
+
+// DefaultOptions returns Options which read a source in the DefaultDialect, deciding its format and
+// compression from its name.
+func DefaultOptions() Options {
+	return Options{Dialect: DefaultDialect()}
+}
+
+func (options Options) maxDecompressedSize() int64 {
+	if options.MaxDecompressedSize == 0 {
+		return DefaultMaxDecompressedSize
+	}
+	return options.MaxDecompressedSize
+}
+
+// Named is implemented by tables which know where they were read from, such as a File.
+type Named interface {
+	// Name is the name of the file the table was read from.
+	Name() string
+	// Member is the name of the archive member the table was read from, or "" if it wasn't in an archive.
+	Member() string
+}
+
+// A File is a table opened by Open or OpenReader. It may have been decompressed or extracted from an
+// archive on the way. Files must be closed once they have been read.
+type File struct {
+	*Reader
+	name     string
+	member   string
+	encoding string
+	closers  []io.Closer
+}
+
+func (file *File) Name() string {
+	return file.name
+}
+
+func (file *File) Member() string {
+	return file.member
+}
+
+// Encoding is the character encoding the file was decoded from, which is the one detected if
+// Options.Encoding was "auto". It is empty for spreadsheets.
+func (file *File) Encoding() string {
+	return file.encoding
+}
+
+func (file *File) Close() error {
+	var firstErr error
+	for index := len(file.closers) - 1; index >= 0; index-- {
+		if err := file.closers[index].Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// formatOf decides the format of a source from its name, e.g. data.tsv is tsv.
+func formatOf(name string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".tsv", ".tab":
+		return "tsv"
+	case ".json":
+		return "json"
+	case ".ndjson", ".jsonl":
+		return "ndjson"
+	case ".xlsx":
+		return "xlsx"
+	case ".ods":
+		return "ods"
+	}
+	return "csv"
+}
+
+// readAllLimited reads all of reader into memory, for formats which can't be streamed, such as zip archives.
+func readAllLimited(reader io.Reader, name string, limit int64) (*bytes.Reader, error) {
+	data, err := io.ReadAll(limitSize(reader, name, limit))
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+// opener holds the state of opening a single File, which may involve several layers of compression.
+type opener struct {
+	options Options
+	file    *File
+}
+
+// openTable creates the Reader for a table, once it has been decompressed.
+func (opener *opener) openTable(reader io.Reader, readerAt io.ReaderAt, size int64, name string) error {
+	dialect := opener.options.Dialect
+	format := opener.options.Format
+	if format == "" {
+		format = formatOf(name)
+	}
+
+	if (format == "xlsx" || format == "ods") && readerAt == nil {
+		buffered, err := readAllLimited(reader, name, opener.options.maxDecompressedSize())
+		if err != nil {
+			return err
+		}
+		readerAt, size = buffered, buffered.Size()
+	}
+
+	switch format {
+	case "csv", "tsv", "json", "ndjson":
+		decoded, encoding, err := NewDecoder(reader, opener.options.Encoding)
+		if err != nil {
+			return err
+		}
+		reader = decoded
+		opener.file.encoding = encoding
+	}
+
+	switch format {
+	case "csv":
+		opener.file.Reader = NewCSVReader(reader, dialect)
+	case "tsv":
+		dialect.Delimiter = "\t"
+		opener.file.Reader = NewCSVReader(reader, dialect)
+	case "json":
+		opener.file.Reader = NewJSONReader(reader, dialect)
+	case "ndjson":
+		opener.file.Reader = NewNDJSONReader(reader, dialect)
+	case "xlsx":
+		opener.file.Reader = newXLSXReader(readerAt, size, dialect, opener.options.maxDecompressedSize())
+	case "ods":
+		opener.file.Reader = newODSReader(readerAt, size, dialect, opener.options.maxDecompressedSize())
+	default:
+		return fmt.Errorf("unknown source format %q", format)
+	}
+	return nil
+}
+
+// openArchive opens the selected member of a zip archive.
+func (opener *opener) openArchive(readerAt io.ReaderAt, size int64, name string) error {
+	archive, err := zip.NewReader(readerAt, size)
+	if err != nil {
+		return fmt.Errorf("could not open %s as a zip archive: %w", name, err)
+	}
+
+	// an archive that doesn't have a .zip extension might be a spreadsheet
+	if opener.options.Format == "" && strings.ToLower(path.Ext(name)) != ".zip" {
+		if format := spreadsheetFormat(archive); format != "" {
+			opener.options.Format = format
+			return opener.openTable(nil, readerAt, size, name)
+		}
+	}
+
+	file, err := selectMember(archive, opener.options.Member)
+	if err != nil {
+		return fmt.Errorf("could not open %s: %w", name, err)
+	}
+	limit := opener.options.maxDecompressedSize()
+	if limit >= 0 && file.UncompressedSize64 > uint64(limit) {
+		return fmt.Errorf("%s in %s is larger than %d bytes: %w", file.Name, name, limit, ErrDecompressedSizeLimit)
+	}
+
+	member, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("could not open %s in %s: %w", file.Name, name, err)
+	}
+	opener.file.closers = append(opener.file.closers, member)
+	opener.file.member = file.Name
+	// archives inside archives aren't opened, but compressed members are
+	opener.options.Member = ""
+	return opener.openStream(limitSize(member, file.Name, limit), file.Name, false)
+}
+
+// openStream detects whether reader is compressed, decompressing it until it reaches the table.
+func (opener *opener) openStream(reader io.Reader, name string, allowArchive bool) error {
+	buffered := bufio.NewReader(reader)
+	magic, _ := buffered.Peek(4)
+	compression, innerName := detectCompression(name, magic)
+
+	switch compression {
+	case gzipCompression, bzip2Compression:
+		decompressed, err := decompress(buffered, compression)
+		if err != nil {
+			return err
+		}
+		return opener.openStream(limitSize(decompressed, name, opener.options.maxDecompressedSize()), innerName, allowArchive)
+	case zipArchive:
+		if !allowArchive {
+			return fmt.Errorf("%s is an archive inside an archive, which isn't supported", name)
+		}
+		archive, err := readAllLimited(buffered, name, opener.options.maxDecompressedSize())
+		if err != nil {
+			return err
+		}
+		return opener.openArchive(archive, archive.Size(), name)
+	}
+
+	return opener.openTable(buffered, nil, 0, name)
+}
+
+// OpenReader opens the table in reader. name is used to decide the source's format and compression, so
+// should have the extensions of the file the source came from, e.g. data.csv.gz. Compression is also
+// detected from the magic bytes at the start of the source, so reader can be stdin with a name of "-".
+func OpenReader(reader io.Reader, name string, options Options) (*File, error) {
+	opener := &opener{options: options, file: &File{name: name}}
+	if err := opener.openStream(reader, name, true); err != nil {
+		opener.file.Close()
+		return nil, err
+	}
+	return opener.file, nil
+}
+
+// Open opens the table in the file at path, as OpenReader does. Zip archives and spreadsheets are read
+// from the file directly, rather than into memory first.
+func Open(path string, options Options) (*File, error) {
+	osFile, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	magic := make([]byte, 4)
+	count, _ := osFile.ReadAt(magic, 0)
+	compression, _ := detectCompression(path, magic[:count])
+	format := options.Format
+	if format == "" {
+		format = formatOf(path)
+	}
+
+	if compression != zipArchive && format != "xlsx" && format != "ods" {
+		file, err := OpenReader(osFile, path, options)
+		if err != nil {
+			osFile.Close()
+			return nil, err
+		}
+		file.closers = append([]io.Closer{osFile}, file.closers...)
+		return file, nil
+	}
+
+	info, err := osFile.Stat()
+	if err != nil {
+		osFile.Close()
+		return nil, err
+	}
+
+	opener := &opener{options: options, file: &File{name: path, closers: []io.Closer{osFile}}}
+	if compression == zipArchive {
+		err = opener.openArchive(osFile, info.Size(), path)
+	} else {
+		err = opener.openTable(nil, osFile, info.Size(), path)
+	}
+	if err != nil {
+		opener.file.Close()
+		return nil, err
+	}
+	return opener.file, nil
+}
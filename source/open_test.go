@@ -0,0 +1,156 @@
+package source
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func gzipped(t *testing.T, content string) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+// bzipped is "id,name\n1,a\n2,b\n" compressed with bzip2, since Go's standard library can only decompress it
+var bzipped = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0xa0, 0x30, 0x7b, 0x7e, 0x00, 0x00, 0x06, 0xd9,
+	0x00, 0x00, 0x10, 0x00, 0x04, 0x30, 0x00, 0x36, 0x23, 0x20, 0x00, 0x31, 0x00, 0xd3, 0x4d, 0x04, 0x03, 0x10,
+	0x20, 0x41, 0x45, 0x97, 0x46, 0xf5, 0xed, 0xf8, 0xbb, 0x92, 0x29, 0xc2, 0x84, 0x85, 0x01, 0x83, 0xdb, 0xf0,
+}
+
+func readAllFrom(t *testing.T, file *File, err error) [][]string {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("Failed to open source with error %s", err.Error())
+	}
+	defer file.Close()
+
+	records, err := file.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read source with error %s", err.Error())
+	}
+	return records
+}
+
+func TestOpenReaderCompression(t *testing.T) {
+	expected := [][]string{{"id", "name"}, {"1", "a"}, {"2", "b"}}
+
+	// by extension
+	file, err := OpenReader(bytes.NewReader(gzipped(t, "id\tname\n1\ta\n2\tb\n")), "data.tsv.gz", DefaultOptions())
+	got := readAllFrom(t, file, err)
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	// by magic bytes
+	file, err = OpenReader(bytes.NewReader(bzipped), "-", DefaultOptions())
+	got = readAllFrom(t, file, err)
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	options := DefaultOptions()
+	options.MaxDecompressedSize = 10
+	file, err = OpenReader(bytes.NewReader(gzipped(t, strings.Repeat("a,b\n", 100))), "-", options)
+	if err == nil {
+		_, err = file.ReadAll()
+	}
+	if !errors.Is(err, ErrDecompressedSizeLimit) {
+		t.Errorf("Expected a decompressed size error, got %v", err)
+	}
+}
+
+func TestOpenArchive(t *testing.T) {
+	archive := zipFiles(t, map[string]string{
+		"__MACOSX/._people.ndjson": "metadata",
+		"exports/people.ndjson.gz": string(gzipped(t, "{\"id\": 1, \"name\": \"a\"}\n")),
+		"exports/readme.csv":       "a,b\n",
+	})
+	data := make([]byte, archive.Size())
+	archive.ReadAt(data, 0)
+
+	_, err := OpenReader(bytes.NewReader(data), "drop.zip", DefaultOptions())
+	if err == nil || !strings.Contains(err.Error(), "the archive has more than one member") {
+		t.Errorf("Expected an error asking for a member to be chosen, got %v", err)
+	}
+
+	options := DefaultOptions()
+	options.Member = "exports/people.ndjson.gz"
+
+	file, err := OpenReader(bytes.NewReader(data), "drop.zip", options)
+	got := readAllFrom(t, file, err)
+	if diff := cmp.Diff([][]string{{"id", "name"}, {"1", "a"}}, got); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+	if file.Name() != "drop.zip" || file.Member() != "exports/people.ndjson.gz" {
+		t.Errorf("Expected the file to be named drop.zip with member exports/people.ndjson.gz, got %s and %s", file.Name(), file.Member())
+	}
+
+	options.MaxDecompressedSize = 8
+	_, err = OpenReader(bytes.NewReader(data), "drop.zip", options)
+	if !errors.Is(err, ErrDecompressedSizeLimit) {
+		t.Errorf("Expected a decompressed size error, got %v", err)
+	}
+}
+
+func TestOpenSpreadsheetWithoutExtension(t *testing.T) {
+	fixture := xlsxFixture(t)
+	data := make([]byte, fixture.Size())
+	fixture.ReadAt(data, 0)
+
+	path := filepath.Join(t.TempDir(), "download")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	options := DefaultOptions()
+	options.Dialect.SheetNumber = 2
+
+	file, err := Open(path, options)
+	got := readAllFrom(t, file, err)
+	if len(got) != 3 || got[0][0] != "name" {
+		t.Errorf("Expected the workbook's Data sheet, got %v", got)
+	}
+}
+
+func TestSpreadsheetFormat(t *testing.T) {
+	testCases := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{name: "workbook", files: map[string]string{"[Content_Types].xml": "", "xl/workbook.xml": ""}, want: "xlsx"},
+		{name: "word document", files: map[string]string{"[Content_Types].xml": "", "word/document.xml": ""}},
+		{name: "presentation", files: map[string]string{"[Content_Types].xml": "", "ppt/presentation.xml": ""}},
+		{name: "spreadsheet", files: map[string]string{"mimetype": "application/vnd.oasis.opendocument.spreadsheet"}, want: "ods"},
+		{name: "text document", files: map[string]string{"mimetype": "application/vnd.oasis.opendocument.text"}},
+	}
+
+	for _, testCase := range testCases {
+		fixture := zipFiles(t, testCase.files)
+		archive, err := zip.NewReader(fixture, fixture.Size())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := spreadsheetFormat(archive); got != testCase.want {
+			t.Errorf("%s: expected %q, got %q", testCase.name, testCase.want, got)
+		}
+	}
+}
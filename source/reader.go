@@ -0,0 +1,233 @@
+package source
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+)
+
+// A Record is a single row of a table, as read from its source.
+type Record struct {
+	// Cells are the textual values of the row's cells.
+	Cells []string
+	// Values are the cells' native values, for formats that have types other than text, such as JSON.
+	// A native value is a string, a json.Number, a bool, nil, or for nested JSON, a []any or map[string]any.
+	// Values is nil for formats which are only text, such as CSV.
+	Values []any
+	// Keys name each cell, for rows which are keyed rather than positional, such as JSON objects. When it
+	// is set it takes precedence over the table's header.
+	Keys []string
+	// Refs locate each cell in the source, for formats where cells have an address, such as the A1
+	// notation of spreadsheets. Refs is nil for other formats.
+	Refs []string
+	// Row is the record's row number in the table, counting from 1 and including header and comment
+	// rows, as the dialect's HeaderRows and CommentRows do. It is set by Reader.
+	Row int
+	// Line is the physical line of the source that the record starts on, counting from 1, for formats
+	// made of lines. It can be further on than Row, since blank lines are skipped and quoted cells can
+	// span several lines. It is 0 if the reader doesn't know it.
+	Line int
+	// Offset is the byte offset of the start of the record in the source, once it has been decompressed
+	// and decoded to UTF-8. It is -1 if the reader doesn't know it.
+	Offset int64
+}
+
+// A Table is anything that can be read as a header followed by a series of records, such as a Reader.
+type Table interface {
+	// Header returns the names of the table's columns, or nil if it has no header.
+	Header() ([]string, error)
+	// Read returns the next data record, or io.EOF when there are no more.
+	Read() (Record, error)
+}
+
+// A Reader reads the records of a table, separating its header rows from its data rows and skipping its
+// comment rows as described by a Dialect. Readers are created by the functions for each format, e.g.
+// NewCSVReader, and implement Table.
+type Reader struct {
+	dialect Dialect
+	readRaw func() (Record, error)
+	// readKeys, if set, is called before the header rows are read. It returns the header of a source
+	// whose rows are keyed, such as a JSON array of objects, in which case the source has no header
+	// rows, or keyed=false if its rows turned out to be positional after all.
+	readKeys   func() (header []string, keyed bool, err error)
+	rowNumber  int
+	header     []string
+	headerRead bool
+	err        error
+}
+
+func newReader(dialect Dialect, readRaw func() (Record, error)) *Reader {
+	return &Reader{dialect: dialect, readRaw: readRaw}
+}
+
+// NewRecordsReader returns a Reader over records that have already been parsed, e.g. by a `*csv.Reader`
+// from Go's `encoding/csv` library, so that a Dialect's header and comment rows can be applied to them.
+// The Dialect's parsing properties, such as Delimiter, are ignored.
+func NewRecordsReader(records [][]string, dialect Dialect) *Reader {
+	index := 0
+	return newReader(dialect, func() (Record, error) {
+		if index >= len(records) {
+			return Record{}, io.EOF
+		}
+		index++
+		return Record{Cells: records[index-1], Offset: -1}, nil
+	})
+}
+
+// FromCSVReader returns a Reader of the records of a `*csv.Reader` from Go's `encoding/csv` library, with
+// header rows and comment rows as described by dialect. Unlike the csv.Reader's ReadAll, the Reader can
+// carry on past a malformed record, which Read reports as a *csv.ParseError. Records have the line they
+// start on, but not their offset.
+func FromCSVReader(reader *csv.Reader, dialect Dialect) *Reader {
+	return newReader(dialect, func() (Record, error) {
+		cells, err := reader.Read()
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return Record{Line: parseErr.StartLine, Offset: -1}, err
+		}
+		if err != nil {
+			return Record{}, err
+		}
+		if reader.ReuseRecord {
+			cells = slices.Clone(cells)
+		}
+		// a csv.Reader reports where records end, but not where they start
+		line, _ := reader.FieldPos(0)
+		return Record{Cells: cells, Line: line, Offset: -1}, nil
+	})
+}
+
+// nextRow reads the next row that isn't a comment row, along with its row number. A record that couldn't
+// be parsed still takes up a row, so it is returned with its row number and position as well as the error.
+func (reader *Reader) nextRow() (Record, int, error) {
+	for {
+		record, err := reader.readRaw()
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			reader.rowNumber++
+			record.Row = reader.rowNumber
+			return record, reader.rowNumber, err
+		}
+		if err != nil {
+			return Record{}, 0, err
+		}
+		reader.rowNumber++
+		record.Row = reader.rowNumber
+		if reader.rowNumber == 1 && len(record.Cells) > 0 && strings.HasPrefix(record.Cells[0], byteOrderMark) {
+			// a UTF-8 byte order mark left in by a reader that didn't remove it isn't part of the first cell
+			record.Cells = append([]string{strings.TrimPrefix(record.Cells[0], byteOrderMark)}, record.Cells[1:]...)
+		}
+		if !reader.dialect.isCommentRow(reader.rowNumber) {
+			return record, reader.rowNumber, nil
+		}
+	}
+}
+
+func (reader *Reader) readHeader() error {
+	if reader.headerRead {
+		return reader.err
+	}
+	reader.headerRead = true
+
+	if reader.readKeys != nil {
+		header, keyed, err := reader.readKeys()
+		if err != nil {
+			reader.err = err
+			return err
+		}
+		if keyed {
+			reader.header = header
+			return nil
+		}
+	}
+
+	var headerRows [][]string
+	for reader.rowNumber < reader.dialect.lastHeaderRow() {
+		record, rowNumber, err := reader.nextRow()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			reader.err = err
+			return err
+		}
+		if reader.dialect.isHeaderRow(rowNumber) {
+			headerRows = append(headerRows, record.Cells)
+		}
+	}
+
+	reader.header = joinHeaderRows(headerRows, reader.dialect.HeaderJoin)
+	return nil
+}
+
+// joinHeaderRows combines multiple header rows into one, joining the non-empty values of each column.
+func joinHeaderRows(headerRows [][]string, headerJoin string) []string {
+	if len(headerRows) == 0 {
+		return nil
+	}
+	if len(headerRows) == 1 {
+		return headerRows[0]
+	}
+
+	width := 0
+	for _, row := range headerRows {
+		width = max(width, len(row))
+	}
+
+	header := make([]string, width)
+	for column := range header {
+		var parts []string
+		for _, row := range headerRows {
+			if column < len(row) && row[column] != "" {
+				parts = append(parts, row[column])
+			}
+		}
+		header[column] = strings.Join(parts, headerJoin)
+	}
+	return header
+}
+
+// Header returns the table's header, joined from its header rows, or nil if the dialect says it has none.
+func (reader *Reader) Header() ([]string, error) {
+	if err := reader.readHeader(); err != nil {
+		return nil, err
+	}
+	return reader.header, nil
+}
+
+// Read returns the next data record, or io.EOF when there are no more. If the record can't be parsed, the
+// error is a *csv.ParseError and the Record has only the record's position, and reading can carry on.
+func (reader *Reader) Read() (Record, error) {
+	if err := reader.readHeader(); err != nil {
+		return Record{}, err
+	}
+	record, _, err := reader.nextRow()
+	return record, err
+}
+
+// ReadAll reads the rest of the table. If the table has a header it is returned as the first record,
+// so that a Reader can be used wherever a `*csv.Reader` is.
+func (reader *Reader) ReadAll() ([][]string, error) {
+	header, err := reader.Header()
+	if err != nil {
+		return nil, err
+	}
+
+	var records [][]string
+	if header != nil {
+		records = append(records, header)
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record.Cells)
+	}
+}
@@ -0,0 +1,147 @@
+package source
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// columnName returns the letters naming a 0-based column index in A1 notation, e.g. 27 is "AB".
+func columnName(column int) string {
+	name := ""
+	for column >= 0 {
+		name = string(rune('A'+column%26)) + name
+		column = column/26 - 1
+	}
+	return name
+}
+
+// cellReference returns the A1 notation of a 0-based column index and a 1-based row number.
+func cellReference(column int, row int) string {
+	return columnName(column) + strconv.Itoa(row)
+}
+
+// parseCellReference splits a cell reference in A1 notation into a 0-based column index and a 1-based row number.
+func parseCellReference(reference string) (int, int, error) {
+	column := 0
+	letters := 0
+	for letters < len(reference) && reference[letters] >= 'A' && reference[letters] <= 'Z' {
+		column = column*26 + int(reference[letters]-'A'+1)
+		letters++
+	}
+
+	row, err := strconv.Atoi(reference[letters:])
+	if letters == 0 || err != nil {
+		return 0, 0, fmt.Errorf("invalid cell reference %q", reference)
+	}
+	return column - 1, row, nil
+}
+
+// spreadsheetRow is a row of cells from a sheet, placed by their column index.
+type spreadsheetRow struct {
+	number int
+	cells  map[int]string
+}
+
+// record fills the gaps between a row's cells with empty cells, so that they line up with the header.
+func (row spreadsheetRow) record() Record {
+	width := 0
+	for column := range row.cells {
+		width = max(width, column+1)
+	}
+
+	record := Record{Cells: make([]string, width), Refs: make([]string, width), Offset: -1}
+	for column := range width {
+		record.Cells[column] = row.cells[column]
+		record.Refs[column] = cellReference(column, row.number)
+	}
+	return record
+}
+
+// isEmpty reports whether every cell in the row is blank. Empty rows are skipped, as blank lines are in CSVs.
+func (row spreadsheetRow) isEmpty() bool {
+	for _, cell := range row.cells {
+		if cell != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// selectSheet picks the sheet named by the dialect's SheetName, or positioned at its SheetNumber.
+func selectSheet(sheetNames []string, dialect Dialect) (int, error) {
+	for index, name := range sheetNames {
+		if dialect.SheetName != "" && dialect.SheetName == name || dialect.SheetName == "" && index+1 == max(dialect.SheetNumber, 1) {
+			return index, nil
+		}
+	}
+	return 0, missingSheetError(sheetNames, dialect)
+}
+
+func missingSheetError(sheetNames []string, dialect Dialect) error {
+	if dialect.SheetName != "" {
+		quoted := make([]string, len(sheetNames))
+		for index, name := range sheetNames {
+			quoted[index] = strconv.Quote(name)
+		}
+		return fmt.Errorf("there is no sheet named %q in the workbook, which has sheets %s", dialect.SheetName, strings.Join(quoted, ", "))
+	}
+	return fmt.Errorf("there is no sheet number %d in the workbook, which has %d sheets", max(dialect.SheetNumber, 1), len(sheetNames))
+}
+
+// limitedReadCloser is a member of a zip archive whose size is limited by limitSize.
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// openZipMember opens the file called name in archive. Reading more than limit bytes from it returns
+// ErrDecompressedSizeLimit, since a spreadsheet is as much a potential zip bomb as any other archive.
+func openZipMember(archive *zip.Reader, name string, limit int64) (io.ReadCloser, error) {
+	name = strings.TrimPrefix(name, "/")
+	for _, file := range archive.File {
+		if file.Name == name {
+			member, err := file.Open()
+			if err != nil {
+				return nil, err
+			}
+			return limitedReadCloser{Reader: limitSize(member, name, limit), Closer: member}, nil
+		}
+	}
+	return nil, fmt.Errorf("the spreadsheet is missing %s", name)
+}
+
+// newSpreadsheetReader returns a Reader which opens a spreadsheet on the first read. openSheet returns
+// a function that reads the next non-empty row of the selected sheet, or io.EOF.
+func newSpreadsheetReader(r io.ReaderAt, size int64, dialect Dialect, openSheet func(*zip.Reader) (func() (spreadsheetRow, error), error)) *Reader {
+	var nextRow func() (spreadsheetRow, error)
+	var openErr error
+	opened := false
+
+	return newReader(dialect, func() (Record, error) {
+		if !opened {
+			opened = true
+			archive, err := zip.NewReader(r, size)
+			if err != nil {
+				openErr = fmt.Errorf("could not open the spreadsheet: %w", err)
+			} else {
+				nextRow, openErr = openSheet(archive)
+			}
+		}
+		if openErr != nil {
+			return Record{}, openErr
+		}
+
+		for {
+			row, err := nextRow()
+			if err != nil {
+				return Record{}, err
+			}
+			if !row.isEmpty() {
+				return row.record(), nil
+			}
+		}
+	})
+}
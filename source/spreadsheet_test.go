@@ -0,0 +1,170 @@
+package source
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// zipFiles builds a zip archive in memory from a map of file names to contents.
+func zipFiles(t *testing.T, files map[string]string) *bytes.Reader {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for name, content := range files {
+		file, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := file.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buffer.Bytes())
+}
+
+func xlsxFixture(t *testing.T) *bytes.Reader {
+	return zipFiles(t, map[string]string{
+		"_rels/.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`,
+		"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+  <sheets>
+    <sheet name="Summary" sheetId="1" r:id="rId1"/>
+    <sheet name="Data" sheetId="2" r:id="rId2"/>
+  </sheets>
+</workbook>`,
+		"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+  <Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/sheet2.xml"/>
+  <Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings" Target="sharedStrings.xml"/>
+  <Relationship Id="rId4" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`,
+		"xl/sharedStrings.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+  <si><t>name</t></si>
+  <si><t>joined</t></si>
+  <si><r><t>Ada </t></r><r><t>Lovelace</t></r></si>
+</sst>`,
+		"xl/styles.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+  <numFmts count="1"><numFmt numFmtId="164" formatCode="dd/mm/yyyy\ hh:mm"/></numFmts>
+  <cellXfs count="3"><xf numFmtId="0"/><xf numFmtId="14"/><xf numFmtId="164"/></cellXfs>
+</styleSheet>`,
+		"xl/worksheets/sheet1.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData/></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+  <sheetData>
+    <row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="inlineStr"><is><t>active</t></is></c><c r="D1" t="inlineStr"><is><t>score</t></is></c></row>
+    <row r="3"><c r="A3" t="s"><v>2</v></c><c r="B3" s="1"><v>45292</v></c><c r="C3" t="b"><v>1</v></c><c r="D3"><v>1.5</v></c></row>
+    <row r="4"><c r="B4" s="2"><v>45292.5</v></c><c r="D4" t="e"><v>#DIV/0!</v></c></row>
+  </sheetData>
+</worksheet>`,
+	})
+}
+
+func TestXLSXReader(t *testing.T) {
+	fixture := xlsxFixture(t)
+
+	dialect := DefaultDialect()
+	dialect.SheetName = "Data"
+
+	header, records := readTable(t, NewXLSXReader(fixture, fixture.Size(), dialect))
+
+	if diff := cmp.Diff([]string{"name", "joined", "active", "score"}, header); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	expected := []Record{
+		{Cells: []string{"Ada Lovelace", "2024-01-01", "true", "1.5"}, Refs: []string{"A3", "B3", "C3", "D3"}, Row: 2, Offset: -1},
+		{Cells: []string{"", "2024-01-01T12:00:00", "", "#DIV/0!"}, Refs: []string{"A4", "B4", "C4", "D4"}, Row: 3, Offset: -1},
+	}
+	if diff := cmp.Diff(expected, records); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	dialect = DefaultDialect()
+	dialect.SheetName = "Missing"
+	_, err := NewXLSXReader(fixture, fixture.Size(), dialect).ReadAll()
+	if err == nil || err.Error() != `there is no sheet named "Missing" in the workbook, which has sheets "Summary", "Data"` {
+		t.Errorf("Expected a missing sheet error, got %v", err)
+	}
+}
+
+func TestODSReader(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0">
+  <office:body>
+    <office:spreadsheet>
+      <table:table table:name="Summary"><table:table-row><table:table-cell><text:p>ignored</text:p></table:table-cell></table:table-row></table:table>
+      <table:table table:name="Data">
+        <table:table-column table:number-columns-repeated="3"/>
+        <table:table-row>
+          <table:table-cell office:value-type="string"><text:p>name</text:p></table:table-cell>
+          <table:table-cell office:value-type="string"><text:p>joined</text:p></table:table-cell>
+          <table:table-cell office:value-type="string"><text:p>score</text:p></table:table-cell>
+        </table:table-row>
+        <table:table-row table:number-rows-repeated="2">
+          <table:table-cell office:value-type="string"><text:p>Ada<text:s text:c="2"/>L.</text:p><office:annotation><text:p>a note</text:p></office:annotation></table:table-cell>
+          <table:table-cell office:value-type="date" office:date-value="2024-01-01"><text:p>01/01/24</text:p></table:table-cell>
+          <table:table-cell office:value-type="float" office:value="0.333333333"><text:p>0.33</text:p></table:table-cell>
+          <table:table-cell table:number-columns-repeated="1020"/>
+        </table:table-row>
+        <table:table-row table:number-rows-repeated="1000"><table:table-cell table:number-columns-repeated="1024"/></table:table-row>
+        <table:table-row>
+          <table:table-cell table:number-columns-repeated="2"/>
+          <table:table-cell office:value-type="time" office:time-value="PT13H05M00S"><text:p>13:05</text:p></table:table-cell>
+        </table:table-row>
+      </table:table>
+    </office:spreadsheet>
+  </office:body>
+</office:document-content>`
+
+	fixture := zipFiles(t, map[string]string{"mimetype": "application/vnd.oasis.opendocument.spreadsheet", "content.xml": content})
+
+	dialect := DefaultDialect()
+	dialect.SheetNumber = 2
+
+	header, records := readTable(t, NewODSReader(fixture, fixture.Size(), dialect))
+
+	if diff := cmp.Diff([]string{"name", "joined", "score"}, header); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	expected := []Record{
+		{Cells: []string{"Ada  L.", "2024-01-01", "0.333333333"}, Refs: []string{"A2", "B2", "C2"}, Row: 2, Offset: -1},
+		{Cells: []string{"Ada  L.", "2024-01-01", "0.333333333"}, Refs: []string{"A3", "B3", "C3"}, Row: 3, Offset: -1},
+		{Cells: []string{"", "", "13:05:00"}, Refs: []string{"A1004", "B1004", "C1004"}, Row: 4, Offset: -1},
+	}
+	if diff := cmp.Diff(expected, records); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestCellReferences(t *testing.T) {
+	for column, name := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		reference := cellReference(column, 7)
+		if reference != name+"7" {
+			t.Errorf("Expected column %d to be %s7, got %s", column, name, reference)
+		}
+		parsedColumn, parsedRow, err := parseCellReference(reference)
+		if err != nil || parsedColumn != column || parsedRow != 7 {
+			t.Errorf("Expected %s to parse as column %d row 7, got column %d row %d (%v)", reference, column, parsedColumn, parsedRow, err)
+		}
+	}
+
+	if _, _, err := parseCellReference("7A"); err == nil || !strings.Contains(err.Error(), "invalid cell reference") {
+		t.Errorf("Expected an invalid cell reference error, got %v", err)
+	}
+}
@@ -0,0 +1,340 @@
+package source
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Type   string `xml:"Type,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxWorkbook struct {
+	Properties struct {
+		Date1904 string `xml:"date1904,attr"`
+	} `xml:"workbookPr"`
+	Sheets []struct {
+		Name           string `xml:"name,attr"`
+		RelationshipID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// xlsxText is rich text, made up of either a single piece of text or a series of runs.
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (text xlsxText) String() string {
+	var builder strings.Builder
+	builder.WriteString(text.Text)
+	for _, run := range text.Runs {
+		builder.WriteString(run.Text)
+	}
+	return builder.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxStyles struct {
+	NumberFormats []struct {
+		ID   int    `xml:"numFmtId,attr"`
+		Code string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	CellFormats []struct {
+		NumberFormatID int `xml:"numFmtId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+type xlsxCell struct {
+	Reference string    `xml:"r,attr"`
+	Type      string    `xml:"t,attr"`
+	Style     int       `xml:"s,attr"`
+	Value     string    `xml:"v"`
+	Inline    *xlsxText `xml:"is"`
+}
+
+type xlsxRow struct {
+	Number int        `xml:"r,attr"`
+	Cells  []xlsxCell `xml:"c"`
+}
+
+// xlsxSheet reads the rows of a worksheet, turning the values of its cells into text.
+type xlsxSheet struct {
+	decoder       *xml.Decoder
+	closer        io.Closer
+	sharedStrings []string
+	dateStyles    map[int]bool
+	date1904      bool
+	rowNumber     int
+}
+
+func decodeZipMember(archive *zip.Reader, name string, value any, limit int64) error {
+	member, err := openZipMember(archive, name, limit)
+	if err != nil {
+		return err
+	}
+	defer member.Close()
+	return xml.NewDecoder(member).Decode(value)
+}
+
+func hasZipMember(archive *zip.Reader, name string) bool {
+	for _, file := range archive.File {
+		if file.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// relationshipsPath returns where the relationships of the part at partPath are kept.
+func relationshipsPath(partPath string) string {
+	return path.Join(path.Dir(partPath), "_rels", path.Base(partPath)+".rels")
+}
+
+// resolveTarget resolves the target of a relationship from a part at partPath.
+func resolveTarget(partPath string, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join(path.Dir(partPath), target)
+}
+
+// isDateFormat reports whether a number format displays a number as a date or time. Formats 14 to 22
+// and 45 to 47 are the built-in date and time formats; custom formats are dates if they use date or
+// time codes outside of quoted text and [colour] sections.
+func isDateFormat(id int, code string) bool {
+	if id >= 14 && id <= 22 || id >= 45 && id <= 47 {
+		return true
+	}
+	if code == "" {
+		return false
+	}
+
+	inQuotes, inBrackets, escaped := false, false, false
+	for _, character := range code {
+		switch {
+		case escaped:
+			escaped = false
+		case character == '\\':
+			escaped = true
+		case character == '"':
+			inQuotes = !inQuotes
+		case inQuotes:
+		case character == '[':
+			inBrackets = true
+		case character == ']':
+			inBrackets = false
+		case inBrackets:
+		case strings.ContainsRune("dDmMyYhHsS", character):
+			return true
+		}
+	}
+	return false
+}
+
+// formatSerialDate turns a spreadsheet's serial date, the number of days since its epoch, into an ISO 8601
+// date, time or datetime, depending on whether it has a whole part, a fractional part, or both.
+func formatSerialDate(serial float64, date1904 bool) string {
+	epoch := time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+	if date1904 {
+		epoch = time.Date(1904, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	days := math.Floor(serial)
+	seconds := math.Round((serial - days) * 24 * 60 * 60)
+	moment := epoch.AddDate(0, 0, int(days)).Add(time.Duration(seconds) * time.Second)
+
+	switch {
+	case seconds == 0:
+		return moment.Format(time.DateOnly)
+	case days == 0:
+		return moment.Format(time.TimeOnly)
+	}
+	return moment.Format("2006-01-02T15:04:05")
+}
+
+func (sheet *xlsxSheet) cellText(cell xlsxCell) (string, error) {
+	switch cell.Type {
+	case "s":
+		index, err := strconv.Atoi(cell.Value)
+		if err != nil || index < 0 || index >= len(sheet.sharedStrings) {
+			return "", errors.New("cell " + cell.Reference + " refers to a shared string that doesn't exist")
+		}
+		return sheet.sharedStrings[index], nil
+	case "inlineStr":
+		if cell.Inline == nil {
+			return "", nil
+		}
+		return cell.Inline.String(), nil
+	case "b":
+		return strconv.FormatBool(cell.Value == "1"), nil
+	case "", "n":
+		if cell.Value != "" && sheet.dateStyles[cell.Style] {
+			if serial, err := strconv.ParseFloat(cell.Value, 64); err == nil {
+				return formatSerialDate(serial, sheet.date1904), nil
+			}
+		}
+	}
+	// formula strings (str), errors (e) and ISO dates (d) are already text
+	return cell.Value, nil
+}
+
+func (sheet *xlsxSheet) nextRow() (spreadsheetRow, error) {
+	for {
+		token, err := sheet.decoder.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				sheet.closer.Close()
+			}
+			return spreadsheetRow{}, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		var row xlsxRow
+		if err := sheet.decoder.DecodeElement(&row, &start); err != nil {
+			return spreadsheetRow{}, err
+		}
+
+		if row.Number == 0 {
+			row.Number = sheet.rowNumber + 1
+		}
+		sheet.rowNumber = row.Number
+
+		cells := make(map[int]string)
+		column := -1
+		for _, cell := range row.Cells {
+			if cell.Reference != "" {
+				if column, _, err = parseCellReference(cell.Reference); err != nil {
+					return spreadsheetRow{}, err
+				}
+			} else {
+				column++
+				cell.Reference = cellReference(column, row.Number)
+			}
+
+			text, err := sheet.cellText(cell)
+			if err != nil {
+				return spreadsheetRow{}, err
+			}
+			cells[column] = text
+		}
+
+		return spreadsheetRow{number: row.Number, cells: cells}, nil
+	}
+}
+
+// openXLSXSheet finds the workbook through the package's relationships, selects a sheet from it, and
+// loads the shared strings and styles needed to read the sheet's cells.
+func openXLSXSheet(archive *zip.Reader, dialect Dialect, limit int64) (func() (spreadsheetRow, error), error) {
+	workbookPath := "xl/workbook.xml"
+	var packageRelationships xlsxRelationships
+	if err := decodeZipMember(archive, "_rels/.rels", &packageRelationships, limit); err == nil {
+		for _, relationship := range packageRelationships.Relationships {
+			if strings.HasSuffix(relationship.Type, "/officeDocument") {
+				workbookPath = resolveTarget("", relationship.Target)
+			}
+		}
+	}
+
+	var workbook xlsxWorkbook
+	if err := decodeZipMember(archive, workbookPath, &workbook, limit); err != nil {
+		return nil, err
+	}
+
+	var relationships xlsxRelationships
+	if err := decodeZipMember(archive, relationshipsPath(workbookPath), &relationships, limit); err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string)
+	for _, relationship := range relationships.Relationships {
+		targets[relationship.ID] = resolveTarget(workbookPath, relationship.Target)
+		if strings.HasSuffix(relationship.Type, "/sharedStrings") {
+			targets["sharedStrings"] = targets[relationship.ID]
+		}
+		if strings.HasSuffix(relationship.Type, "/styles") {
+			targets["styles"] = targets[relationship.ID]
+		}
+	}
+
+	sheetNames := make([]string, len(workbook.Sheets))
+	for index, sheet := range workbook.Sheets {
+		sheetNames[index] = sheet.Name
+	}
+	sheetIndex, err := selectSheet(sheetNames, dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := &xlsxSheet{
+		date1904:   workbook.Properties.Date1904 == "1" || workbook.Properties.Date1904 == "true",
+		dateStyles: make(map[int]bool),
+	}
+
+	if sharedStringsPath, ok := targets["sharedStrings"]; ok && hasZipMember(archive, sharedStringsPath) {
+		var sharedStrings xlsxSharedStrings
+		if err := decodeZipMember(archive, sharedStringsPath, &sharedStrings, limit); err != nil {
+			return nil, err
+		}
+		for _, item := range sharedStrings.Items {
+			sheet.sharedStrings = append(sheet.sharedStrings, item.String())
+		}
+	}
+
+	if stylesPath, ok := targets["styles"]; ok && hasZipMember(archive, stylesPath) {
+		var styles xlsxStyles
+		if err := decodeZipMember(archive, stylesPath, &styles, limit); err != nil {
+			return nil, err
+		}
+		formatCodes := make(map[int]string)
+		for _, format := range styles.NumberFormats {
+			formatCodes[format.ID] = format.Code
+		}
+		for index, format := range styles.CellFormats {
+			sheet.dateStyles[index] = isDateFormat(format.NumberFormatID, formatCodes[format.NumberFormatID])
+		}
+	}
+
+	member, err := openZipMember(archive, targets[workbook.Sheets[sheetIndex].RelationshipID], limit)
+	if err != nil {
+		return nil, err
+	}
+	sheet.decoder = xml.NewDecoder(member)
+	sheet.closer = member
+
+	return sheet.nextRow, nil
+}
+
+// NewXLSXReader returns a Reader for a sheet of an Excel workbook, selected by dialect.SheetName or
+// dialect.SheetNumber. Cells are read as the text they would be exported to a CSV as, except that numbers
+// formatted as dates are converted to ISO 8601 dates and times. Records have the A1 notation of each
+// cell in Refs. Since the workbook is a zip archive, it has to be read from an io.ReaderAt of known size,
+// such as an *os.File.
+func NewXLSXReader(r io.ReaderAt, size int64, dialect Dialect) *Reader {
+	return newXLSXReader(r, size, dialect, DefaultMaxDecompressedSize)
+}
+
+func newXLSXReader(r io.ReaderAt, size int64, dialect Dialect, limit int64) *Reader {
+	return newSpreadsheetReader(r, size, dialect, func(archive *zip.Reader) (func() (spreadsheetRow, error), error) {
+		return openXLSXSheet(archive, dialect, limit)
+	})
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"tableschema-validator/source"
+)
+
+// sourceFlags are the flags of the commands that read data files, describing how to read them.
+type sourceFlags struct {
+	dialectPath *string
+	format      *string
+	encoding    *string
+	member      *string
+}
+
+func addSourceFlags(flags *flag.FlagSet) sourceFlags {
+	return sourceFlags{
+		dialectPath: flags.String("dialect", "", "path to a table dialect descriptor describing how the data is laid out"),
+		format:      flags.String("format", "", `format of the data, e.g. "csv" or "xlsx", if it can't be told from the file names`),
+		encoding:    flags.String("encoding", "", `character encoding of the data, or "auto" to detect it (default utf-8)`),
+		member:      flags.String("member", "", "member of a zip archive to read"),
+	}
+}
+
+// options returns the source.Options the flags describe.
+func (flags sourceFlags) options() (source.Options, error) {
+	options := source.DefaultOptions()
+	options.Format = *flags.format
+	options.Encoding = *flags.encoding
+	options.Member = *flags.member
+	if *flags.dialectPath != "" {
+		data, err := os.ReadFile(*flags.dialectPath)
+		if err == nil {
+			err = json.Unmarshal(data, &options.Dialect)
+		}
+		if err != nil {
+			return source.Options{}, fmt.Errorf("could not read dialect: %w", err)
+		}
+	}
+	return options, nil
+}
+
+// openSource opens the data file at path, or stdin if path is -. name is what the file should be called
+// in messages.
+func openSource(path string, stdin io.Reader, options source.Options) (file *source.File, name string, err error) {
+	if path == "-" {
+		file, err = source.OpenReader(stdin, "-", options)
+		return file, "stdin", err
+	}
+	file, err = source.Open(path, options)
+	return file, path, err
+}
@@ -178,29 +178,29 @@ func TestUniqueConstraint(t *testing.T) {
 	constraint := schema.UniqueContraint{Selected: true, Value: true}
 	header := "foo"
 	actual := []RowValidationResult{
-		{Original: []string{"bar"}, Parsed: map[string]string{"foo": "bar"}, IsValid: true},
-		{Original: []string{"baz"}, Parsed: map[string]string{"foo": "baz"}, IsValid: true},
-		{Original: []string{"bar"}, Parsed: map[string]string{"foo": "bar"}, IsValid: true},
+		{Original: []string{"bar"}, Parsed: map[string]string{"foo": "bar"}, IsValid: true, RowNumber: 2},
+		{Original: []string{"baz"}, Parsed: map[string]string{"foo": "baz"}, IsValid: true, RowNumber: 3},
+		{Original: []string{"bar"}, Parsed: map[string]string{"foo": "bar"}, IsValid: true, RowNumber: 4},
 	}
 
 	EnforceUniqueConstraint(constraint, header, &actual)
 
 	expected := []RowValidationResult{
-		{Original: []string{"bar"}, Parsed: map[string]string{"foo": "bar"}, IsValid: false, Failures: []CellValidationResult{
+		{Original: []string{"bar"}, Parsed: map[string]string{"foo": "bar"}, IsValid: false, RowNumber: 2, Failures: []CellValidationResult{
 			{
 				header:     "foo",
 				value:      "bar",
 				constraint: "unique",
-				reason:     "foo was marked as unique but its value bar was found on rows 0, 2 (this row: 0)",
+				reason:     "foo was marked as unique but its value bar was found on rows 2, 4",
 			},
 		}},
-		{Original: []string{"baz"}, Parsed: map[string]string{"foo": "baz"}, IsValid: true},
-		{Original: []string{"bar"}, Parsed: map[string]string{"foo": "bar"}, IsValid: false, Failures: []CellValidationResult{
+		{Original: []string{"baz"}, Parsed: map[string]string{"foo": "baz"}, IsValid: true, RowNumber: 3},
+		{Original: []string{"bar"}, Parsed: map[string]string{"foo": "bar"}, IsValid: false, RowNumber: 4, Failures: []CellValidationResult{
 			{
 				header:     "foo",
 				value:      "bar",
 				constraint: "unique",
-				reason:     "foo was marked as unique but its value bar was found on rows 0, 2 (this row: 2)",
+				reason:     "foo was marked as unique but its value bar was found on rows 2, 4",
 			},
 		},
 		},
@@ -210,3 +210,77 @@ func TestUniqueConstraint(t *testing.T) {
 		t.Errorf("(-want +got):\n%s", diff)
 	}
 }
+
+func TestBooleanConstraint(t *testing.T) {
+	for _, field := range []string{"true", "True", "TRUE", "1", "false", "False", "FALSE", "0"} {
+		validationResult, err := EnforceBooleanConstraint("foo", field)
+		if err != nil {
+			t.Errorf("Error enforcing boolean constraint")
+		}
+		expectedValidationResult := CellValidationResult{constraint: "Boolean", isValid: true}
+		if diff := cmp.Diff(expectedValidationResult, validationResult, cmp.AllowUnexported(CellValidationResult{})); diff != "" {
+			t.Errorf("(-want +got):\n%s", diff)
+		}
+	}
+
+	validationResult, err := EnforceBooleanConstraint("foo", "yes")
+	if err != nil {
+		t.Errorf("Error enforcing boolean constraint")
+	}
+	expectedValidationResult := CellValidationResult{constraint: "Boolean", isValid: false, header: "foo", value: "yes", reason: "foo was marked as a boolean, but its value yes could not be parsed as a boolean"}
+	if diff := cmp.Diff(expectedValidationResult, validationResult, cmp.AllowUnexported(CellValidationResult{})); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestTemporalAndIntegerConstraints(t *testing.T) {
+	testCases := []struct {
+		name     string
+		enforce  func(header string, field string) (CellValidationResult, error)
+		valid    []string
+		invalid  string
+		expected CellValidationResult
+	}{
+		{
+			name:     "integer",
+			enforce:  EnforceIntegerConstraint,
+			valid:    []string{"0", "-46", "+8", "1000000000000000000000"},
+			invalid:  "1.0",
+			expected: CellValidationResult{constraint: "Integer", isValid: false, header: "foo", value: "1.0", reason: "foo was marked as an integer, but its value 1.0 could not be parsed as an integer"},
+		},
+		{
+			name:     "date",
+			enforce:  EnforceDateConstraint,
+			valid:    []string{"2024-01-31", "1999-12-01"},
+			invalid:  "2024-02-30",
+			expected: CellValidationResult{constraint: "Date", isValid: false, header: "foo", value: "2024-02-30", reason: "foo was marked as a date, but its value 2024-02-30 could not be parsed as a date"},
+		},
+		{
+			name:     "datetime",
+			enforce:  EnforceDateTimeConstraint,
+			valid:    []string{"2024-01-31T09:30:00Z", "2024-01-31T09:30:00+01:00", "2024-01-31T09:30:00.25"},
+			invalid:  "2024-01-31 09:30",
+			expected: CellValidationResult{constraint: "Datetime", isValid: false, header: "foo", value: "2024-01-31 09:30", reason: "foo was marked as a datetime, but its value 2024-01-31 09:30 could not be parsed as a datetime"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		for _, field := range testCase.valid {
+			validationResult, err := testCase.enforce("foo", field)
+			if err != nil {
+				t.Errorf("%s: error enforcing constraint", testCase.name)
+			}
+			if !validationResult.isValid {
+				t.Errorf("%s: expected %s to be valid, got %s", testCase.name, field, validationResult.reason)
+			}
+		}
+
+		validationResult, err := testCase.enforce("foo", testCase.invalid)
+		if err != nil {
+			t.Errorf("%s: error enforcing constraint", testCase.name)
+		}
+		if diff := cmp.Diff(testCase.expected, validationResult, cmp.AllowUnexported(CellValidationResult{})); diff != "" {
+			t.Errorf("%s (-want +got):\n%s", testCase.name, diff)
+		}
+	}
+}
@@ -1,12 +1,14 @@
 package validate
 
 import (
+	"encoding/json"
+	"fmt"
 	"regexp"
 	"slices"
-	"strconv"
 	"strings"
 	"tableschema-validator/schema"
 	"tableschema-validator/util"
+	"time"
 )
 
 // EnforceStringConstraint reports whether a cell can be interpreted as a string.
@@ -43,6 +45,99 @@ func EnforceNumberConstraint(header string, field string) (CellValidationResult,
 
 }
 
+// EnforceBooleanConstraint reports whether a cell can be interpreted as a boolean, using the default
+// trueValues and falseValues defined [here](https://datapackage.org/standard/table-schema/#boolean), which are
+// the values schema.IsBoolean accepts.
+func EnforceBooleanConstraint(header string, field string) (CellValidationResult, error) {
+	if schema.IsBoolean(field) {
+		return CellValidationResult{constraint: "Boolean", isValid: true}, nil
+	}
+
+	return CellValidationResult{constraint: "Boolean", isValid: false, header: header, value: field, reason: header + " was marked as a boolean, but its value " + field + " could not be parsed as a boolean"}, nil
+}
+
+// EnforceIntegerConstraint reports whether a cell can be interpreted as an integer,
+// defined [here](https://datapackage.org/standard/table-schema/#integer): an optional
+// sign followed by digits.
+func EnforceIntegerConstraint(header string, field string) (CellValidationResult, error) {
+	isMatch, err := regexp.MatchString(`^[+-]?\d+$`, field)
+	if err != nil {
+		return CellValidationResult{}, err
+	}
+
+	if isMatch {
+		return CellValidationResult{constraint: "Integer", isValid: true}, nil
+	}
+
+	return CellValidationResult{constraint: "Integer", isValid: false, header: header, value: field, reason: header + " was marked as an integer, but its value " + field + " could not be parsed as an integer"}, nil
+}
+
+// dateTimeLayouts are the forms of ISO 8601 datetime accepted in a datetime field, with and without a timezone.
+var dateTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"}
+
+// EnforceDateConstraint reports whether a cell can be interpreted as a date in the
+// default format defined [here](https://datapackage.org/standard/table-schema/#date), YYYY-MM-DD.
+func EnforceDateConstraint(header string, field string) (CellValidationResult, error) {
+	if _, err := time.Parse(time.DateOnly, field); err == nil {
+		return CellValidationResult{constraint: "Date", isValid: true}, nil
+	}
+
+	return CellValidationResult{constraint: "Date", isValid: false, header: header, value: field, reason: header + " was marked as a date, but its value " + field + " could not be parsed as a date"}, nil
+}
+
+// EnforceDateTimeConstraint reports whether a cell can be interpreted as a datetime in the
+// default format defined [here](https://datapackage.org/standard/table-schema/#datetime),
+// e.g. 2024-01-31T09:30:00Z. A datetime without a timezone is also accepted.
+func EnforceDateTimeConstraint(header string, field string) (CellValidationResult, error) {
+	for _, layout := range dateTimeLayouts {
+		if _, err := time.Parse(layout, field); err == nil {
+			return CellValidationResult{constraint: "Datetime", isValid: true}, nil
+		}
+	}
+
+	return CellValidationResult{constraint: "Datetime", isValid: false, header: header, value: field, reason: header + " was marked as a datetime, but its value " + field + " could not be parsed as a datetime"}, nil
+}
+
+// EnforceNativeTypeConstraint reports whether a natively typed cell, such as a number or boolean from a JSON
+// source, has the type its field was marked as. A native number is always a valid number, even where its
+// textual form (e.g. 1e5) would not be, and a native boolean is always a valid boolean. Native strings and
+// nulls are not checked here, since they should be checked in their textual form, e.g. with
+// EnforceNumberConstraint.
+func EnforceNativeTypeConstraint(fieldType string, header string, field string, value any) (CellValidationResult, error) {
+	constraint := strings.ToUpper(fieldType[:1]) + fieldType[1:]
+	validResponse := CellValidationResult{constraint: constraint, isValid: true}
+
+	var nativeType string
+	switch value.(type) {
+	case nil, string:
+		return validResponse, nil
+	case json.Number:
+		nativeType = "number"
+	case bool:
+		nativeType = "boolean"
+	case []any:
+		nativeType = "array"
+	case map[string]any:
+		nativeType = "object"
+	default:
+		return CellValidationResult{}, fmt.Errorf("%s has a native value of unsupported type %T", header, value)
+	}
+
+	if nativeType == fieldType {
+		return validResponse, nil
+	}
+	// a JSON number is an integer if it is written as one
+	if nativeType == "number" && fieldType == "integer" {
+		return EnforceIntegerConstraint(header, field)
+	}
+
+	article := "a "
+	if strings.ContainsRune("aeiou", rune(fieldType[0])) {
+		article = "an "
+	}
+	return CellValidationResult{constraint: constraint, isValid: false, header: header, value: field, reason: header + " was marked as " + article + fieldType + ", but its value " + field + " is a JSON " + nativeType}, nil
+}
+
 // EnforceRequiredConstraint reports whether a cell is both required and absent.
 // if the cell is both required and absent, EnforceRequiredConstraint marks the cell
 // as invalid; if the cell is either not required, or has a value, it is reported as
@@ -69,6 +164,10 @@ func EnforceUniqueConstraint(uniqueConstraint schema.Constraint[bool], header st
 	uniqueValueIndices := make(map[string][]int)
 
 	for index, row := range *validatedRows {
+		// rows that couldn't be parsed from the source have no values to compare
+		if row.Parsed == nil {
+			continue
+		}
 		value := row.Parsed[header]
 		uniqueValueIndices[value] = append(uniqueValueIndices[value], index)
 	}
@@ -80,10 +179,16 @@ func EnforceUniqueConstraint(uniqueConstraint schema.Constraint[bool], header st
 			continue
 		}
 
+		// rows are referred to by their number in the source, which is what a user sees, rather than their index
+		rowNumbers := make([]int, len(sourceIndices))
+		for index, sourceIndex := range sourceIndices {
+			rowNumbers[index] = (*validatedRows)[sourceIndex].RowNumber
+		}
+
 		for _, sourceIndexOfDuplicate := range sourceIndices {
 			newRow := (*validatedRows)[sourceIndexOfDuplicate]
 
-			reason := header + " was marked as unique but its value " + sourceValue + " was found on rows " + util.CommaSeparatedList(sourceIndices) + " (this row: " + strconv.Itoa(sourceIndexOfDuplicate) + ")"
+			reason := header + " was marked as unique but its value " + sourceValue + " was found on rows " + util.CommaSeparatedList(rowNumbers)
 
 			failure := CellValidationResult{constraint: "unique", isValid: false, header: header, value: sourceValue, reason: reason}
 
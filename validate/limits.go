@@ -0,0 +1,113 @@
+package validate
+
+import "strconv"
+
+// ValidationOptions controls how much work ValidateWithOptions does once it knows the source is invalid.
+// The zero value places no limits: every row is examined and every failure is recorded, which is
+// what Validate does.
+type ValidationOptions struct {
+	// MaxErrors is the maximum number of failures recorded across the whole source. Once it is reached
+	// no further rows are examined. 0 means no limit.
+	MaxErrors int
+	// MaxErrorsPerColumn is the maximum number of failures recorded for any one column. Failures beyond
+	// it are dropped, but rows continue to be examined. 0 means no limit.
+	MaxErrorsPerColumn int
+	// FailFast stops validation at the first row with a failure.
+	FailFast bool
+	// TolerateSourceErrors reports records that can't be parsed, such as a line of a CSV with a stray
+	// quote, as a row with a single "source-error" failure and carries on with the rest of the source,
+	// rather than giving up on the whole source with an error. It applies to source.Tables that report
+	// malformed records as a *csv.ParseError, and to a *csv.Reader.
+	TolerateSourceErrors bool
+}
+
+// A ValidationReport is the 'verdict' on a whole source. Rows holds a RowValidationResult for every row
+// that was examined, which may be fewer than the rows in the source if validation was truncated by
+// one of the limits in ValidationOptions.
+type ValidationReport struct {
+	// Source and Member name the file, and the member of the archive within it, that the rows were read
+	// from, if the table knows (see source.Named). Member is empty if the source wasn't an archive.
+	Source string
+	Member string
+	Rows   []RowValidationResult
+	// RowsExamined is the number of data rows (not counting the header) that were validated.
+	RowsExamined int
+	// Truncated is true if rows were left unexamined or failures were dropped because of a limit.
+	// When it is true, a row can be invalid without every one of its failures being listed.
+	Truncated bool
+	// TruncationReason describes the first limit that was hit, e.g. "reached the maximum of 100 errors".
+	TruncationReason string
+}
+
+// IsValid reports whether every examined row was valid. A truncated report is never valid, because
+// truncation only happens once a failure has been found.
+func (report ValidationReport) IsValid() bool {
+	if report.Truncated {
+		return false
+	}
+	for _, row := range report.Rows {
+		if !row.IsValid {
+			return false
+		}
+	}
+	return true
+}
+
+// errorLimiter keeps count of the failures recorded so far and decides whether the next one fits
+// within the limits of a ValidationOptions.
+type errorLimiter struct {
+	options   ValidationOptions
+	total     int
+	perColumn map[string]int
+	truncated bool
+	reason    string
+}
+
+func newErrorLimiter(options ValidationOptions) *errorLimiter {
+	return &errorLimiter{options: options, perColumn: make(map[string]int)}
+}
+
+func (limiter *errorLimiter) truncate(reason string) {
+	if !limiter.truncated {
+		limiter.truncated = true
+		limiter.reason = reason
+	}
+}
+
+// exhausted reports whether the total error limit has been reached, meaning no more rows should be examined.
+func (limiter *errorLimiter) exhausted() bool {
+	return limiter.options.MaxErrors > 0 && limiter.total >= limiter.options.MaxErrors
+}
+
+// admit reports whether failure should be recorded, counting it if so.
+func (limiter *errorLimiter) admit(failure CellValidationResult) bool {
+	if limiter.exhausted() {
+		limiter.truncate("reached the maximum of " + strconv.Itoa(limiter.options.MaxErrors) + " errors")
+		return false
+	}
+
+	maxPerColumn := limiter.options.MaxErrorsPerColumn
+	if maxPerColumn > 0 && limiter.perColumn[failure.header] >= maxPerColumn {
+		limiter.truncate("reached the maximum of " + strconv.Itoa(maxPerColumn) + " errors for column " + failure.header)
+		return false
+	}
+
+	limiter.total++
+	limiter.perColumn[failure.header]++
+	return true
+}
+
+// limitFailures drops the failures of row from index `from` onwards that don't fit within the limits.
+// The row stays invalid even if all of its failures are dropped.
+func (limiter *errorLimiter) limitFailures(row *RowValidationResult, from int) {
+	kept := row.Failures[:from]
+	for _, failure := range row.Failures[from:] {
+		if limiter.admit(failure) {
+			kept = append(kept, failure)
+		}
+	}
+	if len(kept) == 0 {
+		kept = nil
+	}
+	row.Failures = kept
+}
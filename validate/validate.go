@@ -5,7 +5,12 @@
 package validate
 
 import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
 	"tableschema-validator/schema"
+	"tableschema-validator/source"
 )
 
 type Readable interface {
@@ -20,10 +25,44 @@ type CellValidationResult struct {
 	constraint string
 	reason     string
 	isValid    bool
+	// cell is the address of the cell in the source, e.g. B7 in a spreadsheet, if the source has addresses
+	cell string
+}
+
+// Header is the name of the column of the cell the result is for. It is empty for results about a whole row,
+// such as a "source-error".
+func (result CellValidationResult) Header() string {
+	return result.header
+}
+
+// Value is the value of the cell the result is for.
+func (result CellValidationResult) Value() string {
+	return result.value
+}
+
+// Constraint is the name of the constraint the cell was checked against, e.g. "required" or "Number".
+func (result CellValidationResult) Constraint() string {
+	return result.constraint
+}
+
+// Reason describes why the cell failed the constraint, and where it is in the source.
+func (result CellValidationResult) Reason() string {
+	return result.reason
+}
+
+// IsValid reports whether the cell passed the constraint.
+func (result CellValidationResult) IsValid() bool {
+	return result.isValid
+}
+
+// Cell is the address of the cell in the source, e.g. B7, if the source has addresses.
+func (result CellValidationResult) Cell() string {
+	return result.cell
 }
 
 // A RowValidationResult is the 'verdict' on a single row. It includes the Validate package's internal representation of the row,
-// as well as an `isValid` result which is false iff there is at least one item in the Failures slice. While a `CellValidationResult`
+// as well as an `isValid` result which is false iff there is at least one item in the Failures slice (or, in a truncated
+// ValidationReport, would have been, had the failures not been dropped by a limit). While a `CellValidationResult`
 // may be produced for a valid or an invalid row, it will only exist in a `RowValidationResult` to indicate invalid data - if
 // `CellValidationResult.isValid` is false.
 type RowValidationResult struct {
@@ -31,21 +70,83 @@ type RowValidationResult struct {
 	Parsed   map[string]string
 	IsValid  bool
 	Failures []CellValidationResult
+	// RowNumber is the row's number in the source, counting from 1 and including header rows, so the first data row
+	// of a source with one header row is row 2. Line and Offset are where the row starts in the source, if the source
+	// knows (see source.Record): Line is 0 and Offset is -1 if it doesn't.
+	RowNumber int
+	Line      int
+	Offset    int64
 }
 
 func mapRowCellsToHeaders(headers []string, rawRow []string) map[string]string {
+	if rawRow == nil {
+		return nil
+	}
+
 	row := make(map[string]string)
 
 	for headerIndex, header := range headers {
-		row[header] = rawRow[headerIndex]
+		// rows with fewer cells than there are headers are treated as having empty cells at the end
+		if headerIndex < len(rawRow) {
+			row[header] = rawRow[headerIndex]
+		} else {
+			row[header] = ""
+		}
+	}
+
+	return row
+}
+
+func mapRowValuesToHeaders(headers []string, values []any) map[string]any {
+	if values == nil {
+		return nil
+	}
+
+	row := make(map[string]any)
+	for headerIndex, header := range headers {
+		if headerIndex < len(values) {
+			row[header] = values[headerIndex]
+		}
 	}
 
 	return row
 }
 
+// locateFailures adds where the failure is in the source to the reasons of the failures of row from index `from`
+// onwards: its row number, and either the address of the failing cell, if the source has addresses (e.g. a
+// spreadsheet), or the line the row starts on, if the source has lines.
+func locateFailures(row *RowValidationResult, cellRefs map[string]string, from int) {
+	for index := from; index < len(row.Failures); index++ {
+		failure := &row.Failures[index]
+		location := "row " + strconv.Itoa(row.RowNumber)
+		if cell := cellRefs[failure.header]; cell != "" {
+			failure.cell = cell
+			location += ", cell " + cell
+		} else if row.Line != 0 {
+			location += ", line " + strconv.Itoa(row.Line)
+		}
+		failure.reason += " (" + location + ")"
+	}
+}
+
+// sourceErrorResult is the result for a record that couldn't be parsed, which is reported as a single
+// "source-error" failure. If the record spans several lines, the reason gives the line the error was found on.
+func sourceErrorResult(record source.Record, parseErr *csv.ParseError) RowValidationResult {
+	reason := "the record could not be parsed: " + parseErr.Err.Error()
+	if parseErr.StartLine != 0 && parseErr.StartLine != parseErr.Line {
+		reason += " on line " + strconv.Itoa(parseErr.Line)
+	}
+	failure := CellValidationResult{constraint: "source-error", reason: reason, isValid: false}
+	return RowValidationResult{IsValid: false, Failures: []CellValidationResult{failure}, RowNumber: record.Row, Line: record.Line, Offset: record.Offset}
+}
+
+// requiredConstraint is schema.RequiredConstraint, named here because validateRow's schema parameter hides the package
+type requiredConstraint = schema.RequiredConstraint
+
 // validateRow is used for standard validations. It takes the raw row string, a map of input header to csv values, and then a row
-// it then applies all validations to the row that aren't relational, i.e. don't depend on other rows.
-func validateRow(rawRow []string, row map[string]string, schema schema.Schema) (RowValidationResult, error) {
+// it then applies all validations to the row that aren't relational, i.e. don't depend on other rows. If the source has native
+// values (see source.Record) they are passed in nativeRow, and are used for type checking in place of the csv values.
+func validateRow(rawRow []string, row map[string]string, nativeRow map[string]any, schema schema.Schema) (RowValidationResult, error) {
 	isValid := true
 	var validationFailures []CellValidationResult
 
@@ -56,8 +157,19 @@ func validateRow(rawRow []string, row map[string]string, schema schema.Schema) (
 		}
 	}
 
+	// enforceType applies enforceTextualType unless the cell has a native value which isn't a string or null
+	enforceType := func(fieldType string, header string, enforceTextualType func() (CellValidationResult, error)) (CellValidationResult, error) {
+		switch nativeRow[header].(type) {
+		case nil, string:
+			return enforceTextualType()
+		}
+		return EnforceNativeTypeConstraint(fieldType, header, row[header], nativeRow[header])
+	}
+
 	for _, stringField := range schema.Fields.StringFields {
-		dataTypeValidationFailure, err := EnforceStringConstraint()
+		dataTypeValidationFailure, err := enforceType("string", stringField.Name, func() (CellValidationResult, error) {
+			return EnforceStringConstraint()
+		})
 		if err != nil {
 			return RowValidationResult{}, err
 		}
@@ -70,7 +182,9 @@ func validateRow(rawRow []string, row map[string]string, schema schema.Schema) (
 	}
 
 	for _, numberField := range schema.Fields.NumberFields {
-		dataTypeValidationFailure, err := EnforceNumberConstraint(numberField.Name, row[numberField.Name])
+		dataTypeValidationFailure, err := enforceType("number", numberField.Name, func() (CellValidationResult, error) {
+			return EnforceNumberConstraint(numberField.Name, row[numberField.Name])
+		})
 		if err != nil {
 			return RowValidationResult{}, err
 		}
@@ -83,6 +197,62 @@ func validateRow(rawRow []string, row map[string]string, schema schema.Schema) (
 		handleValidationResult(requiredValidationFailure)
 	}
 
+	for _, booleanField := range schema.Fields.BooleanFields {
+		// an empty cell is a missing value rather than an invalid boolean; whether it's allowed is up to the required constraint
+		if row[booleanField.Name] != "" {
+			dataTypeValidationFailure, err := enforceType("boolean", booleanField.Name, func() (CellValidationResult, error) {
+				return EnforceBooleanConstraint(booleanField.Name, row[booleanField.Name])
+			})
+			if err != nil {
+				return RowValidationResult{}, err
+			}
+			handleValidationResult(dataTypeValidationFailure)
+		}
+		requiredValidationFailure, err := EnforceRequiredConstraint(booleanField.Constraints.Required, booleanField.Name, row[booleanField.Name])
+		if err != nil {
+			return RowValidationResult{}, err
+		}
+		handleValidationResult(requiredValidationFailure)
+	}
+
+	// enforceOptionalType is used for the types where an empty cell is a missing value rather than an invalid one, and
+	// whether it's allowed is up to the required constraint
+	enforceOptionalType := func(fieldType string, header string, required requiredConstraint, enforceTextualType func(string, string) (CellValidationResult, error)) error {
+		if row[header] != "" {
+			dataTypeValidationFailure, err := enforceType(fieldType, header, func() (CellValidationResult, error) {
+				return enforceTextualType(header, row[header])
+			})
+			if err != nil {
+				return err
+			}
+			handleValidationResult(dataTypeValidationFailure)
+		}
+		requiredValidationFailure, err := EnforceRequiredConstraint(required, header, row[header])
+		if err != nil {
+			return err
+		}
+		handleValidationResult(requiredValidationFailure)
+		return nil
+	}
+
+	for _, integerField := range schema.Fields.IntegerFields {
+		if err := enforceOptionalType("integer", integerField.Name, integerField.Constraints.Required, EnforceIntegerConstraint); err != nil {
+			return RowValidationResult{}, err
+		}
+	}
+
+	for _, dateField := range schema.Fields.DateFields {
+		if err := enforceOptionalType("date", dateField.Name, dateField.Constraints.Required, EnforceDateConstraint); err != nil {
+			return RowValidationResult{}, err
+		}
+	}
+
+	for _, dateTimeField := range schema.Fields.DateTimeFields {
+		if err := enforceOptionalType("datetime", dateTimeField.Name, dateTimeField.Constraints.Required, EnforceDateTimeConstraint); err != nil {
+			return RowValidationResult{}, err
+		}
+	}
+
 	return RowValidationResult{Original: rawRow, Parsed: row, IsValid: isValid, Failures: validationFailures}, nil
 }
 
@@ -104,6 +274,24 @@ func validateColumns(schema schema.Schema, validatedRows *[]RowValidationResult)
 		continue
 	}
 
+	for _, integerField := range schema.Fields.IntegerFields {
+		if integerField.Constraints.Unique.Selected && integerField.Constraints.Unique.Value {
+			EnforceUniqueConstraint(integerField.Constraints.Unique, integerField.Name, validatedRows)
+		}
+	}
+
+	for _, dateField := range schema.Fields.DateFields {
+		if dateField.Constraints.Unique.Selected && dateField.Constraints.Unique.Value {
+			EnforceUniqueConstraint(dateField.Constraints.Unique, dateField.Name, validatedRows)
+		}
+	}
+
+	for _, dateTimeField := range schema.Fields.DateTimeFields {
+		if dateTimeField.Constraints.Unique.Selected && dateTimeField.Constraints.Unique.Value {
+			EnforceUniqueConstraint(dateTimeField.Constraints.Unique, dateTimeField.Name, validatedRows)
+		}
+	}
+
 	return validatedRows
 }
 
@@ -111,27 +299,134 @@ func validateColumns(schema schema.Schema, validatedRows *[]RowValidationResult)
 // such as a `*csv.Reader` from Go's `encoding/csv`library, and returns a list of
 // `RowValidationResult` structs.
 func Validate(schema schema.Schema, sourceData Readable) ([]RowValidationResult, error) {
+	report, err := ValidateWithOptions(schema, sourceData, ValidationOptions{})
+	return report.Rows, err
+}
+
+// ValidateWithOptions is Validate with limits on how much of an invalid source is examined. It
+// returns a ValidationReport, which records whether the limits in options truncated validation
+// and how many rows were examined before they did. If sourceData is a source.Table, such as a
+// Reader from the source package, its dialect decides which rows are headers.
+func ValidateWithOptions(schema schema.Schema, sourceData Readable, options ValidationOptions) (ValidationReport, error) {
+	if table, ok := sourceData.(source.Table); ok {
+		return ValidateTable(schema, table, options)
+	}
+	// a *csv.Reader is read record by record, rather than with ReadAll, so that each row's line is known and
+	// reading can carry on past a malformed record
+	if csvReader, ok := sourceData.(*csv.Reader); ok {
+		return ValidateTable(schema, source.FromCSVReader(csvReader, source.DefaultDialect()), options)
+	}
+
 	data, err := sourceData.ReadAll()
 	if err != nil {
-		return nil, err
+		return ValidationReport{}, err
 	}
 
-	headers := data[0]
+	return ValidateTable(schema, source.NewRecordsReader(data, source.DefaultDialect()), options)
+}
+
+// ValidateTable validates the records of a source.Table. If the table has no header its columns are
+// matched to the schema's fields by position.
+func ValidateTable(schema schema.Schema, table source.Table, options ValidationOptions) (ValidationReport, error) {
+	headers, err := table.Header()
+	if err != nil {
+		return ValidationReport{}, err
+	}
+	// row numbers count the header row, for tables that don't number their records themselves
+	rowNumber := 0
+	if headers != nil {
+		rowNumber = 1
+	}
+	if headers == nil {
+		headers = schema.Fields.Names()
+	}
 
+	limiter := newErrorLimiter(options)
 	var rowValidationResults []RowValidationResult
+	// the addresses of each row's cells by header, if the source has them (see source.Record)
+	var cellRefs []map[string]string
 
-	for _, rawRow := range data[1:] {
-		row := mapRowCellsToHeaders(headers, rawRow)
-		rowValidationResult, err := validateRow(rawRow, row, schema)
-		if err != nil {
-			return rowValidationResults, err
+	for {
+		record, err := table.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if record.Row == 0 {
+			record.Row = rowNumber + 1
+		}
+		rowNumber = record.Row
+
+		var rowValidationResult RowValidationResult
+		var rowCellRefs map[string]string
+
+		var parseErr *csv.ParseError
+		if options.TolerateSourceErrors && errors.As(err, &parseErr) {
+			rowValidationResult = sourceErrorResult(record, parseErr)
+			locateFailures(&rowValidationResult, nil, 0)
+		} else {
+			if err != nil {
+				return ValidationReport{Rows: rowValidationResults, RowsExamined: len(rowValidationResults)}, err
+			}
+
+			recordHeaders := headers
+			if record.Keys != nil {
+				recordHeaders = record.Keys
+			}
+
+			row := mapRowCellsToHeaders(recordHeaders, record.Cells)
+			nativeRow := mapRowValuesToHeaders(recordHeaders, record.Values)
+			rowValidationResult, err = validateRow(record.Cells, row, nativeRow, schema)
+			if err != nil {
+				return ValidationReport{Rows: rowValidationResults, RowsExamined: len(rowValidationResults)}, err
+			}
+			rowValidationResult.RowNumber, rowValidationResult.Line, rowValidationResult.Offset = record.Row, record.Line, record.Offset
+			rowCellRefs = mapRowCellsToHeaders(recordHeaders, record.Refs)
+			locateFailures(&rowValidationResult, rowCellRefs, 0)
 		}
+		cellRefs = append(cellRefs, rowCellRefs)
+		limiter.limitFailures(&rowValidationResult, 0)
 		rowValidationResults = append(rowValidationResults, rowValidationResult)
+
+		stopReason := ""
+		if options.FailFast && !rowValidationResult.IsValid {
+			stopReason = "stopped at the first invalid row"
+		} else if limiter.exhausted() {
+			stopReason = "reached the maximum of " + strconv.Itoa(options.MaxErrors) + " errors"
+		}
+
+		// validation was only truncated if there were rows left to examine
+		if stopReason != "" {
+			if _, err := table.Read(); !errors.Is(err, io.EOF) {
+				limiter.truncate(stopReason)
+			}
+			break
+		}
+	}
+
+	// column validations append to the failures of rows that have already been limited, so only the new ones are checked
+	failureCounts := make([]int, len(rowValidationResults))
+	for index, row := range rowValidationResults {
+		failureCounts[index] = len(row.Failures)
 	}
 
 	columnValidationResults := validateColumns(schema, &rowValidationResults)
 
-	return *columnValidationResults, nil
+	for index := range *columnValidationResults {
+		locateFailures(&(*columnValidationResults)[index], cellRefs[index], failureCounts[index])
+		limiter.limitFailures(&(*columnValidationResults)[index], failureCounts[index])
+	}
+
+	report := ValidationReport{
+		Rows:             *columnValidationResults,
+		RowsExamined:     len(*columnValidationResults),
+		Truncated:        limiter.truncated,
+		TruncationReason: limiter.reason,
+	}
+	if named, ok := table.(source.Named); ok {
+		report.Source = named.Name()
+		report.Member = named.Member()
+	}
+	return report, nil
 }
 
 // TODOs
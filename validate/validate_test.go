@@ -1,9 +1,14 @@
 package validate
 
 import (
+	"archive/zip"
+	"bytes"
 	"encoding/csv"
+	"io"
 	"os"
+	"strings"
 	"tableschema-validator/schema"
+	"tableschema-validator/source"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -48,17 +53,17 @@ func TestValidate(t *testing.T) {
 	reader := csv.NewReader(file)
 
 	expected := []RowValidationResult{
-		{Original: []string{"baz", "baz", "0"}, Parsed: map[string]string{"bar": "baz", "foo": "baz", "php": "0"}, IsValid: false, Failures: []CellValidationResult{
-			{header: "foo", value: "baz", constraint: "unique", isValid: false, reason: "foo was marked as unique but its value baz was found on rows 0, 4 (this row: 0)"},
+		{Original: []string{"baz", "baz", "0"}, Parsed: map[string]string{"bar": "baz", "foo": "baz", "php": "0"}, IsValid: false, RowNumber: 2, Line: 2, Offset: -1, Failures: []CellValidationResult{
+			{header: "foo", value: "baz", constraint: "unique", isValid: false, reason: "foo was marked as unique but its value baz was found on rows 2, 6 (row 2, line 2)"},
 		}},
-		{Original: []string{"bar", "luhrman", "2"}, Parsed: map[string]string{"bar": "luhrman", "foo": "bar", "php": "2"}, IsValid: true, Failures: nil},
-		{Original: []string{"100", "antidisestablishmentarianism", "3"}, Parsed: map[string]string{"bar": "antidisestablishmentarianism", "foo": "100", "php": "3"}, IsValid: true, Failures: nil},
-		{Original: []string{"", "qux", ""}, Parsed: map[string]string{"bar": "qux", "foo": "", "php": ""}, IsValid: false, Failures: []CellValidationResult{
-			{header: "foo", constraint: "required", isValid: false, value: "", reason: "foo was marked as required, but not provided"},
-			{header: "php", constraint: "required", isValid: false, value: "", reason: "php was marked as required, but not provided"},
+		{Original: []string{"bar", "luhrman", "2"}, Parsed: map[string]string{"bar": "luhrman", "foo": "bar", "php": "2"}, IsValid: true, Failures: nil, RowNumber: 3, Line: 3, Offset: -1},
+		{Original: []string{"100", "antidisestablishmentarianism", "3"}, Parsed: map[string]string{"bar": "antidisestablishmentarianism", "foo": "100", "php": "3"}, IsValid: true, Failures: nil, RowNumber: 4, Line: 4, Offset: -1},
+		{Original: []string{"", "qux", ""}, Parsed: map[string]string{"bar": "qux", "foo": "", "php": ""}, IsValid: false, RowNumber: 5, Line: 5, Offset: -1, Failures: []CellValidationResult{
+			{header: "foo", constraint: "required", isValid: false, value: "", reason: "foo was marked as required, but not provided (row 5, line 5)"},
+			{header: "php", constraint: "required", isValid: false, value: "", reason: "php was marked as required, but not provided (row 5, line 5)"},
 		}},
-		{Original: []string{"baz", "ghgh1010101010101", "4"}, Parsed: map[string]string{"bar": "ghgh1010101010101", "foo": "baz", "php": "4"}, IsValid: false, Failures: []CellValidationResult{
-			{header: "foo", value: "baz", constraint: "unique", isValid: false, reason: "foo was marked as unique but its value baz was found on rows 0, 4 (this row: 4)"},
+		{Original: []string{"baz", "ghgh1010101010101", "4"}, Parsed: map[string]string{"bar": "ghgh1010101010101", "foo": "baz", "php": "4"}, IsValid: false, RowNumber: 6, Line: 6, Offset: -1, Failures: []CellValidationResult{
+			{header: "foo", value: "baz", constraint: "unique", isValid: false, reason: "foo was marked as unique but its value baz was found on rows 2, 6 (row 6, line 6)"},
 		}}}
 
 	got, err := Validate(schema, reader)
@@ -72,3 +77,316 @@ func TestValidate(t *testing.T) {
 	}
 
 }
+
+func TestValidateWithOptions(t *testing.T) {
+	schema := schema.MakeSchema(schema.SchemaOptions{
+		Fields: schema.Fields{
+			StringFields: []schema.StringField{
+				{
+					FieldBase: schema.FieldBase{Name: "foo"},
+					Constraints: schema.StringConstraints{
+						Required: schema.RequiredConstraint{Selected: true, Value: true},
+					},
+				},
+			},
+			NumberFields: []schema.NumberField{
+				{
+					FieldBase: schema.FieldBase{Name: "bar"},
+					Constraints: schema.NumberConstraints{
+						Unique: schema.UniqueContraint{Selected: true, Value: true},
+					},
+				},
+			},
+		},
+	})
+
+	source := "foo,bar\n,x\n,1\na,1\n,y\nb,2\n"
+
+	testCases := []struct {
+		name             string
+		options          ValidationOptions
+		rowsExamined     int
+		failures         int
+		truncated        bool
+		truncationReason string
+	}{
+		{name: "no limits", options: ValidationOptions{}, rowsExamined: 5, failures: 7, truncated: false},
+		{name: "fail fast", options: ValidationOptions{FailFast: true}, rowsExamined: 1, failures: 2, truncated: true, truncationReason: "stopped at the first invalid row"},
+		{name: "max errors", options: ValidationOptions{MaxErrors: 3}, rowsExamined: 2, failures: 3, truncated: true, truncationReason: "reached the maximum of 3 errors"},
+		{name: "max errors per column", options: ValidationOptions{MaxErrorsPerColumn: 1}, rowsExamined: 5, failures: 2, truncated: true, truncationReason: "reached the maximum of 1 errors for column foo"},
+		{name: "limits not reached", options: ValidationOptions{MaxErrors: 7, MaxErrorsPerColumn: 4}, rowsExamined: 5, failures: 7, truncated: false},
+	}
+
+	for _, testCase := range testCases {
+		report, err := ValidateWithOptions(schema, csv.NewReader(strings.NewReader(source)), testCase.options)
+		if err != nil {
+			t.Errorf("%s: failed to validate with error %s", testCase.name, err.Error())
+			continue
+		}
+
+		failures := 0
+		for _, row := range report.Rows {
+			failures += len(row.Failures)
+		}
+
+		if report.RowsExamined != testCase.rowsExamined || len(report.Rows) != testCase.rowsExamined {
+			t.Errorf("%s: expected %d rows examined, got %d (%d rows)", testCase.name, testCase.rowsExamined, report.RowsExamined, len(report.Rows))
+		}
+		if failures != testCase.failures {
+			t.Errorf("%s: expected %d failures, got %d", testCase.name, testCase.failures, failures)
+		}
+		if report.Truncated != testCase.truncated || report.TruncationReason != testCase.truncationReason {
+			t.Errorf("%s: expected truncated %t (%q), got %t (%q)", testCase.name, testCase.truncated, testCase.truncationReason, report.Truncated, report.TruncationReason)
+		}
+		if report.IsValid() {
+			t.Errorf("%s: expected an invalid report", testCase.name)
+		}
+	}
+}
+
+func TestValidateHeaderlessTable(t *testing.T) {
+	schema := schema.MakeSchema(schema.SchemaOptions{
+		Fields: schema.Fields{
+			StringFields: []schema.StringField{
+				{
+					FieldBase:   schema.FieldBase{Name: "foo"},
+					Constraints: schema.StringConstraints{Required: schema.RequiredConstraint{Selected: true, Value: true}},
+				},
+			},
+			NumberFields: []schema.NumberField{
+				{FieldBase: schema.FieldBase{Name: "bar"}},
+			},
+		},
+	})
+
+	dialect := source.DefaultDialect()
+	dialect.Header = false
+	dialect.Delimiter = "\t"
+
+	got, err := Validate(schema, source.NewCSVReader(strings.NewReader("a\t1\n\tb\n"), dialect))
+	if err != nil {
+		t.Fatalf("Failed to validate headerless source with error %s", err.Error())
+	}
+
+	expected := []RowValidationResult{
+		{Original: []string{"a", "1"}, Parsed: map[string]string{"foo": "a", "bar": "1"}, IsValid: true, RowNumber: 1, Line: 1, Offset: 0},
+		{Original: []string{"", "b"}, Parsed: map[string]string{"foo": "", "bar": "b"}, IsValid: false, RowNumber: 2, Line: 2, Offset: 4, Failures: []CellValidationResult{
+			{header: "foo", value: "", constraint: "required", reason: "foo was marked as required, but not provided (row 2, line 2)"},
+			{header: "bar", value: "b", constraint: "Number", reason: "bar was marked as a number, but its value b could not be parsed as a number (row 2, line 2)"},
+		}},
+	}
+
+	if diff := cmp.Diff(expected, got, cmp.AllowUnexported(CellValidationResult{})); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestValidateByteOrderMark(t *testing.T) {
+	schema := schema.MakeSchema(schema.SchemaOptions{
+		Fields: schema.Fields{
+			StringFields: []schema.StringField{
+				{
+					FieldBase:   schema.FieldBase{Name: "id"},
+					Constraints: schema.StringConstraints{Required: schema.RequiredConstraint{Selected: true, Value: true}},
+				},
+			},
+		},
+	})
+
+	// a reader from the standard library leaves the byte order mark in the first header
+	got, err := Validate(schema, csv.NewReader(strings.NewReader("\ufeffid\n1\n")))
+	if err != nil {
+		t.Fatalf("Failed to validate source with error %s", err.Error())
+	}
+
+	expected := []RowValidationResult{
+		{Original: []string{"1"}, Parsed: map[string]string{"id": "1"}, IsValid: true, RowNumber: 2, Line: 2, Offset: -1},
+	}
+
+	if diff := cmp.Diff(expected, got, cmp.AllowUnexported(CellValidationResult{})); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestValidateSourceErrors(t *testing.T) {
+	schema := schema.MakeSchema(schema.SchemaOptions{
+		Fields: schema.Fields{
+			StringFields: []schema.StringField{
+				{
+					FieldBase:   schema.FieldBase{Name: "id"},
+					Constraints: schema.StringConstraints{Unique: schema.UniqueContraint{Selected: true, Value: true}},
+				},
+			},
+			NumberFields: []schema.NumberField{
+				{FieldBase: schema.FieldBase{Name: "score"}},
+			},
+		},
+	})
+
+	input := "id,score\na,1\nb,2\"\nc,\"3\"x\nd,four\n"
+	sourceError := func(rowNumber int, offset int64, reason string) RowValidationResult {
+		return RowValidationResult{IsValid: false, RowNumber: rowNumber, Line: rowNumber, Offset: offset, Failures: []CellValidationResult{{constraint: "source-error", reason: reason}}}
+	}
+
+	_, err := Validate(schema, source.NewCSVReader(strings.NewReader(input), source.DefaultDialect()))
+	if err == nil {
+		t.Error("Expected a parse error without TolerateSourceErrors")
+	}
+
+	options := ValidationOptions{TolerateSourceErrors: true}
+	report, err := ValidateWithOptions(schema, source.NewCSVReader(strings.NewReader(input), source.DefaultDialect()), options)
+	if err != nil {
+		t.Fatalf("Failed to validate source with error %s", err.Error())
+	}
+
+	expected := []RowValidationResult{
+		{Original: []string{"a", "1"}, Parsed: map[string]string{"id": "a", "score": "1"}, IsValid: true, RowNumber: 2, Line: 2, Offset: 9},
+		sourceError(3, 13, "the record could not be parsed: bare \" in non-quoted-field (row 3, line 3)"),
+		sourceError(4, 18, "the record could not be parsed: extraneous or missing \" in quoted-field (row 4, line 4)"),
+		{Original: []string{"d", "four"}, Parsed: map[string]string{"id": "d", "score": "four"}, IsValid: false, RowNumber: 5, Line: 5, Offset: 25, Failures: []CellValidationResult{
+			{header: "score", value: "four", constraint: "Number", reason: "score was marked as a number, but its value four could not be parsed as a number (row 5, line 5)"},
+		}},
+	}
+
+	if diff := cmp.Diff(expected, report.Rows, cmp.AllowUnexported(CellValidationResult{})); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	// a *csv.Reader reports a quote left open as spanning the rest of the source, and doesn't know where records start
+	got, err := ValidateWithOptions(schema, csv.NewReader(strings.NewReader("id,score\na,1\nb,\"2\nc,3\n")), options)
+	if err != nil {
+		t.Fatalf("Failed to validate source with error %s", err.Error())
+	}
+
+	expected = []RowValidationResult{
+		{Original: []string{"a", "1"}, Parsed: map[string]string{"id": "a", "score": "1"}, IsValid: true, RowNumber: 2, Line: 2, Offset: -1},
+		sourceError(3, -1, "the record could not be parsed: extraneous or missing \" in quoted-field on line 4 (row 3, line 3)"),
+	}
+
+	if diff := cmp.Diff(expected, got.Rows, cmp.AllowUnexported(CellValidationResult{})); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestValidateNativeValues(t *testing.T) {
+	schema := schema.MakeSchema(schema.SchemaOptions{
+		Fields: schema.Fields{
+			StringFields:  []schema.StringField{{FieldBase: schema.FieldBase{Name: "name"}}},
+			NumberFields:  []schema.NumberField{{FieldBase: schema.FieldBase{Name: "score"}}},
+			BooleanFields: []schema.BooleanField{{FieldBase: schema.FieldBase{Name: "active"}}},
+		},
+	})
+
+	input := `[{"name": "a", "score": 1e5, "active": true}, {"name": 7, "score": "2", "active": "yes"}, {"name": "c", "score": false, "active": "1"}]`
+
+	report, err := ValidateTable(schema, source.NewJSONReader(strings.NewReader(input), source.DefaultDialect()), ValidationOptions{})
+	if err != nil {
+		t.Fatalf("Failed to validate JSON source with error %s", err.Error())
+	}
+
+	var got [][]CellValidationResult
+	for _, row := range report.Rows {
+		got = append(got, row.Failures)
+	}
+
+	expected := [][]CellValidationResult{
+		nil,
+		{
+			{header: "name", value: "7", constraint: "String", reason: "name was marked as a string, but its value 7 is a JSON number (row 2)"},
+			{header: "active", value: "yes", constraint: "Boolean", reason: "active was marked as a boolean, but its value yes could not be parsed as a boolean (row 2)"},
+		},
+		{
+			{header: "score", value: "false", constraint: "Number", reason: "score was marked as a number, but its value false is a JSON boolean (row 3)"},
+		},
+	}
+
+	if diff := cmp.Diff(expected, got, cmp.AllowUnexported(CellValidationResult{})); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+// recordsTable is a source.Table over records that have already been read, for testing how their properties are used.
+type recordsTable struct {
+	header  []string
+	records []source.Record
+}
+
+func (table *recordsTable) Header() ([]string, error) {
+	return table.header, nil
+}
+
+func (table *recordsTable) Read() (source.Record, error) {
+	if len(table.records) == 0 {
+		return source.Record{}, io.EOF
+	}
+	record := table.records[0]
+	table.records = table.records[1:]
+	return record, nil
+}
+
+func TestValidateCellReferences(t *testing.T) {
+	schema := schema.MakeSchema(schema.SchemaOptions{
+		Fields: schema.Fields{
+			NumberFields: []schema.NumberField{
+				{
+					FieldBase:   schema.FieldBase{Name: "score"},
+					Constraints: schema.NumberConstraints{Unique: schema.UniqueContraint{Selected: true, Value: true}},
+				},
+			},
+		},
+	})
+
+	table := &recordsTable{header: []string{"name", "score"}, records: []source.Record{
+		{Cells: []string{"a", "x"}, Refs: []string{"A2", "B2"}},
+		{Cells: []string{"b", "x"}, Refs: []string{"A3", "B3"}},
+	}}
+
+	report, err := ValidateTable(schema, table, ValidationOptions{})
+	if err != nil {
+		t.Fatalf("Failed to validate table with error %s", err.Error())
+	}
+
+	expected := []CellValidationResult{
+		{header: "score", value: "x", constraint: "Number", cell: "B3", reason: "score was marked as a number, but its value x could not be parsed as a number (row 3, cell B3)"},
+		{header: "score", value: "x", constraint: "unique", cell: "B3", reason: "score was marked as unique but its value x was found on rows 2, 3 (row 3, cell B3)"},
+	}
+
+	if diff := cmp.Diff(expected, report.Rows[1].Failures, cmp.AllowUnexported(CellValidationResult{})); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestValidateArchiveMember(t *testing.T) {
+	schema := schema.MakeSchema(schema.SchemaOptions{
+		Fields: schema.Fields{
+			NumberFields: []schema.NumberField{{FieldBase: schema.FieldBase{Name: "id"}}},
+		},
+	})
+
+	var archive bytes.Buffer
+	writer := zip.NewWriter(&archive)
+	member, err := writer.Create("exports/ids.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	member.Write([]byte("id\n1\ntwo\n"))
+	writer.Close()
+
+	file, err := source.OpenReader(&archive, "drop.zip", source.DefaultOptions())
+	if err != nil {
+		t.Fatalf("Failed to open archive with error %s", err.Error())
+	}
+	defer file.Close()
+
+	report, err := ValidateWithOptions(schema, file, ValidationOptions{})
+	if err != nil {
+		t.Fatalf("Failed to validate archive with error %s", err.Error())
+	}
+
+	if report.Source != "drop.zip" || report.Member != "exports/ids.csv" {
+		t.Errorf("Expected the report to name drop.zip and exports/ids.csv, got %q and %q", report.Source, report.Member)
+	}
+	if report.RowsExamined != 2 || report.IsValid() {
+		t.Errorf("Expected 2 rows examined and an invalid report, got %d rows and valid %t", report.RowsExamined, report.IsValid())
+	}
+}
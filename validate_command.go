@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"tableschema-validator/schema"
+	"tableschema-validator/source"
+	"tableschema-validator/validate"
+)
+
+// validationReport is the JSON report of the validate command.
+type validationReport struct {
+	Valid   bool           `json:"valid"`
+	Sources []sourceReport `json:"sources"`
+}
+
+type sourceReport struct {
+	Source           string        `json:"source"`
+	Member           string        `json:"member,omitempty"`
+	Valid            bool          `json:"valid"`
+	RowsExamined     int           `json:"rowsExamined"`
+	InvalidRows      int           `json:"invalidRows"`
+	Truncated        bool          `json:"truncated"`
+	TruncationReason string        `json:"truncationReason,omitempty"`
+	Errors           []errorReport `json:"errors"`
+	// Error is why the source couldn't be validated, if it couldn't
+	Error string `json:"error,omitempty"`
+}
+
+type errorReport struct {
+	Row        int    `json:"row"`
+	Line       int    `json:"line,omitempty"`
+	Offset     *int64 `json:"offset,omitempty"`
+	Field      string `json:"field,omitempty"`
+	Cell       string `json:"cell,omitempty"`
+	Value      string `json:"value,omitempty"`
+	Constraint string `json:"constraint"`
+	Reason     string `json:"reason"`
+}
+
+// isDataError reports whether err is a problem with the data itself, which makes a source invalid, rather
+// than a problem reading it.
+func isDataError(err error) bool {
+	var encodingErr *source.EncodingError
+	return errors.As(err, &encodingErr)
+}
+
+func newSourceReport(name string, report validate.ValidationReport) sourceReport {
+	result := sourceReport{
+		Source:           name,
+		Member:           report.Member,
+		Valid:            report.IsValid(),
+		RowsExamined:     report.RowsExamined,
+		Truncated:        report.Truncated,
+		TruncationReason: report.TruncationReason,
+		Errors:           []errorReport{},
+	}
+
+	for _, row := range report.Rows {
+		if row.IsValid {
+			continue
+		}
+		result.InvalidRows++
+		for _, failure := range row.Failures {
+			failureReport := errorReport{
+				Row:        row.RowNumber,
+				Line:       row.Line,
+				Field:      failure.Header(),
+				Cell:       failure.Cell(),
+				Value:      failure.Value(),
+				Constraint: failure.Constraint(),
+				Reason:     failure.Reason(),
+			}
+			if row.Offset >= 0 {
+				offset := row.Offset
+				failureReport.Offset = &offset
+			}
+			result.Errors = append(result.Errors, failureReport)
+		}
+	}
+	return result
+}
+
+// validateSource opens and validates a single source. The returned error is set if the source couldn't be
+// read; it is also recorded in the report.
+func validateSource(tableSchema schema.Schema, path string, stdin io.Reader, options source.Options, validationOptions validate.ValidationOptions) (sourceReport, error) {
+	file, name, err := openSource(path, stdin, options)
+	if err != nil {
+		return sourceReport{Source: name, Errors: []errorReport{}, Error: err.Error()}, err
+	}
+	defer file.Close()
+
+	report, err := validate.ValidateTable(tableSchema, file, validationOptions)
+	result := newSourceReport(name, report)
+	if err != nil {
+		result.Valid = false
+		result.Error = err.Error()
+		if !isDataError(err) {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// countRows returns e.g. "1 row" or "3 rows".
+func countRows(count int) string {
+	if count == 1 {
+		return "1 row"
+	}
+	return fmt.Sprintf("%d rows", count)
+}
+
+func printSourceReport(w io.Writer, report sourceReport) {
+	name := report.Source
+	if report.Member != "" {
+		name += " (" + report.Member + ")"
+	}
+
+	switch {
+	case report.Valid:
+		fmt.Fprintf(w, "%s: valid, %s\n", name, countRows(report.RowsExamined))
+	case report.RowsExamined == 0 && report.Error != "":
+		fmt.Fprintf(w, "%s: error: %s\n", name, report.Error)
+		return
+	default:
+		fmt.Fprintf(w, "%s: invalid, %d of %s invalid\n", name, report.InvalidRows, countRows(report.RowsExamined))
+	}
+
+	for _, failure := range report.Errors {
+		fmt.Fprintf(w, "  %s\n", failure.Reason)
+	}
+	if report.Truncated {
+		fmt.Fprintf(w, "  validation stopped early: %s\n", report.TruncationReason)
+	}
+	if report.Error != "" {
+		fmt.Fprintf(w, "  error: %s\n", report.Error)
+	}
+}
+
+func runValidate(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := newFlagSet("validate", stderr)
+	schemaFlags := addSchemaFlags(flags)
+	schemaPath := flags.String("schema", "", "path to the table schema descriptor (required)")
+	output := flags.String("output", "text", `report format, "text" or "json"`)
+	dataFlags := addSourceFlags(flags)
+	maxErrors := flags.Int("max-errors", 0, "stop after this many errors (0 for no limit)")
+	maxErrorsPerColumn := flags.Int("max-errors-per-column", 0, "report at most this many errors for each column (0 for no limit)")
+	failFast := flags.Bool("fail-fast", false, "stop at the first invalid row")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: tableschema-validator validate --schema schema.json [flags] [file ...]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Validates each file against the schema, or stdin if there are no files or a file is -.")
+		fmt.Fprintln(stderr, "Exits with 0 if every file is valid, 1 if any is invalid, and 2 on a usage or read error.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+
+	paths, err := parseFlags(flags, args)
+	if err != nil {
+		return flagsExitCode(err)
+	}
+	if *schemaPath == "" {
+		fmt.Fprintln(stderr, "tableschema-validator validate: --schema is required")
+		flags.Usage()
+		return exitError
+	}
+	if *output != "text" && *output != "json" {
+		fmt.Fprintf(stderr, "tableschema-validator validate: unknown output %q, expected text or json\n", *output)
+		return exitError
+	}
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+
+	tableSchema, err := schemaFlags.load(*schemaPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "tableschema-validator validate: %s\n", err.Error())
+		return exitError
+	}
+
+	options, err := dataFlags.options()
+	if err != nil {
+		fmt.Fprintf(stderr, "tableschema-validator validate: %s\n", err.Error())
+		return exitError
+	}
+
+	validationOptions := validate.ValidationOptions{
+		MaxErrors:          *maxErrors,
+		MaxErrorsPerColumn: *maxErrorsPerColumn,
+		FailFast:           *failFast,
+		// a malformed line is reported like any other failure, rather than hiding the rest of the file
+		TolerateSourceErrors: true,
+	}
+
+	report := validationReport{Valid: true}
+	exitCode := exitValid
+	for _, path := range paths {
+		result, err := validateSource(tableSchema, path, stdin, options, validationOptions)
+		report.Sources = append(report.Sources, result)
+		if err != nil {
+			exitCode = exitError
+		} else if !result.Valid && exitCode == exitValid {
+			exitCode = exitInvalid
+		}
+		report.Valid = report.Valid && result.Valid
+		if *output == "text" {
+			printSourceReport(stdout, result)
+		}
+	}
+
+	if *output == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			fmt.Fprintf(stderr, "tableschema-validator validate: %s\n", err.Error())
+			return exitError
+		}
+	}
+	return exitCode
+}
@@ -0,0 +1,949 @@
+// Package yaml converts documents between YAML and JSON, so that descriptors can be written in either and
+// read with encoding/json. It reads the part of YAML 1.2 that configuration files use: block mappings and
+// sequences, flow ones such as [a, b], plain and quoted scalars, literal and folded block scalars, and
+// comments. Anchors, aliases, tags and multiple documents aren't supported. Keys are kept in their order.
+//
+// Scalars are typed as in YAML 1.2's core schema: true, false, null and numbers are JSON's, and anything
+// else is a string. So a string that looks like a number, e.g. an enum value of "1", has to be quoted.
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// member is a key of a mapping and its value.
+type member struct {
+	key   string
+	value any
+}
+
+// A mapping is a YAML mapping or JSON object, whose members are kept in order. The other values of a
+// document are []any, string, json.Number, bool and nil.
+type mapping []member
+
+// ToJSON returns the YAML document data as JSON.
+func ToJSON(data []byte) ([]byte, error) {
+	if !utf8.Valid(data) {
+		return nil, errors.New("the document isn't UTF-8")
+	}
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	reader := &reader{lines: strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")}
+	value, err := reader.document()
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	if err := writeJSON(&buffer, value); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// FromJSON returns the JSON document data as YAML, in block style but for sequences of scalars, which are
+// written as flow sequences, e.g. [a, b].
+func FromJSON(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	value, err := decodeJSON(decoder)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("the JSON has more than one value")
+	}
+	var buffer bytes.Buffer
+	writeYAML(&buffer, value, 0)
+	return buffer.Bytes(), nil
+}
+
+// reader reads a YAML document a line at a time.
+type reader struct {
+	lines []string
+	// index is the index of the line being read.
+	index int
+}
+
+// errorf returns an error in the line being read.
+func (reader *reader) errorf(format string, args ...any) error {
+	return errorAt(reader.index, format, args...)
+}
+
+// errorAt returns an error in the line at index.
+func errorAt(index int, format string, args ...any) error {
+	return fmt.Errorf("line %d: %s", index+1, fmt.Sprintf(format, args...))
+}
+
+// peek returns the indentation and content, without its comment, of the next line that has any, or ok false
+// at the end of the document.
+func (reader *reader) peek() (indent int, content string, ok bool, err error) {
+	for ; reader.index < len(reader.lines) && !reader.atDocumentStart(); reader.index++ {
+		line := reader.lines[reader.index]
+		if line == "..." || strings.HasPrefix(line, "... ") {
+			// the end of the document: anything after it is ignored
+			reader.index = len(reader.lines)
+			break
+		}
+		content := strings.TrimLeft(line, " ")
+		if strings.HasPrefix(content, "\t") {
+			return 0, "", false, reader.errorf("a line can't be indented with tabs")
+		}
+		content = stripComment(content)
+		if content != "" {
+			return len(line) - len(strings.TrimLeft(line, " ")), content, true, nil
+		}
+	}
+	return 0, "", false, nil
+}
+
+// stripComment returns line without its comment, which starts with a # at its start or after a space that
+// isn't in a quoted scalar, and without trailing spaces.
+func stripComment(line string) string {
+	var quote byte
+	// tokenStart is whether a quote here would start a quoted scalar rather than be part of a plain one
+	tokenStart := true
+	for index := 0; index < len(line); index++ {
+		character := line[index]
+		switch {
+		case quote == '"' && character == '\\':
+			index++
+		case quote != 0:
+			if character == quote {
+				quote = 0
+			}
+		case character == '#' && (index == 0 || line[index-1] == ' ' || line[index-1] == '\t'):
+			return strings.TrimRight(line[:index], " \t")
+		case (character == '"' || character == '\'') && tokenStart:
+			quote = character
+		}
+		if quote == 0 {
+			tokenStart = strings.IndexByte("[{,", character) >= 0 ||
+				(character == ' ' || character == '\t') && (tokenStart || index > 0 && strings.IndexByte(":-", line[index-1]) >= 0)
+		}
+	}
+	return strings.TrimRight(line, " \t")
+}
+
+func (reader *reader) document() (any, error) {
+	indent, content, ok, err := reader.peek()
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(content, "%") {
+		return nil, reader.errorf("directives aren't supported")
+	}
+	if reader.atDocumentStart() {
+		// the document can start on the same line as its ---
+		reader.lines[reader.index] = strings.TrimPrefix(reader.lines[reader.index], "---")
+		if indent, _, ok, err = reader.peek(); err != nil {
+			return nil, err
+		}
+	}
+
+	var value any
+	if ok {
+		if value, err = reader.node(indent); err != nil {
+			return nil, err
+		}
+	}
+	if _, _, ok, err = reader.peek(); err != nil {
+		return nil, err
+	}
+	if reader.atDocumentStart() {
+		return nil, reader.errorf("only one document is supported")
+	}
+	if ok {
+		return nil, reader.errorf("unexpected indentation")
+	}
+	return value, nil
+}
+
+// atDocumentStart reports whether the line being read is a ---, which starts a document.
+func (reader *reader) atDocumentStart() bool {
+	if reader.index == len(reader.lines) {
+		return false
+	}
+	line := reader.lines[reader.index]
+	return line == "---" || strings.HasPrefix(line, "--- ") || strings.HasPrefix(line, "---\t")
+}
+
+// node reads the block node whose first line is the next, at indentation indent.
+func (reader *reader) node(indent int) (any, error) {
+	_, content, _, err := reader.peek()
+	if err != nil {
+		return nil, err
+	}
+	if isSequenceItem(content) {
+		return reader.sequence(indent)
+	}
+	if _, _, isKey, err := reader.splitKey(content); err != nil {
+		return nil, err
+	} else if isKey {
+		return reader.mapping(indent)
+	}
+	// a scalar on a line of its own can only be in a block sequence or be the whole document
+	return reader.value(content, indent-1)
+}
+
+func isSequenceItem(content string) bool {
+	return content == "-" || strings.HasPrefix(content, "- ")
+}
+
+// sequence reads the block sequence whose items start with a - at indentation indent.
+func (reader *reader) sequence(indent int) ([]any, error) {
+	items := []any{}
+	for {
+		lineIndent, content, ok, err := reader.peek()
+		if err != nil {
+			return nil, err
+		}
+		if !ok || lineIndent < indent {
+			return items, nil
+		}
+		if lineIndent > indent {
+			return nil, reader.errorf("unexpected indentation")
+		}
+		if !isSequenceItem(content) {
+			return items, nil
+		}
+
+		rest := strings.TrimLeft(strings.TrimPrefix(content, "-"), " ")
+		if rest == "" {
+			reader.index++
+			item, err := reader.child(indent, false)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			continue
+		}
+
+		// an item on the same line as its -, which is a node of its own if it's a mapping or sequence,
+		// indented as far as it is
+		column := indent + len(content) - len(rest)
+		_, _, isKey, err := reader.splitKey(rest)
+		if err != nil {
+			return nil, err
+		}
+		var item any
+		if isKey || isSequenceItem(rest) {
+			reader.lines[reader.index] = strings.Repeat(" ", column) + rest
+			item, err = reader.node(column)
+		} else {
+			item, err = reader.value(rest, indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+}
+
+// mapping reads the block mapping whose keys are at indentation indent.
+func (reader *reader) mapping(indent int) (mapping, error) {
+	result := mapping{}
+	for {
+		lineIndent, content, ok, err := reader.peek()
+		if err != nil {
+			return nil, err
+		}
+		if !ok || lineIndent < indent {
+			return result, nil
+		}
+		if lineIndent > indent {
+			return nil, reader.errorf("unexpected indentation")
+		}
+		if isSequenceItem(content) {
+			return nil, reader.errorf("expected a key of a mapping, not an item of a sequence")
+		}
+		key, rest, isKey, err := reader.splitKey(content)
+		if err != nil {
+			return nil, err
+		}
+		if !isKey {
+			return nil, reader.errorf("expected a key of a mapping, got %s", content)
+		}
+		for _, existing := range result {
+			if existing.key == key {
+				return nil, reader.errorf("the key %s is already in the mapping", key)
+			}
+		}
+
+		var value any
+		if rest == "" {
+			reader.index++
+			value, err = reader.child(indent, true)
+		} else {
+			value, err = reader.value(rest, indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, member{key: key, value: value})
+	}
+}
+
+// child reads the node on the lines after a key or - at indentation indent that has nothing after it, which is
+// null if there is none. The items of a mapping's sequence can be at the same indentation as its keys.
+func (reader *reader) child(indent int, inMapping bool) (any, error) {
+	childIndent, content, ok, err := reader.peek()
+	if err != nil {
+		return nil, err
+	}
+	if ok && (childIndent > indent || inMapping && childIndent == indent && isSequenceItem(content)) {
+		return reader.node(childIndent)
+	}
+	return nil, nil
+}
+
+// splitKey returns the key and the rest of content, if content is a key of a mapping followed by a :.
+func (reader *reader) splitKey(content string) (key string, rest string, isKey bool, err error) {
+	var end int
+	switch {
+	case content[0] == '"' || content[0] == '\'':
+		key, end, err = quoted(content)
+		if err != nil {
+			return "", "", false, reader.errorf("%s", err.Error())
+		}
+		for end < len(content) && content[end] == ' ' {
+			end++
+		}
+		if end == len(content) || content[end] != ':' {
+			return "", "", false, nil
+		}
+	case strings.IndexByte("[{&*!|>", content[0]) >= 0:
+		return "", "", false, nil
+	default:
+		end = strings.Index(content+" ", ": ")
+		if end < 0 {
+			return "", "", false, nil
+		}
+		key = strings.TrimRight(content[:end], " ")
+	}
+	rest = content[end+1:]
+	if rest != "" && rest[0] != ' ' {
+		return "", "", false, nil
+	}
+	return key, strings.TrimLeft(rest, " "), true, nil
+}
+
+// value reads the scalar or flow collection text, which is on the line being read after a key or - at
+// indentation indent, and may carry on over the following lines.
+func (reader *reader) value(text string, indent int) (any, error) {
+	start := reader.index
+	switch text[0] {
+	case '|', '>':
+		return reader.blockScalar(text, indent)
+	case '[', '{':
+		// a flow collection carries on until its brackets are closed
+		reader.index++
+		for depth := flowDepth(text); depth > 0; depth = flowDepth(text) {
+			lineIndent, content, ok, err := reader.peek()
+			if err != nil {
+				return nil, err
+			}
+			if !ok || lineIndent <= indent {
+				return nil, errorAt(start, "a %c is never closed", text[0])
+			}
+			text += " " + content
+			reader.index++
+		}
+		flow := &flowReader{line: start, text: text}
+		value, err := flow.value()
+		if err != nil {
+			return nil, err
+		}
+		flow.skipSpaces()
+		if flow.position < len(flow.text) {
+			return nil, errorAt(start, "unexpected %s after a flow collection", flow.text[flow.position:])
+		}
+		return value, nil
+	case '"', '\'':
+		value, end, err := quoted(text)
+		if err != nil {
+			return nil, reader.errorf("%s", err.Error())
+		}
+		if strings.TrimSpace(text[end:]) != "" {
+			return nil, reader.errorf("unexpected %s after a quoted scalar", strings.TrimSpace(text[end:]))
+		}
+		reader.index++
+		return value, nil
+	case '&', '*', '!':
+		return nil, reader.errorf("anchors, aliases and tags aren't supported")
+	}
+
+	// a plain scalar carries on over the more indented lines after it, which are folded into it
+	reader.index++
+	for {
+		lineIndent, content, ok, err := reader.peek()
+		if err != nil {
+			return nil, err
+		}
+		if !ok || lineIndent <= indent {
+			break
+		}
+		if _, _, isKey, _ := reader.splitKey(content); isKey {
+			return nil, reader.errorf("unexpected indentation")
+		}
+		text += " " + content
+		reader.index++
+	}
+	value, err := scalar(text)
+	if err != nil {
+		return nil, errorAt(start, "%s", err.Error())
+	}
+	return value, nil
+}
+
+// flowDepth returns how many of the brackets in text aren't closed, ignoring those in quoted scalars. A quote
+// only starts a quoted scalar at the start of a value, so the ' of a plain scalar such as it's isn't one.
+func flowDepth(text string) int {
+	depth := 0
+	var quote byte
+	// tokenStart is whether a quote here would start a quoted scalar rather than be part of a plain one
+	tokenStart := true
+	for index := 0; index < len(text); index++ {
+		character := text[index]
+		switch {
+		case quote == '"' && character == '\\':
+			index++
+		case quote != 0:
+			if character == quote {
+				quote = 0
+			}
+		case (character == '"' || character == '\'') && tokenStart:
+			quote = character
+		case character == '[' || character == '{':
+			depth++
+		case character == ']' || character == '}':
+			depth--
+		}
+		if quote == 0 {
+			tokenStart = strings.IndexByte("[{,:", character) >= 0 || character == ' ' && tokenStart
+		}
+	}
+	return depth
+}
+
+// quoted reads the quoted scalar at the start of text, returning it and the index of the end of its quotes.
+// It has to end on the line it starts on.
+func quoted(text string) (value string, end int, err error) {
+	quote := text[0]
+	if quote == '\'' {
+		var builder strings.Builder
+		for index := 1; index < len(text); index++ {
+			if text[index] != '\'' {
+				builder.WriteByte(text[index])
+			} else if index+1 < len(text) && text[index+1] == '\'' {
+				builder.WriteByte('\'')
+				index++
+			} else {
+				return builder.String(), index + 1, nil
+			}
+		}
+		return "", 0, errors.New("a ' is never closed")
+	}
+
+	for index := 1; index < len(text); index++ {
+		switch text[index] {
+		case '\\':
+			index++
+		case '"':
+			value, err := unescape(text[1:index])
+			return value, index + 1, err
+		}
+	}
+	return "", 0, errors.New(`a " is never closed`)
+}
+
+// escapes are the characters of YAML's escape sequences, other than \x, \u and \U.
+var escapes = map[byte]string{
+	'0': "\x00", 'a': "\a", 'b': "\b", 't': "\t", '\t': "\t", 'n': "\n", 'v': "\v", 'f': "\f", 'r': "\r",
+	'e': "\x1b", ' ': " ", '"': `"`, '/': "/", '\\': `\`, 'N': "\u0085", '_': " ", 'L': " ", 'P': " ",
+}
+
+// unescape returns the value of the double-quoted scalar whose text is text.
+func unescape(text string) (string, error) {
+	var builder strings.Builder
+	for index := 0; index < len(text); index++ {
+		if text[index] != '\\' {
+			builder.WriteByte(text[index])
+			continue
+		}
+		index++
+		if escaped, ok := escapes[text[index]]; ok {
+			builder.WriteString(escaped)
+			continue
+		}
+		digits := map[byte]int{'x': 2, 'u': 4, 'U': 8}[text[index]]
+		if digits == 0 || index+1+digits > len(text) {
+			return "", fmt.Errorf(`\%c isn't an escape sequence`, text[index])
+		}
+		code, err := strconv.ParseUint(text[index+1:index+1+digits], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return "", fmt.Errorf(`\%s isn't an escape sequence`, text[index:index+1+digits])
+		}
+		builder.WriteRune(rune(code))
+		index += digits
+	}
+	return builder.String(), nil
+}
+
+// blockScalar reads the literal (|) or folded (>) scalar whose header is header, whose lines are indented
+// further than indent.
+func (reader *reader) blockScalar(header string, indent int) (string, error) {
+	chomping := byte(0)
+	explicitIndent := 0
+	for _, character := range []byte(header[1:]) {
+		switch {
+		case (character == '-' || character == '+') && chomping == 0:
+			chomping = character
+		case '1' <= character && character <= '9' && explicitIndent == 0:
+			explicitIndent = int(character - '0')
+		default:
+			return "", reader.errorf("%s isn't the header of a block scalar", header)
+		}
+	}
+	reader.index++
+
+	var lines []string
+	contentIndent := 0
+	if explicitIndent > 0 {
+		contentIndent = max(indent, 0) + explicitIndent
+	}
+	for ; reader.index < len(reader.lines); reader.index++ {
+		line := strings.TrimRight(reader.lines[reader.index], " \t\r")
+		lineIndent := len(line) - len(strings.TrimLeft(line, " "))
+		if line == "" {
+			lines = append(lines, "")
+			continue
+		}
+		if contentIndent == 0 {
+			if lineIndent <= indent {
+				break
+			}
+			contentIndent = lineIndent
+		}
+		if lineIndent < contentIndent {
+			break
+		}
+		lines = append(lines, reader.lines[reader.index][contentIndent:])
+	}
+
+	// blank lines at the end are kept only if the scalar is chomped with +
+	content := len(lines)
+	for content > 0 && lines[content-1] == "" {
+		content--
+	}
+	var value string
+	if header[0] == '|' {
+		value = strings.Join(lines[:content], "\n")
+	} else {
+		// folding joins lines with a space, but blank lines are line breaks, and the line breaks around a more
+		// indented line are kept
+		var builder strings.Builder
+		blanks := 0
+		previousIndented := false
+		for index, line := range lines[:content] {
+			if line == "" {
+				blanks++
+				continue
+			}
+			indented := strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+			switch {
+			case builder.Len() == 0 && index == blanks:
+				builder.WriteString(strings.Repeat("\n", blanks))
+			case indented || previousIndented:
+				builder.WriteString(strings.Repeat("\n", blanks+1))
+			case blanks > 0:
+				builder.WriteString(strings.Repeat("\n", blanks))
+			default:
+				builder.WriteByte(' ')
+			}
+			builder.WriteString(line)
+			blanks, previousIndented = 0, indented
+		}
+		value = builder.String()
+	}
+
+	switch {
+	case content == 0 && chomping != '+':
+		return "", nil
+	case chomping == '-':
+		return value, nil
+	case chomping == '+':
+		return value + strings.Repeat("\n", len(lines)-content+1), nil
+	}
+	return value + "\n", nil
+}
+
+var (
+	integerPattern = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	floatPattern   = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+	infinityNaN    = regexp.MustCompile(`^([-+]?\.(inf|Inf|INF)|\.(nan|NaN|NAN))$`)
+)
+
+// scalar returns the value of the plain scalar text, as in YAML 1.2's core schema.
+func scalar(text string) (any, error) {
+	switch text {
+	case "", "~", "null", "Null", "NULL":
+		return nil, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	switch {
+	case integerPattern.MatchString(text):
+		integer := strings.TrimPrefix(text, "+")
+		// JSON numbers can't have leading zeros
+		if trimmed := strings.TrimLeft(strings.TrimPrefix(integer, "-"), "0"); trimmed != strings.TrimPrefix(integer, "-") {
+			if trimmed == "" {
+				trimmed = "0"
+			}
+			if strings.HasPrefix(integer, "-") && trimmed != "0" {
+				trimmed = "-" + trimmed
+			}
+			integer = trimmed
+		}
+		return json.Number(integer), nil
+	case strings.HasPrefix(text, "0x") || strings.HasPrefix(text, "0o"):
+		base := map[string]int{"0x": 16, "0o": 8}[text[:2]]
+		if integer, err := strconv.ParseUint(text[2:], base, 64); err == nil {
+			return json.Number(strconv.FormatUint(integer, 10)), nil
+		}
+	case floatPattern.MatchString(text):
+		number, err := strconv.ParseFloat(text, 64)
+		if err != nil || math.IsInf(number, 0) {
+			return nil, fmt.Errorf("%s is too large a number", text)
+		}
+		return json.Number(strconv.FormatFloat(number, 'g', -1, 64)), nil
+	case infinityNaN.MatchString(text):
+		return nil, fmt.Errorf("%s can't be written in JSON", text)
+	}
+	return text, nil
+}
+
+// flowReader reads a flow collection, e.g. [a, {b: c}].
+type flowReader struct {
+	// line is the index of the line the collection starts on.
+	line     int
+	text     string
+	position int
+}
+
+func (flow *flowReader) errorf(format string, args ...any) error {
+	return errorAt(flow.line, format, args...)
+}
+
+func (flow *flowReader) skipSpaces() {
+	for flow.position < len(flow.text) && flow.text[flow.position] == ' ' {
+		flow.position++
+	}
+}
+
+// value reads the flow collection or scalar at the current position.
+func (flow *flowReader) value() (any, error) {
+	flow.skipSpaces()
+	if flow.position == len(flow.text) {
+		return nil, flow.errorf("expected a value at the end of the line")
+	}
+	switch flow.text[flow.position] {
+	case '[':
+		flow.position++
+		items := []any{}
+		for {
+			flow.skipSpaces()
+			if flow.accept(']') {
+				return items, nil
+			}
+			item, err := flow.value()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			if err := flow.separator(']'); err != nil {
+				return nil, err
+			}
+		}
+	case '{':
+		flow.position++
+		result := mapping{}
+		for {
+			flow.skipSpaces()
+			if flow.accept('}') {
+				return result, nil
+			}
+			key, err := flow.value()
+			if err != nil {
+				return nil, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				if key == nil {
+					return nil, flow.errorf("a key of a mapping can't be null")
+				}
+				name = fmt.Sprint(key)
+			}
+			flow.skipSpaces()
+			var value any
+			if flow.accept(':') {
+				if value, err = flow.value(); err != nil {
+					return nil, err
+				}
+			}
+			result = append(result, member{key: name, value: value})
+			if err := flow.separator('}'); err != nil {
+				return nil, err
+			}
+		}
+	case '"', '\'':
+		value, end, err := quoted(flow.text[flow.position:])
+		if err != nil {
+			return nil, flow.errorf("%s", err.Error())
+		}
+		flow.position += end
+		return value, nil
+	case ']', '}', ',':
+		return nil, flow.errorf("unexpected %c", flow.text[flow.position])
+	}
+
+	// a plain scalar in a flow collection ends at a flow indicator, or at a : that ends a key
+	start := flow.position
+	for flow.position < len(flow.text) && strings.IndexByte(",[]{}", flow.text[flow.position]) < 0 &&
+		!(flow.text[flow.position] == ':' && (flow.position+1 == len(flow.text) || strings.IndexByte(" ,]}", flow.text[flow.position+1]) >= 0)) {
+		flow.position++
+	}
+	value, err := scalar(strings.TrimRight(flow.text[start:flow.position], " "))
+	if err != nil {
+		return nil, flow.errorf("%s", err.Error())
+	}
+	return value, nil
+}
+
+func (flow *flowReader) accept(character byte) bool {
+	if flow.position < len(flow.text) && flow.text[flow.position] == character {
+		flow.position++
+		return true
+	}
+	return false
+}
+
+// separator reads the , between the values of a flow collection, or the end bracket after them.
+func (flow *flowReader) separator(end byte) error {
+	flow.skipSpaces()
+	if flow.accept(',') {
+		return nil
+	}
+	if flow.position < len(flow.text) && flow.text[flow.position] == end {
+		return nil
+	}
+	return flow.errorf("expected , or %c in a flow collection", end)
+}
+
+// writeJSON writes value as compact JSON.
+func writeJSON(buffer *bytes.Buffer, value any) error {
+	switch typed := value.(type) {
+	case mapping:
+		buffer.WriteByte('{')
+		for index, member := range typed {
+			if index > 0 {
+				buffer.WriteByte(',')
+			}
+			if err := writeJSON(buffer, member.key); err != nil {
+				return err
+			}
+			buffer.WriteByte(':')
+			if err := writeJSON(buffer, member.value); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte('}')
+		return nil
+	case []any:
+		buffer.WriteByte('[')
+		for index, item := range typed {
+			if index > 0 {
+				buffer.WriteByte(',')
+			}
+			if err := writeJSON(buffer, item); err != nil {
+				return err
+			}
+		}
+		buffer.WriteByte(']')
+		return nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	buffer.Write(encoded)
+	return nil
+}
+
+// decodeJSON decodes the next JSON value from decoder, keeping the order of objects' keys.
+func decodeJSON(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('{'):
+		result := mapping{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, member{key: key.(string), value: value})
+		}
+		_, err = decoder.Token()
+		return result, err
+	case json.Delim('['):
+		items := []any{}
+		for decoder.More() {
+			item, err := decodeJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		_, err = decoder.Token()
+		return items, err
+	}
+	return token, nil
+}
+
+// writeYAML writes value, which is the value of a key or item at indentation indent, starting on the line of
+// the key or item.
+func writeYAML(buffer *bytes.Buffer, value any, indent int) {
+	switch typed := value.(type) {
+	case mapping:
+		if len(typed) == 0 {
+			buffer.WriteString("{}\n")
+			return
+		}
+		for index, member := range typed {
+			if index > 0 {
+				buffer.WriteString(strings.Repeat(" ", indent))
+			}
+			buffer.WriteString(quote(member.key, false))
+			buffer.WriteByte(':')
+			writeValue(buffer, member.value, indent)
+		}
+	case []any:
+		if isScalars(typed) {
+			buffer.WriteString(flowSequence(typed))
+			buffer.WriteByte('\n')
+			return
+		}
+		for index, item := range typed {
+			if index > 0 {
+				buffer.WriteString(strings.Repeat(" ", indent))
+			}
+			buffer.WriteString("-")
+			if nested, ok := item.([]any); ok && !isScalars(nested) {
+				buffer.WriteString("\n" + strings.Repeat(" ", indent+2))
+			} else {
+				buffer.WriteByte(' ')
+			}
+			writeYAML(buffer, item, indent+2)
+		}
+	default:
+		buffer.WriteString(scalarText(value, false))
+		buffer.WriteByte('\n')
+	}
+}
+
+// writeValue writes value after the key of a mapping at indentation indent.
+func writeValue(buffer *bytes.Buffer, value any, indent int) {
+	switch typed := value.(type) {
+	case mapping:
+		if len(typed) > 0 {
+			buffer.WriteString("\n" + strings.Repeat(" ", indent+2))
+			writeYAML(buffer, value, indent+2)
+			return
+		}
+	case []any:
+		if !isScalars(typed) {
+			buffer.WriteString("\n" + strings.Repeat(" ", indent+2))
+			writeYAML(buffer, value, indent+2)
+			return
+		}
+	}
+	buffer.WriteByte(' ')
+	writeYAML(buffer, value, indent)
+}
+
+// isScalars reports whether items are all scalars, so can be written as a flow sequence.
+func isScalars(items []any) bool {
+	for _, item := range items {
+		switch item.(type) {
+		case mapping, []any:
+			return false
+		}
+	}
+	return true
+}
+
+func flowSequence(items []any) string {
+	var texts []string
+	for _, item := range items {
+		texts = append(texts, scalarText(item, true))
+	}
+	return "[" + strings.Join(texts, ", ") + "]"
+}
+
+func scalarText(value any, inFlow bool) string {
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(typed)
+	case json.Number:
+		return typed.String()
+	case string:
+		return quote(typed, inFlow)
+	}
+	return fmt.Sprint(value)
+}
+
+// quote returns text as a plain scalar if it would be read back as the same string, and a double-quoted
+// one otherwise.
+func quote(text string, inFlow bool) string {
+	plain := text != "" && text == strings.TrimSpace(text) &&
+		strings.IndexByte("-?:,[]{}#&*!|>'\"%@`~", text[0]) < 0 &&
+		!strings.Contains(text, ": ") && !strings.Contains(text, " #") && !strings.HasSuffix(text, ":") &&
+		!(inFlow && strings.ContainsAny(text, ",[]{}"))
+	for _, character := range text {
+		if character < ' ' || character == 0x7f || character == '\ufeff' {
+			plain = false
+		}
+	}
+	if plain {
+		if value, err := scalar(text); err != nil || value != text {
+			plain = false
+		}
+	}
+	if plain {
+		return text
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(text)
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
@@ -0,0 +1,202 @@
+package yaml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestToJSON(t *testing.T) {
+	testCases := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{
+			name: "descriptor",
+			yaml: `# an accounts table
+---
+fields:
+  - name: id   # the key
+    type: integer
+    constraints: {required: true, minimum: 1}
+  - name: size
+    type: string
+    title: 'T-shirt size: S or M'
+    example: "M"
+    constraints:
+      enum: [S, M, "1"]
+  -
+    name: notes
+    type: string
+primaryKey:
+- id
+missingValues: ['', NA]
+`,
+			want: `{"fields":[{"name":"id","type":"integer","constraints":{"required":true,"minimum":1}},{"name":"size","type":"string","title":"T-shirt size: S or M","example":"M","constraints":{"enum":["S","M","1"]}},{"name":"notes","type":"string"}],"primaryKey":["id"],"missingValues":["","NA"]}`,
+		},
+		{
+			name: "scalars",
+			yaml: `
+a: ~
+b: null
+c: True
+d: -012
+e: 0x1F
+f: 1.50
+g: 1e3
+h: 2024-01-31
+i: it's #1
+j: http://example.com/a#b
+k: 'it''s'
+l: "tab\there \u00e9"
+m: yes
+n: a long
+  plain scalar
+"o p": q
+`,
+			want: `{"a":null,"b":null,"c":true,"d":-12,"e":31,"f":1.5,"g":1000,"h":"2024-01-31","i":"it's","j":"http://example.com/a#b","k":"it's","l":"tab\there é","m":"yes","n":"a long plain scalar","o p":"q"}`,
+		},
+		{
+			name: "block scalars",
+			yaml: `literal: |
+  one
+    two
+
+  three
+folded: >
+  one
+  two
+
+  three
+stripped: |-
+  text
+kept: |+
+  text
+
+next: x
+`,
+			want: `{"literal":"one\n  two\n\nthree\n","folded":"one two\nthree\n","stripped":"text","kept":"text\n\n","next":"x"}`,
+		},
+		{
+			name: "nested",
+			yaml: `- - a
+  - b
+- key: [1, {x: y, z: [c, d]}]
+  other:
+- [
+    multi,
+    line,
+  ]
+`,
+			want: `[["a","b"],{"key":[1,{"x":"y","z":["c","d"]}],"other":null},["multi","line"]]`,
+		},
+		{name: "empty", yaml: "# nothing\n", want: "null"},
+		{name: "document end", yaml: "--- [a]\n...\nignored: true\n", want: `["a"]`},
+	}
+
+	for _, testCase := range testCases {
+		got, err := ToJSON([]byte(testCase.yaml))
+		if err != nil {
+			t.Errorf("%s: %s", testCase.name, err.Error())
+			continue
+		}
+		if diff := cmp.Diff(testCase.want, string(got)); diff != "" {
+			t.Errorf("%s (-want +got):\n%s", testCase.name, diff)
+		}
+	}
+}
+
+func TestToJSONErrors(t *testing.T) {
+	testCases := []struct {
+		yaml    string
+		wantErr string
+	}{
+		{yaml: "a: 1\n  b: 2\n", wantErr: "line 2: unexpected indentation"},
+		{yaml: "a: 1\na: 2\n", wantErr: "line 2: the key a is already in the mapping"},
+		{yaml: "a:\n\tb: 1\n", wantErr: "line 2: a line can't be indented with tabs"},
+		{yaml: "a: [1, 2\n", wantErr: "a [ is never closed"},
+		{yaml: "a: 'text\n", wantErr: "line 1: a ' is never closed"},
+		{yaml: "a: &anchor 1\n", wantErr: "anchors, aliases and tags aren't supported"},
+		{yaml: "a: 1\n---\nb: 2\n", wantErr: "line 2: only one document is supported"},
+		{yaml: "a: 1\n- b\n", wantErr: "line 2: expected a key of a mapping, not an item of a sequence"},
+		{yaml: "a: .inf\n", wantErr: ".inf can't be written in JSON"},
+		{yaml: `a: "\q"`, wantErr: `\q isn't an escape sequence`},
+		{yaml: "a: \xff\n", wantErr: "the document isn't UTF-8"},
+	}
+
+	for _, testCase := range testCases {
+		_, err := ToJSON([]byte(testCase.yaml))
+		if err == nil || !strings.Contains(err.Error(), testCase.wantErr) {
+			t.Errorf("%q: expected an error containing %q, got %v", testCase.yaml, testCase.wantErr, err)
+		}
+	}
+}
+
+func TestFromJSON(t *testing.T) {
+	json := `{"fields":[{"name":"id","type":"integer","constraints":{"required":true,"min":1}},{"name":"size","title":"Size: S or M","example":"1","constraints":{"enum":["S","M, L","true",""]}}],"primaryKey":["id"],"uniqueKeys":[["id","size"]],"missingValues":[],"x":{},"description":"two\nlines","nested":[[{"a":null}]]}`
+
+	got, err := FromJSON([]byte(json))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `fields:
+  - name: id
+    type: integer
+    constraints:
+      required: true
+      min: 1
+  - name: size
+    title: "Size: S or M"
+    example: "1"
+    constraints:
+      enum: [S, "M, L", "true", ""]
+primaryKey: [id]
+uniqueKeys:
+  - [id, size]
+missingValues: []
+x: {}
+description: "two\nlines"
+nested:
+  -
+    - a: null
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+
+	// what's written is read back as the same JSON
+	roundTrip, err := ToJSON(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(json, string(roundTrip)); diff != "" {
+		t.Errorf("(-want +got):\n%s", diff)
+	}
+}
+
+func TestRoundTripQuotes(t *testing.T) {
+	// a quote inside a plain scalar doesn't start a quoted one, in a flow collection or not
+	testCases := []string{
+		`{"enum":["it's","x"]}`,
+		`{"enum":["it's, or not","x"]}`,
+		`{"enum":["say \"hi\"","x","'quoted'","\"quoted\""]}`,
+		`{"title":"it's","nested":[["a'b",{"c'd":"e\"f"}]]}`,
+	}
+
+	for _, json := range testCases {
+		written, err := FromJSON([]byte(json))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ToJSON(written)
+		if err != nil {
+			t.Errorf("%s: could not read back\n%s: %s", json, written, err.Error())
+			continue
+		}
+		if diff := cmp.Diff(json, string(got)); diff != "" {
+			t.Errorf("%s (-want +got):\n%s", written, diff)
+		}
+	}
+}